@@ -86,7 +86,7 @@ func RegisterHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 			Query:         entry.AuthConfig.Query,
 		}
 
-		result, err := app.Engine.Register("", db, payload)
+		result, err := app.Engine.Register(database.ADMIN_ROLE, db, payload)
 
 		if err != nil {
 			app.ErrorResponse(res, http.StatusUnauthorized, err.Error())
@@ -84,6 +84,11 @@ func AuthWSMiddleware(app *engine.Router) func(res http.ResponseWriter, req *htt
 			return
 		}
 
+		if len(req.URL.Query().Get("jwt")) == 0 && engine.IsGraphqlTransportRequest(req) {
+			next(req)
+			return
+		}
+
 		enhancedReq, err := app.Engine.AuthenticateForDatabaseDataTriggers(req)
 		if err != nil {
 			app.ErrorResponse(res, http.StatusUnauthorized, err.Error())
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"application/database"
+	"application/engine"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func GetComputedFields(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		computedFields := app.Engine.GetComputedFieldsList()
+		app.Json(res, http.StatusOK, computedFields)
+	}
+}
+
+func CreateComputedField(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		computedFieldInput, err := engine.GetBodyIntoStruct(req, database.ComputedField{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		computedFieldInput, err = app.Engine.ValidateComputedField(db, computedFieldInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.CreateComputedField(db, computedFieldInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusCreated, map[string]string{"message": fmt.Sprintf("Computed field %s for table %s of database %s created", computedFieldInput.Name, computedFieldInput.Table, computedFieldInput.Database)})
+	}
+}
+
+func UpdateComputedField(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		computedFieldInput, err := engine.GetBodyIntoStruct(req, database.ComputedField{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if computedFieldInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide computed field id for this operation")
+			return
+		}
+
+		computedFieldInput, err = app.Engine.ValidateComputedField(db, computedFieldInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.UpdateComputedFieldByID(db, computedFieldInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Computed field %d updated", computedFieldInput.Id)})
+	}
+}
+
+func DeleteComputedField(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		computedFieldInput, err := engine.GetBodyIntoStruct(req, database.ComputedField{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if computedFieldInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide computed field id for this operation")
+			return
+		}
+
+		err = app.Engine.DeleteComputedFieldByID(db, computedFieldInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Computed field %d deleted", computedFieldInput.Id)})
+	}
+}
@@ -11,7 +11,7 @@ import (
 func GetRestHandlers(app *engine.Router, db *sql.DB) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 
-		app.Json(res, http.StatusCreated, app.Engine.RestHandlers)
+		app.Json(res, http.StatusOK, app.Engine.RestHandlers)
 
 	}
 }
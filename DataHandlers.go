@@ -1,6 +1,7 @@
 package main
 
 import (
+	"application/database"
 	engine "application/engine"
 	"database/sql"
 	"net/http"
@@ -16,7 +17,7 @@ func SelectHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 		auth := engine.GetAuth(req)
 		x, err := app.Engine.SelectExec(auth, db, database, body, false)
 		if err != nil {
-			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
 			return
 		}
 		app.Json(res, http.StatusOK, x)
@@ -28,12 +29,13 @@ func InsertHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		body := engine.GetBody(req)
 		params := engine.GetParams(req)
-		database := params["database"]
+		dbName := params["database"]
+		auth := engine.GetAuth(req)
 
-		result, err := app.Engine.InsertExec("", db, database, body)
+		result, err := app.Engine.InsertExec(auth, engine.GetRequestId(req), database.GetClaimsRole(auth), db, dbName, body)
 
 		if err != nil {
-			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
 			return
 		}
 		app.Json(res, http.StatusCreated, result)
@@ -45,10 +47,11 @@ func UpdateHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		body := engine.GetBody(req)
 		params := engine.GetParams(req)
-		database := params["database"]
-		result, err := app.Engine.UpdateExec("", db, database, body)
+		dbName := params["database"]
+		auth := engine.GetAuth(req)
+		result, err := app.Engine.UpdateExec(auth, engine.GetRequestId(req), database.GetClaimsRole(auth), db, dbName, body)
 		if err != nil {
-			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
 			return
 		}
 		app.Json(res, http.StatusOK, result)
@@ -59,12 +62,13 @@ func DeleteHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		body := engine.GetBody(req)
 		params := engine.GetParams(req)
-		database := params["database"]
+		dbName := params["database"]
+		auth := engine.GetAuth(req)
 
-		result, err := app.Engine.DeleteExec("", db, database, body)
+		result, err := app.Engine.DeleteExec(auth, engine.GetRequestId(req), database.GetClaimsRole(auth), db, dbName, body)
 
 		if err != nil {
-			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
 			return
 		}
 		app.Json(res, http.StatusOK, result)
@@ -76,15 +80,33 @@ func ProcessHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		body := engine.GetBody(req)
 		params := engine.GetParams(req)
-		database := params["database"]
+		dbName := params["database"]
+		auth := engine.GetAuth(req)
 
-		result, err := app.Engine.Process("", db, database, body)
+		result, err := app.Engine.Process(auth, engine.GetRequestId(req), database.GetClaimsRole(auth), database.RestDataTrigger, db, dbName, body)
 
 		if err != nil {
-			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
 			return
 		}
 		app.Json(res, http.StatusCreated, result)
 
 	}
 }
+
+func RpcHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		body := engine.GetBody(req)
+		params := engine.GetParams(req)
+		dbName := params["database"]
+		function := params["function"]
+		auth := engine.GetAuth(req)
+
+		result, err := app.Engine.CallFunction(auth, db, dbName, function, body)
+		if err != nil {
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
+			return
+		}
+		app.Json(res, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"application/database"
+	"application/engine"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func GetDataTriggers(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		dataTriggers := app.Engine.GetDataTriggersList()
+		app.Json(res, http.StatusOK, dataTriggers)
+	}
+}
+
+func CreateDataTrigger(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		dataTriggerInput, err := engine.GetBodyIntoStruct(req, database.DataTriggerConfigInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.CreateDataTrigger(db, dataTriggerInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusCreated, map[string]string{"message": fmt.Sprintf("Data trigger for table %s of database %s created", dataTriggerInput.Table, dataTriggerInput.Database)})
+	}
+}
+
+func UpdateDataTrigger(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		dataTriggerInput, err := engine.GetBodyIntoStruct(req, database.DataTriggerConfigInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if dataTriggerInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide data trigger id for this operation")
+			return
+		}
+
+		err = app.Engine.UpdateDataTriggerByID(db, dataTriggerInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Data trigger %d updated", dataTriggerInput.Id)})
+	}
+}
+
+func DeleteDataTrigger(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		dataTriggerInput, err := engine.GetBodyIntoStruct(req, database.DataTriggerConfigInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if dataTriggerInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide data trigger id for this operation")
+			return
+		}
+
+		err = app.Engine.DeleteDataTriggerByID(db, dataTriggerInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Data trigger %d deleted", dataTriggerInput.Id)})
+	}
+}
@@ -102,6 +102,31 @@ func DropDatabase(app *engine.Router, db *sql.DB) http.HandlerFunc {
 		}
 		app.Engine.DeleteRestHandlerByDatabase(db, restHandlerInput)
 
+		webhookInput := database.WebhookInput{
+			Database: dbname,
+		}
+		app.Engine.DeleteWebhooksByDatabase(db, webhookInput)
+
+		dataTriggerInput := database.DataTriggerConfigInput{
+			Database: dbname,
+		}
+		app.Engine.DeleteDataTriggerByDatabase(db, dataTriggerInput)
+
+		computedFieldInput := database.ComputedField{
+			Database: dbname,
+		}
+		app.Engine.DeleteComputedFieldsByDatabase(db, computedFieldInput)
+
+		exposedFunctionInput := database.ExposedFunction{
+			Database: dbname,
+		}
+		app.Engine.DeleteExposedFunctionsByDatabase(db, exposedFunctionInput)
+
+		materializedViewRefreshInput := database.MaterializedViewRefresh{
+			Database: dbname,
+		}
+		app.Engine.DeleteMaterializedViewRefreshesByDatabase(db, materializedViewRefreshInput)
+
 		// RELOAD ENGINE IN MEMORY
 		app.Engine.Reload(db)
 
@@ -14,30 +14,80 @@ func NormalizeEventData(eventData ...any) any {
 }
 
 func WebsocketEventResponse(app *engine.Router, eventData ...any) {
-	for client := range app.Rooms {
-		go func(client *engine.Client) {
-			err := client.Conn.WriteJSON(NormalizeEventData(eventData))
+	input, ok := NormalizeEventData(eventData...).(database.DataTriggerInput)
+	if !ok {
+		return
+	}
+	clients := app.GetClients()
+	targets := make([]database.SubscriptionEventTarget, 0)
+	owners := make([]*engine.Client, 0)
+	for _, client := range clients {
+		for _, subscription := range client.GetSubscriptions() {
+			if !subscription.Matches(input) {
+				continue
+			}
+			targets = append(targets, database.SubscriptionEventTarget{Auth: client.Auth, Subscription: subscription})
+			owners = append(owners, client)
+		}
+	}
+
+	messages := make(map[*engine.Client][]engine.SubscriptionMessage)
+	if len(targets) > 0 {
+		results, err := app.Engine.FilterSubscriptionEvents(app.DB, targets, input)
+		if err != nil {
+			fmt.Println(err)
+		}
+		for i, result := range results {
+			if result.Err != nil {
+				fmt.Println(result.Err)
+				continue
+			}
+			if !result.Ok {
+				continue
+			}
+			messages[owners[i]] = append(messages[owners[i]], engine.SubscriptionMessage{
+				Type: engine.EVENT_MESSAGE,
+				Id:   targets[i].Subscription.Id,
+				Data: result.Event,
+			})
+		}
+	}
+
+	for _, client := range clients {
+		go SendSubscriptionEvents(app, client, input, messages[client])
+	}
+}
+
+func SendSubscriptionEvents(app *engine.Router, client *engine.Client, input database.DataTriggerInput, messages []engine.SubscriptionMessage) {
+	err := app.SendGraphqlSubscriptionEvents(client, input)
+	if err != nil {
+		fmt.Println(err)
+		client.Conn.Close()
+		return
+	}
+	for _, message := range messages {
+		err = engine.SendMessage(client, message)
+		if err != nil {
+			err = client.Conn.Close()
 			if err != nil {
-				err = client.Conn.Close()
-				if err != nil {
-					fmt.Println(err)
-				}
+				fmt.Println(err)
 			}
-		}(client)
+			return
+		}
 	}
 }
 
 func RegisterDatabaseTriggers(app *engine.Router) func() {
-	unsubscribeInsert := app.Engine.EventEmitter.Subscribe(database.INSERT_OPERATION, func(eventData ...any) {
-		go WebsocketEventResponse(app, eventData...)
+	unsubscribeInsert := app.Engine.EventEmitter.SubscribeInternal(database.GetDataTriggerEventName("*", "*", database.INSERT_OPERATION), func(eventData ...any) {
+		WebsocketEventResponse(app, eventData...)
 	})
 
-	unsubscribeUpdate := app.Engine.EventEmitter.Subscribe(database.UPDATE_OPERATION, func(eventData ...any) {
-		go WebsocketEventResponse(app, eventData...)
+	unsubscribeUpdate := app.Engine.EventEmitter.SubscribeInternal(database.GetDataTriggerEventName("*", "*", database.UPDATE_OPERATION), func(eventData ...any) {
+		WebsocketEventResponse(app, eventData...)
 	})
 
-	unsubscribeDelete := app.Engine.EventEmitter.Subscribe(database.DELETE_OPERATION, func(eventData ...any) {
-		go WebsocketEventResponse(app, eventData...)
+	unsubscribeDelete := app.Engine.EventEmitter.SubscribeInternal(database.GetDataTriggerEventName("*", "*", database.DELETE_OPERATION), func(eventData ...any) {
+		WebsocketEventResponse(app, eventData...)
 	})
 
 	return func() {
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"application/database"
 	"application/engine"
 	"database/sql"
+	"fmt"
 	"net/http"
+	"strings"
 )
 
 func GetEngineConfigHandler(app *engine.Router) http.HandlerFunc {
@@ -24,3 +27,35 @@ func ReloadEngine(app *engine.Router, db *sql.DB) http.HandlerFunc {
 		app.Json(res, http.StatusOK, map[string]string{"message": "Engine has been reloaded!"})
 	}
 }
+
+func GetEngineEventsMetrics(app *engine.Router) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		app.Json(res, http.StatusOK, app.Engine.EventEmitter.Metrics())
+	}
+}
+
+func GetEngineOpenAPI(app *engine.Router) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		app.Json(res, http.StatusOK, app.Engine.OpenAPI)
+	}
+}
+
+func GetEngineSDK(app *engine.Router) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		params := engine.GetParams(req)
+		language := params["language"]
+		source, err := app.Engine.GenerateSDK(language, req.URL.Query().Get("package"))
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+		fileName := "client.ts"
+		if strings.EqualFold(language, database.SDK_GO) {
+			fileName = "client.go"
+		}
+		res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+		res.WriteHeader(http.StatusOK)
+		res.Write([]byte(source))
+	}
+}
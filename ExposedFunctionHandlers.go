@@ -0,0 +1,102 @@
+package main
+
+import (
+	"application/database"
+	"application/engine"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func GetExposedFunctions(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		exposedFunctions, err := database.GetEngineExposedFunctions(db)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+		app.Json(res, http.StatusOK, exposedFunctions)
+	}
+}
+
+func CreateExposedFunction(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		exposedFunctionInput, err := engine.GetBodyIntoStruct(req, database.ExposedFunction{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		exposedFunctionInput, err = app.Engine.ValidateExposedFunction(db, exposedFunctionInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.CreateExposedFunction(db, exposedFunctionInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusCreated, map[string]string{"message": fmt.Sprintf("Function %s of database %s exposed", exposedFunctionInput.Name, exposedFunctionInput.Database)})
+	}
+}
+
+func UpdateExposedFunction(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		exposedFunctionInput, err := engine.GetBodyIntoStruct(req, database.ExposedFunction{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if exposedFunctionInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide exposed function id for this operation")
+			return
+		}
+
+		exposedFunctionInput, err = app.Engine.ValidateExposedFunction(db, exposedFunctionInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.UpdateExposedFunctionByID(db, exposedFunctionInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Exposed function %d updated", exposedFunctionInput.Id)})
+	}
+}
+
+func DeleteExposedFunction(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		exposedFunctionInput, err := engine.GetBodyIntoStruct(req, database.ExposedFunction{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if exposedFunctionInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide exposed function id for this operation")
+			return
+		}
+
+		err = app.Engine.DeleteExposedFunctionByID(db, exposedFunctionInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Exposed function %d deleted", exposedFunctionInput.Id)})
+	}
+}
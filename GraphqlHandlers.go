@@ -72,9 +72,9 @@ func GraphqlHandler(app *engine.Router, db *sql.DB) http.HandlerFunc {
 
 		resolveResults["data"] = queryParsedResults
 
-		mutationResults, err := app.Engine.GraphqlMutationResolve(parsedBody, auth, db)
+		mutationResults, err := app.Engine.GraphqlMutationResolve(parsedBody, auth, engine.GetRequestId(req), db)
 		if err != nil {
-			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"application/database"
+	"application/engine"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func RefreshMaterializedView(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		refreshInput, err := engine.GetBodyIntoStruct(req, database.MaterializedViewRefreshInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.RefreshMaterializedView(db, refreshInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Materialized view %s of database %s refreshed", refreshInput.Table, refreshInput.Database)})
+	}
+}
+
+func GetMaterializedViewRefreshes(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		refreshes, err := database.GetMaterializedViewRefreshes(db)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+		app.Json(res, http.StatusOK, refreshes)
+	}
+}
+
+func CreateMaterializedViewRefresh(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		refreshInput, err := engine.GetBodyIntoStruct(req, database.MaterializedViewRefresh{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.CreateMaterializedViewRefresh(db, refreshInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Json(res, http.StatusCreated, map[string]string{"message": fmt.Sprintf("Refresh schedule for materialized view %s of database %s created", refreshInput.Table, refreshInput.Database)})
+	}
+}
+
+func UpdateMaterializedViewRefresh(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		refreshInput, err := engine.GetBodyIntoStruct(req, database.MaterializedViewRefresh{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if refreshInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide refresh schedule id for this operation")
+			return
+		}
+
+		err = app.Engine.UpdateMaterializedViewRefreshByID(db, refreshInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Refresh schedule %d updated", refreshInput.Id)})
+	}
+}
+
+func DeleteMaterializedViewRefresh(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		refreshInput, err := engine.GetBodyIntoStruct(req, database.MaterializedViewRefresh{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if refreshInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide refresh schedule id for this operation")
+			return
+		}
+
+		err = app.Engine.DeleteMaterializedViewRefreshByID(db, refreshInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Refresh schedule %d deleted", refreshInput.Id)})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"application/database"
+	"application/engine"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+func GetRoles(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		roles, err := database.GetEngineRoles(db)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+		app.Json(res, http.StatusOK, roles)
+	}
+}
+
+func CreateRole(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		roleInput, err := engine.GetBodyIntoStruct(req, database.EngineRole{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		roleInput, err = app.Engine.ValidateRolePermissions(roleInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.CreateRole(db, roleInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusCreated, map[string]string{"message": fmt.Sprintf("Role %s created", roleInput.RoleName)})
+	}
+}
+
+func UpdateRolePermissions(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		roleInput, err := engine.GetBodyIntoStruct(req, database.EngineRole{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		roleInput, err = app.Engine.ValidateRolePermissions(roleInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.UpdateRolePermissions(db, roleInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Permissions of role %s updated", roleInput.RoleName)})
+	}
+}
+
+func DeleteRole(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		roleInput, err := engine.GetBodyIntoStruct(req, database.EngineRole{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if len(roleInput.RoleName) == 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide role name for this operation")
+			return
+		}
+
+		err = app.Engine.DeleteRole(db, roleInput)
+		if err != nil {
+			app.ErrorResponseFromError(res, http.StatusInternalServerError, err)
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Role %s deleted", roleInput.RoleName)})
+	}
+}
@@ -9,11 +9,15 @@ var AliveRoute string = "/alive"
 //ENGINE ROUTES
 var EngineRoute string = "/engine"
 var EngineReloadRoute string = "/engine/reload"
+var EngineEventsMetricsRoute string = "/engine/events/metrics"
+var EngineOpenAPIRoute string = "/engine/openapi.json"
+var EngineSDKRoute string = "/engine/sdk/<str:language>"
 
 // DATA ROUTES
 var QueryRoute string = "/<str:database>"
 var StatementRoute string = "/<str:database>/actions"
 var ProcessMultipleStatementsRoute string = "/<str:database>/process"
+var RpcRoute string = "/<str:database>/rpc/<str:function>"
 
 // AUTH ROUTES
 var RefreshTokenRoute string = "/auth"
@@ -44,6 +48,28 @@ var IndexesRoute string = "/engine/databases/<str:database>/tables/<str:table>/i
 // CUSTOM REST HANDLERS ROUTES
 var CustomRestHandlersRoute string = "/engine/rest-handlers"
 
+// WEBHOOKS ROUTES
+var WebhooksRoute string = "/engine/webhooks"
+var WebhookEnableRoute string = "/engine/webhooks/enable"
+var WebhookDeliveriesRoute string = "/engine/webhooks/deliveries"
+var WebhookDeliveriesReplayRoute string = "/engine/webhooks/deliveries/replay"
+
+// DATA TRIGGERS ROUTES
+var DataTriggersRoute string = "/engine/data-triggers"
+
+// COMPUTED FIELDS ROUTES
+var ComputedFieldsRoute string = "/engine/computed-fields"
+
+// EXPOSED FUNCTIONS ROUTES
+var ExposedFunctionsRoute string = "/engine/functions"
+
+// ROLES ROUTES
+var RolesRoute string = "/engine/roles"
+
+// MATERIALIZED VIEWS ROUTES
+var MaterializedViewRefreshRoute string = "/engine/materialized-views/refresh"
+var MaterializedViewSchedulesRoute string = "/engine/materialized-views/schedules"
+
 // GRAPHQL ROUTES
 var GraphQLRoute string = environment.GetEnvValueToStringWithDefault("GRAPHQL_ENDPOINT", "/graphql")
 var GraphiQLRoute string = environment.GetEnvValueToStringWithDefault("GRAPHIQL_ENDPOINT", GraphQLRoute)
@@ -115,6 +115,30 @@ func DropTable(app *engine.Router, db *sql.DB) http.HandlerFunc {
 		}
 		database.DeleteRelationsByDatabaseTable(db, relationInput)
 
+		webhookInput := database.WebhookInput{
+			Database: dbname,
+			Table:    tblname,
+		}
+		app.Engine.DeleteWebhooksByDatabaseTable(db, webhookInput)
+
+		dataTriggerInput := database.DataTriggerConfigInput{
+			Database: dbname,
+			Table:    tblname,
+		}
+		app.Engine.DeleteDataTriggerByDatabaseTable(db, dataTriggerInput)
+
+		computedFieldInput := database.ComputedField{
+			Database: dbname,
+			Table:    tblname,
+		}
+		app.Engine.DeleteComputedFieldsByDatabaseTable(db, computedFieldInput)
+
+		materializedViewRefreshInput := database.MaterializedViewRefresh{
+			Database: dbname,
+			Table:    tblname,
+		}
+		app.Engine.DeleteMaterializedViewRefreshesByDatabaseTable(db, materializedViewRefreshInput)
+
 		// RELOAD ENGINE IN MEMORY
 		app.Engine.Reload(db)
 		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Table %s for Database %s was successfully deleted", tblname, dbname)})
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"application/database"
+	"application/engine"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+func GetWebhooks(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webhooks := app.Engine.GetWebhooksList()
+		app.Json(res, http.StatusOK, webhooks)
+	}
+}
+
+func CreateWebhook(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webhookInput, err := engine.GetBodyIntoStruct(req, database.WebhookInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.CreateWebhook(db, webhookInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusCreated, map[string]string{"message": fmt.Sprintf("Webhook %s for table %s of database %s created", webhookInput.Endpoint, webhookInput.Table, webhookInput.Database)})
+	}
+}
+
+func UpdateWebhook(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webhookInput, err := engine.GetBodyIntoStruct(req, database.WebhookInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if webhookInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide webhook id for this operation")
+			return
+		}
+
+		err = app.Engine.UpdateWebhookByID(db, webhookInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Webhook %d updated", webhookInput.Id)})
+	}
+}
+
+func DeleteWebhook(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webhookInput, err := engine.GetBodyIntoStruct(req, database.WebhookInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if webhookInput.Id <= 0 {
+			app.ErrorResponse(res, http.StatusBadRequest, "Please provide webhook id for this operation")
+			return
+		}
+
+		err = app.Engine.DeleteWebhookByID(db, webhookInput)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Webhook %d deleted", webhookInput.Id)})
+	}
+}
+
+func EnableWebhook(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webhookInput, err := engine.GetBodyIntoStruct(req, database.WebhookInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.SetWebhookEnabledByID(db, webhookInput, true)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Webhook %d: Enabled", webhookInput.Id)})
+	}
+}
+
+func DisableWebhook(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webhookInput, err := engine.GetBodyIntoStruct(req, database.WebhookInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		err = app.Engine.SetWebhookEnabledByID(db, webhookInput, false)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Engine.Reload(db)
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("Webhook %d: Disabled", webhookInput.Id)})
+	}
+}
+
+func GetWebhookDeliveries(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil {
+			limit = 0
+		}
+		input := database.WebhookDeliveriesInput{
+			Status: query.Get("status"),
+			Limit:  limit,
+		}
+
+		deliveries, err := app.Engine.GetWebhookDeliveries(db, input)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Json(res, http.StatusOK, deliveries)
+	}
+}
+
+func ReplayWebhookDeliveries(app *engine.Router, db *sql.DB) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		input, err := engine.GetBodyIntoStruct(req, database.WebhookDeliveriesInput{})
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		replayed, err := app.Engine.ReplayWebhookDeliveries(db, input)
+		if err != nil {
+			app.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		app.Json(res, http.StatusOK, map[string]string{"message": fmt.Sprintf("%d webhook deliveries scheduled for replay", replayed)})
+	}
+}
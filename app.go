@@ -15,6 +15,12 @@ func main() {
 	app.Get(EngineRoute, GetEngineConfigHandler(app))
 	app.Use(EngineReloadRoute, AuthMainMiddleware(app))
 	app.Get(EngineReloadRoute, ReloadEngine(app, db))
+	app.Use(EngineEventsMetricsRoute, AuthMainMiddleware(app))
+	app.Get(EngineEventsMetricsRoute, GetEngineEventsMetrics(app))
+	app.Use(EngineOpenAPIRoute, AuthMainMiddleware(app))
+	app.Get(EngineOpenAPIRoute, GetEngineOpenAPI(app))
+	app.Use(EngineSDKRoute, AuthMainMiddleware(app))
+	app.Get(EngineSDKRoute, GetEngineSDK(app))
 
 	// DATA ROUTES
 	app.Use(QueryRoute, AuthDBMiddleware(app))
@@ -25,6 +31,8 @@ func main() {
 	app.Post(StatementRoute, InsertHandler(app, db))
 	app.Put(StatementRoute, UpdateHandler(app, db))
 	app.Delete(StatementRoute, DeleteHandler(app, db))
+	app.Use(RpcRoute, AuthDBMiddleware(app))
+	app.Post(RpcRoute, RpcHandler(app, db))
 
 	// AUTH ROUTES
 	app.Use(RefreshTokenRoute, AuthMainMiddleware(app))
@@ -69,6 +77,64 @@ func main() {
 	app.Post(IndexesRoute, CreateIndex(app, db))
 	app.Delete(IndexesRoute, DropIndex(app, db))
 
+	// CUSTOM REST HANDLERS ROUTES
+	app.Use(CustomRestHandlersRoute, AuthMainMiddleware(app))
+	app.Get(CustomRestHandlersRoute, GetRestHandlers(app, db))
+	app.Post(CustomRestHandlersRoute, CreateRestHandler(app, db))
+	app.Put(CustomRestHandlersRoute, UpdateRestHandler(app, db))
+	app.Delete(CustomRestHandlersRoute, DeleteRestHandler(app, db))
+
+	// WEBHOOKS ROUTES
+	app.Use(WebhooksRoute, AuthMainMiddleware(app))
+	app.Get(WebhooksRoute, GetWebhooks(app, db))
+	app.Post(WebhooksRoute, CreateWebhook(app, db))
+	app.Put(WebhooksRoute, UpdateWebhook(app, db))
+	app.Delete(WebhooksRoute, DeleteWebhook(app, db))
+	app.Use(WebhookEnableRoute, AuthMainMiddleware(app))
+	app.Put(WebhookEnableRoute, EnableWebhook(app, db))
+	app.Delete(WebhookEnableRoute, DisableWebhook(app, db))
+	app.Use(WebhookDeliveriesRoute, AuthMainMiddleware(app))
+	app.Get(WebhookDeliveriesRoute, GetWebhookDeliveries(app, db))
+	app.Use(WebhookDeliveriesReplayRoute, AuthMainMiddleware(app))
+	app.Post(WebhookDeliveriesReplayRoute, ReplayWebhookDeliveries(app, db))
+
+	// DATA TRIGGERS ROUTES
+	app.Use(DataTriggersRoute, AuthMainMiddleware(app))
+	app.Get(DataTriggersRoute, GetDataTriggers(app, db))
+	app.Post(DataTriggersRoute, CreateDataTrigger(app, db))
+	app.Put(DataTriggersRoute, UpdateDataTrigger(app, db))
+	app.Delete(DataTriggersRoute, DeleteDataTrigger(app, db))
+
+	// COMPUTED FIELDS ROUTES
+	app.Use(ComputedFieldsRoute, AuthMainMiddleware(app))
+	app.Get(ComputedFieldsRoute, GetComputedFields(app, db))
+	app.Post(ComputedFieldsRoute, CreateComputedField(app, db))
+	app.Put(ComputedFieldsRoute, UpdateComputedField(app, db))
+	app.Delete(ComputedFieldsRoute, DeleteComputedField(app, db))
+
+	// EXPOSED FUNCTIONS ROUTES
+	app.Use(ExposedFunctionsRoute, AuthMainMiddleware(app))
+	app.Get(ExposedFunctionsRoute, GetExposedFunctions(app, db))
+	app.Post(ExposedFunctionsRoute, CreateExposedFunction(app, db))
+	app.Put(ExposedFunctionsRoute, UpdateExposedFunction(app, db))
+	app.Delete(ExposedFunctionsRoute, DeleteExposedFunction(app, db))
+
+	// ROLES ROUTES
+	app.Use(RolesRoute, AuthMainMiddleware(app))
+	app.Get(RolesRoute, GetRoles(app, db))
+	app.Post(RolesRoute, CreateRole(app, db))
+	app.Put(RolesRoute, UpdateRolePermissions(app, db))
+	app.Delete(RolesRoute, DeleteRole(app, db))
+
+	// MATERIALIZED VIEWS ROUTES
+	app.Use(MaterializedViewRefreshRoute, AuthMainMiddleware(app))
+	app.Post(MaterializedViewRefreshRoute, RefreshMaterializedView(app, db))
+	app.Use(MaterializedViewSchedulesRoute, AuthMainMiddleware(app))
+	app.Get(MaterializedViewSchedulesRoute, GetMaterializedViewRefreshes(app, db))
+	app.Post(MaterializedViewSchedulesRoute, CreateMaterializedViewRefresh(app, db))
+	app.Put(MaterializedViewSchedulesRoute, UpdateMaterializedViewRefresh(app, db))
+	app.Delete(MaterializedViewSchedulesRoute, DeleteMaterializedViewRefresh(app, db))
+
 	// GRAPHQL ROUTES
 	app.Use(GraphiQLRoute, AuthMainMiddleware(app))
 	app.Get(GraphiQLRoute, GraphqlIntrospection(app, db))
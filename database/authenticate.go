@@ -149,6 +149,15 @@ func (e *Engine) AuthenticateForDatabaseDataTriggers(req *http.Request) (*http.R
 	if len(tokenString) < 1 {
 		return nil, fmt.Errorf("no token was provided")
 	}
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(context.WithValue(req.Context(), "auth", claims))
+	return req, nil
+}
+
+func ParseToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -161,11 +170,35 @@ func (e *Engine) AuthenticateForDatabaseDataTriggers(req *http.Request) (*http.R
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		req = req.WithContext(context.WithValue(req.Context(), "auth", claims))
-		return req, nil
+		return claims, nil
 	}
 	return nil, fmt.Errorf("unauthorized")
 }
+
+var CONNECTION_INIT_TOKEN_KEYS []string = []string{"Authorization", "authorization", "token", "jwt"}
+
+func GetConnectionInitToken(payload json.RawMessage) string {
+	var parsedPayload map[string]interface{}
+	err := json.Unmarshal(payload, &parsedPayload)
+	if err != nil {
+		return ""
+	}
+	sources := []map[string]interface{}{parsedPayload}
+	if headers, err := IsMapToInterface(parsedPayload["headers"]); err == nil {
+		sources = append(sources, headers)
+	}
+	for _, source := range sources {
+		for _, key := range CONNECTION_INIT_TOKEN_KEYS {
+			value, ok := source[key].(string)
+			if !ok || len(value) == 0 {
+				continue
+			}
+			parts := strings.Split(strings.Trim(value, " "), " ")
+			return parts[len(parts)-1]
+		}
+	}
+	return ""
+}
 func (e *Engine) Login(role string, db *sql.DB, payload AuthActionPayload) (string, error) {
 
 	identityValue, ok := payload.Body[payload.IdentityField]
@@ -337,7 +370,7 @@ func (e *Engine) Register(role string, db *sql.DB, payload AuthActionPayload) (i
 		"objects": objects,
 	}
 
-	result, err := e.InsertExec(role, db, payload.Database, body)
+	result, err := e.InsertExec(nil, "", role, db, payload.Database, body)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestGetConnectionInitToken(t *testing.T) {
+	cases := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{name: "bearer authorization", payload: `{"Authorization":"Bearer abc"}`, want: "abc"},
+		{name: "lowercase authorization", payload: `{"authorization":"abc"}`, want: "abc"},
+		{name: "token", payload: `{"token":"abc"}`, want: "abc"},
+		{name: "jwt", payload: `{"jwt":"abc"}`, want: "abc"},
+		{name: "headers", payload: `{"headers":{"Authorization":"Bearer abc"}}`, want: "abc"},
+		{name: "top level wins over headers", payload: `{"token":"abc","headers":{"Authorization":"Bearer def"}}`, want: "abc"},
+		{name: "empty payload", payload: ``, want: ""},
+		{name: "without token", payload: `{"locale":"en"}`, want: ""},
+		{name: "not a string", payload: `{"token":42}`, want: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := GetConnectionInitToken(json.RawMessage(c.payload)); got != c.want {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "42"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	claims, err := ParseToken(tokenString)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if claims["sub"] != "42" {
+		t.Errorf("expected sub 42, got %v", claims["sub"])
+	}
+
+	otherToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "42"}).SignedString([]byte("other"))
+	if _, err := ParseToken(otherToken); err == nil {
+		t.Errorf("expected a token signed with another secret to be rejected")
+	}
+}
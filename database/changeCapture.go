@@ -0,0 +1,211 @@
+package database
+
+import (
+	"application/environment"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var DatabaseDataTrigger string = "DATABASE"
+
+type DataChangeNotification struct {
+	Database  string `json:"database"`
+	Table     string `json:"table"`
+	Operation string `json:"operation"`
+	Old       any    `json:"old"`
+	New       any    `json:"new"`
+	Truncated bool   `json:"truncated"`
+}
+
+func IsDirectWritesCaptureEnabled() bool {
+	return environment.GetEnvValue("DATA_TRIGGER_CAPTURE_DIRECT_WRITES") == "ON"
+}
+
+// SetTransactionEngineOrigin marks writes the engine emits to data triggers itself, so
+// change capture skips them. Rest handlers and exposed functions don't set it because
+// the engine can't tell which tables they write to; their changes are only reported
+// through change capture (DATA_TRIGGER_CAPTURE_DIRECT_WRITES=ON).
+func SetTransactionEngineOrigin(ctx context.Context, tx *sql.Tx, requestId string) error {
+	if len(requestId) == 0 {
+		requestId = "engine"
+	}
+	_, err := tx.ExecContext(ctx, SET_TRANSACTION_ENGINE_ORIGIN, requestId)
+	return err
+}
+
+func GetInstalledDataChangeTriggers(db *sql.DB) (map[string]map[string]bool, error) {
+	installed := make(map[string]map[string]bool)
+	scanner := Query(db, GET_DATA_CHANGE_TRIGGERS)
+	callback := func(rows *sql.Rows) error {
+		var database, table string
+		err := rows.Scan(&database, &table)
+		if err != nil {
+			return err
+		}
+		if _, ok := installed[database]; !ok {
+			installed[database] = make(map[string]bool)
+		}
+		installed[database][table] = true
+		return nil
+	}
+	err := scanner(callback)
+	return installed, err
+}
+
+func (engine *Engine) SyncDataChangeTriggers(db *sql.DB) error {
+	installed, err := GetInstalledDataChangeTriggers(db)
+	if err != nil {
+		return err
+	}
+
+	if IsDirectWritesCaptureEnabled() {
+		_, err = db.Exec(CREATE_DATA_CHANGE_NOTIFY_FUNCTION)
+		if err != nil {
+			return err
+		}
+
+		for database, tables := range engine.DataTriggers {
+			for table := range tables {
+				if _, err := engine.GetWritableModelByKey(database, table); err != nil {
+					continue
+				}
+				if installed[database][table] {
+					delete(installed[database], table)
+					continue
+				}
+				query := fmt.Sprintf(CREATE_DATA_CHANGE_TRIGGER, database, table)
+				_, err := db.Exec(query)
+				LogSql(query)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for database, tables := range installed {
+		for table := range tables {
+			query := fmt.Sprintf(DROP_DATA_CHANGE_TRIGGER, database, table)
+			_, err := db.Exec(query)
+			LogSql(query)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func AcquireDataChangeListenerLock(db *sql.DB) (*sql.Conn, bool) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return nil, false
+	}
+	locked := false
+	err = conn.QueryRowContext(ctx, TRY_DATA_CHANGE_LISTENER_LOCK, DATA_CHANGE_CHANNEL).Scan(&locked)
+	if err != nil {
+		fmt.Println(err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false
+	}
+	return conn, true
+}
+
+func ReleaseDataChangeListenerLock(conn *sql.Conn) {
+	conn.ExecContext(context.Background(), RELEASE_DATA_CHANGE_LISTENER_LOCK, DATA_CHANGE_CHANNEL)
+	conn.Close()
+}
+
+func (engine *Engine) StartDataChangeListener(db *sql.DB) {
+	if !IsDirectWritesCaptureEnabled() {
+		return
+	}
+
+	interval := time.Duration(environment.GetEnvValueToIntWithDefault("DATA_CHANGE_LISTENER_LOCK_INTERVAL_IN_SECONDS", 30)) * time.Second
+	go func() {
+		for {
+			if lock, ok := AcquireDataChangeListenerLock(db); ok {
+				engine.ListenDataChanges(lock, interval)
+				ReleaseDataChangeListenerLock(lock)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func (engine *Engine) ListenDataChanges(lock *sql.Conn, interval time.Duration) {
+	connStr := environment.GetEnvValue("CONNECTION_STRING")
+	listener := pq.NewListener(connStr, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+	defer listener.Close()
+
+	err := listener.Listen(DATA_CHANGE_CHANNEL)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case notification := <-listener.Notify:
+			if notification == nil {
+				continue
+			}
+			engine.HandleDataChangeNotification(notification.Extra)
+		case <-ticker.C:
+			err := lock.PingContext(context.Background())
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			go listener.Ping()
+		}
+	}
+}
+
+func (engine *Engine) HandleDataChangeNotification(payload string) {
+	var notification DataChangeNotification
+	err := json.Unmarshal([]byte(payload), &notification)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	input := DataTriggerInput{
+		Database:  notification.Database,
+		Table:     notification.Table,
+		Operation: notification.Operation,
+		Type:      DatabaseDataTrigger,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+
+	if notification.Old != nil {
+		input.Old = []any{notification.Old}
+	}
+	if notification.New != nil {
+		input.New = []any{notification.New}
+	}
+
+	switch notification.Operation {
+	case DELETE_OPERATION:
+		input.Payload = input.Old
+	default:
+		input.Payload = input.New
+	}
+
+	engine.ExecuteDataTrigger(input)
+}
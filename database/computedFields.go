@@ -0,0 +1,242 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var COMPUTED_FIELD_ARGS_KEY string = "args"
+
+var COMPUTED_FIELD_VOLATILITIES = map[string]bool{
+	"s": true,
+	"i": true,
+}
+
+type ComputedField struct {
+	Id             int64  `json:"id"`
+	Name           string `json:"name"`
+	Database       string `json:"database"`
+	Table          string `json:"table"`
+	FunctionSchema string `json:"function_schema"`
+	Function       string `json:"function"`
+	ReturnType     string `json:"return_type"`
+}
+
+type ComputedFieldsMap map[string]ComputedField
+
+func GetEngineComputedFields(db *sql.DB) ([]ComputedField, error) {
+	computedFields := make([]ComputedField, 0)
+	scanner := Query(db, GET_ENGINE_COMPUTED_FIELDS)
+	cb := func(rows *sql.Rows) error {
+		var computedField ComputedField
+		err := rows.Scan(&computedField.Id, &computedField.Name, &computedField.Database, &computedField.Table, &computedField.FunctionSchema, &computedField.Function, &computedField.ReturnType)
+		if err != nil {
+			return err
+		}
+		computedFields = append(computedFields, computedField)
+		return nil
+	}
+	err := scanner(cb)
+	return computedFields, err
+}
+
+func (engine *Engine) GetComputedFieldsList() []ComputedField {
+	computedFields := make([]ComputedField, 0)
+	for _, model := range engine.Models {
+		for _, computedField := range model.ComputedFields {
+			computedFields = append(computedFields, computedField)
+		}
+	}
+	sort.Slice(computedFields, func(i, j int) bool {
+		return computedFields[i].Id < computedFields[j].Id
+	})
+	return computedFields
+}
+
+func (engine *Engine) ValidateComputedField(db *sql.DB, input ComputedField) (ComputedField, error) {
+	if !GRAPHQL_NAME_PATTERN.MatchString(input.Name) {
+		return input, fmt.Errorf("computed field name %s should contain only letters numbers and underscores", input.Name)
+	}
+	model, ok := engine.DatabaseToTableToModelMap[input.Database][input.Table]
+	if !ok {
+		return input, fmt.Errorf("table %s doesn't exist for database %s", input.Table, input.Database)
+	}
+	if model.isModelColumn(input.Name) || model.isRelationColumn(input.Name) {
+		return input, fmt.Errorf("%s is already a column or relation of table %s", input.Name, input.Table)
+	}
+	if existing, ok := model.ComputedFields[input.Name]; ok && existing.Id != input.Id {
+		return input, fmt.Errorf("computed field %s already exists for table %s", input.Name, input.Table)
+	}
+	if len(input.FunctionSchema) == 0 {
+		input.FunctionSchema = input.Database
+	}
+	if !GRAPHQL_NAME_PATTERN.MatchString(input.FunctionSchema) || !GRAPHQL_NAME_PATTERN.MatchString(input.Function) {
+		return input, fmt.Errorf("please provide a valid function")
+	}
+
+	var returnsSet bool
+	var volatility string
+	err := db.QueryRow(GET_COMPUTED_FIELD_FUNCTION, input.FunctionSchema, input.Function, input.Database, input.Table).Scan(&input.ReturnType, &returnsSet, &volatility)
+	if err == sql.ErrNoRows {
+		return input, fmt.Errorf("function %s.%s accepting a %s.%s row doesn't exist", input.FunctionSchema, input.Function, input.Database, input.Table)
+	}
+	if err != nil {
+		return input, err
+	}
+	if returnsSet {
+		return input, fmt.Errorf("function %s.%s should return a single value", input.FunctionSchema, input.Function)
+	}
+	if !COMPUTED_FIELD_VOLATILITIES[volatility] {
+		return input, fmt.Errorf("function %s.%s should be STABLE or IMMUTABLE", input.FunctionSchema, input.Function)
+	}
+	return input, nil
+}
+
+func (engine *Engine) CreateComputedField(db *sql.DB, input ComputedField) error {
+	_, err := db.Exec(CREATE_COMPUTED_FIELD, input.Name, input.Database, input.Table, input.FunctionSchema, input.Function, input.ReturnType)
+	return err
+}
+
+func (engine *Engine) UpdateComputedFieldByID(db *sql.DB, input ComputedField) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("computed field id was not provided")
+	}
+	result, err := db.Exec(UPDATE_COMPUTED_FIELD_BY_ID, input.Name, input.Database, input.Table, input.FunctionSchema, input.Function, input.ReturnType, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("computed field %d doesn't exist", input.Id))
+}
+
+func (engine *Engine) DeleteComputedFieldByID(db *sql.DB, input ComputedField) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("computed field id was not provided")
+	}
+	result, err := db.Exec(DELETE_COMPUTED_FIELD_BY_ID, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("computed field %d doesn't exist", input.Id))
+}
+
+func (engine *Engine) DeleteComputedFieldsByDatabase(db *sql.DB, input ComputedField) error {
+	_, err := db.Exec(DELETE_COMPUTED_FIELDS_BY_DATABASE_NAME, input.Database)
+	return err
+}
+
+func (engine *Engine) DeleteComputedFieldsByDatabaseTable(db *sql.DB, input ComputedField) error {
+	_, err := db.Exec(DELETE_COMPUTED_FIELDS_BY_DATABASE_TABLE_NAME, input.Database, input.Table)
+	return err
+}
+
+func (computedField ComputedField) Column() Column {
+	return Column{Name: computedField.Name, Type: computedField.ReturnType, Nullable: true}
+}
+
+func GetSortedComputedFields(model *Model) []ComputedField {
+	computedFields := make([]ComputedField, 0, len(model.ComputedFields))
+	for _, computedField := range model.ComputedFields {
+		computedFields = append(computedFields, computedField)
+	}
+	sort.Slice(computedFields, func(i, j int) bool {
+		return computedFields[i].Name < computedFields[j].Name
+	})
+	return computedFields
+}
+
+func (model *Model) isComputedField(key string) bool {
+	_, ok := model.ComputedFields[key]
+	return ok
+}
+
+func (model *Model) BuildComputedFieldExpression(key string, value interface{}, alias string, idx *int) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	computedField := model.ComputedFields[key]
+	parts := []string{alias}
+	if parsedValue, err := IsMapToInterface(value); err == nil {
+		if functionArgs, err := IsMapToInterface(parsedValue[COMPUTED_FIELD_ARGS_KEY]); err == nil {
+			names := make([]string, 0, len(functionArgs))
+			for name := range functionArgs {
+				if GRAPHQL_NAME_PATTERN.MatchString(name) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				parts = append(parts, fmt.Sprintf("%s => $%d", name, *idx))
+				args = append(args, GetComputedFieldArgValue(functionArgs[name]))
+				*idx += 1
+			}
+		}
+	}
+	return computedField.Call(parts...), args
+}
+
+func GetComputedFieldArgValue(value interface{}) interface{} {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		jsonValue, err := json.Marshal(value)
+		if err == nil {
+			return string(jsonValue)
+		}
+	}
+	return value
+}
+
+func (computedField ComputedField) Call(arguments ...string) string {
+	return fmt.Sprintf("%s.%s(%s)", computedField.FunctionSchema, computedField.Function, strings.Join(arguments, ", "))
+}
+
+func WithoutComputedFieldArgs(value interface{}) interface{} {
+	parsedValue, err := IsMapToInterface(value)
+	if err != nil {
+		return value
+	}
+	if _, ok := parsedValue[COMPUTED_FIELD_ARGS_KEY]; !ok {
+		return value
+	}
+	comparison := make(map[string]interface{})
+	for key, entry := range parsedValue {
+		if key != COMPUTED_FIELD_ARGS_KEY {
+			comparison[key] = entry
+		}
+	}
+	return comparison
+}
+
+func (model *Model) GetSelectedComputedFields(body interface{}) map[string]interface{} {
+	selected := make(map[string]interface{})
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return selected
+	}
+	_select, _ := IsMapToInterface(parsedBody["_select"])
+	for name := range model.ComputedFields {
+		if value, ok := _select[name]; ok {
+			selected[name] = value
+		} else if value, ok := parsedBody[name]; ok {
+			selected[name] = value
+		}
+	}
+	return selected
+}
+
+func (model *Model) BuildComputedFieldColumns(body interface{}, alias string, idx *int) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	selected := model.GetSelectedComputedFields(body)
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	columns := ""
+	for _, name := range names {
+		expression, expressionArgs := model.BuildComputedFieldExpression(name, selected[name], alias, idx)
+		columns += fmt.Sprintf(", %s AS %s", expression, name)
+		args = append(args, expressionArgs...)
+	}
+	return columns, args
+}
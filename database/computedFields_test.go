@@ -0,0 +1,90 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func NewComputedFieldsTestModel() *Model {
+	_, posts := NewQueryBuilderTestModels()
+	posts.ComputedFields = ComputedFieldsMap{
+		"score": {Name: "score", Database: "public", Table: "posts", FunctionSchema: "public", Function: "post_score", ReturnType: "numeric"},
+	}
+	return posts
+}
+
+func TestBuildComputedFieldExpression(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    any
+		want     string
+		wantArgs []interface{}
+	}{
+		{name: "without args", value: true, want: "public.post_score(_0_posts)", wantArgs: []interface{}{}},
+		{
+			name:     "scalar args are sorted by name",
+			value:    map[string]any{"args": map[string]any{"weight": 2.0, "bonus": "x"}},
+			want:     "public.post_score(_0_posts, bonus => $4, weight => $5)",
+			wantArgs: []interface{}{"x", 2.0},
+		},
+		{
+			name:     "object and array args are sent as json",
+			value:    map[string]any{"args": map[string]any{"options": map[string]any{"decay": 0.5}, "tags": []any{"a", "b"}}},
+			want:     "public.post_score(_0_posts, options => $4, tags => $5)",
+			wantArgs: []interface{}{`{"decay":0.5}`, `["a","b"]`},
+		},
+		{
+			name:     "invalid arg names are skipped",
+			value:    map[string]any{"args": map[string]any{"weight) OR true --": 1.0, "weight": 1.0}},
+			want:     "public.post_score(_0_posts, weight => $4)",
+			wantArgs: []interface{}{1.0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			model := NewComputedFieldsTestModel()
+			idx := 4
+			expression, args := model.BuildComputedFieldExpression("score", c.value, "_0_posts", &idx)
+			if expression != c.want {
+				t.Errorf("expected %q, got %q", c.want, expression)
+			}
+			if !reflect.DeepEqual(args, c.wantArgs) {
+				t.Errorf("expected args %#v, got %#v", c.wantArgs, args)
+			}
+			if idx != 4+len(c.wantArgs) {
+				t.Errorf("expected next index %d, got %d", 4+len(c.wantArgs), idx)
+			}
+		})
+	}
+}
+
+func TestSelectComputedField(t *testing.T) {
+	model := NewComputedFieldsTestModel()
+	body := map[string]any{
+		"_select":  map[string]any{"id": true, "score": map[string]any{"args": map[string]any{"tags": []any{"go"}}}},
+		"_where":   map[string]any{"score": map[string]any{"_gt": 1.0}},
+		"_orderBy": map[string]any{"score": "DESC"},
+	}
+	idx := 1
+	query, args, err := model.Select(jwt.MapClaims{"bypass_all": true}, body, 0, &idx, nil, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, part := range []string{
+		"(SELECT _0_posts.id,_0_posts.score )",
+		"public.post_score(_0_posts, tags => $2) AS score",
+		"WHERE   public.post_score(_0_posts)  > $1",
+		"ORDER BY public.post_score(_0_posts) DESC",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("expected %q in %q", part, query)
+		}
+	}
+	wantArgs := []interface{}{1.0, `["go"]`}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("expected args %v, got %v", wantArgs, args)
+	}
+}
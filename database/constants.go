@@ -8,8 +8,16 @@ var SELECT_BODY_KEYS map[string]bool = map[string]bool{
 	"_distinct": true,
 	"_offset":   true,
 	"_limit":    true,
+	"_after":    true,
+	"_before":   true,
+	"_total":    true,
 }
 
+var TOTAL_KEY string = "_total"
+var PAGINATED_SUFFIX string = "_paginated"
+var PAGINATED_ROWS_KEY string = "rows"
+var PAGINATED_TOTAL_KEY string = "total"
+
 var WHERE_CLAUSE_KEYS map[string]string = map[string]string{
 	"_and":            "AND",
 	"_or":             "OR",
@@ -33,7 +41,7 @@ var WHERE_CLAUSE_KEYS map[string]string = map[string]string{
 	"_key_exists":     "?",
 	"_key_exists_any": "?|",
 	"_key_exists_all": "?&",
-	"_text_search":    "tsquery",
+	"_text_search":    "@@",
 }
 
 var QUERY_BINDER_KEYS map[string]bool = map[string]bool{
@@ -0,0 +1,295 @@
+package database
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var CURSOR_KEY string = "_cursor"
+var CURSOR_AFTER_KEY string = "_after"
+var CURSOR_BEFORE_KEY string = "_before"
+
+type CursorOrderColumn struct {
+	Name       string
+	Descending bool
+	NullsFirst bool
+}
+
+type CursorPagination struct {
+	Enabled   bool
+	Reverse   bool
+	Column    string
+	Condition string
+	Args      []any
+	OrderBy   string
+	Columns   []CursorOrderColumn
+}
+
+func IsCursorPagination(body any) bool {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return false
+	}
+	if _, ok := parsedBody[CURSOR_AFTER_KEY]; ok {
+		return true
+	}
+	if _, ok := parsedBody[CURSOR_BEFORE_KEY]; ok {
+		return true
+	}
+	_select, err := IsMapToInterface(parsedBody["_select"])
+	if err != nil {
+		return false
+	}
+	_, ok := _select[CURSOR_KEY]
+	return ok
+}
+
+func GetCursorValue(body any, key string) string {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return ""
+	}
+	cursor, ok := parsedBody[key].(string)
+	if !ok {
+		return ""
+	}
+	return cursor
+}
+
+func NewCursorOrderColumn(name string, direction string) CursorOrderColumn {
+	descending := strings.HasPrefix(direction, "DESC")
+	nullsFirst := descending
+	if strings.HasSuffix(direction, "_NULLS_FIRST") {
+		nullsFirst = true
+	}
+	if strings.HasSuffix(direction, "_NULLS_LAST") {
+		nullsFirst = false
+	}
+	return CursorOrderColumn{Name: name, Descending: descending, NullsFirst: nullsFirst}
+}
+
+func (column CursorOrderColumn) Reverse() CursorOrderColumn {
+	return CursorOrderColumn{Name: column.Name, Descending: !column.Descending, NullsFirst: !column.NullsFirst}
+}
+
+func (column CursorOrderColumn) String() string {
+	direction := "ASC"
+	if column.Descending {
+		direction = "DESC"
+	}
+	nulls := "NULLS LAST"
+	if column.NullsFirst {
+		nulls = "NULLS FIRST"
+	}
+	return fmt.Sprintf("%s %s %s", column.Name, direction, nulls)
+}
+
+func (model *Model) GetPrimaryKeyColumns() []string {
+	columns := make([]string, 0)
+	for _, index := range model.Indexes {
+		if index.Type == "PRIMARY KEY" && model.isModelColumn(index.Column) {
+			columns = append(columns, index.Column)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func (model *Model) GetCursorOrderColumns(body any) ([]CursorOrderColumn, error) {
+	columns := make([]CursorOrderColumn, 0)
+	used := make(map[string]bool)
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return columns, err
+	}
+	for _, entry := range GetOrderByEntries(parsedBody["_orderBy"]) {
+		direction, ok := entry.Value.(string)
+		if !ok || !model.isModelColumn(entry.Key) {
+			return columns, NewBadRequestError("cursor pagination on %s.%s can't order by %s, only table columns are supported", model.Database, model.Table, entry.Key)
+		}
+		if _, ok := ORDER_BY_KEYS[direction]; !ok {
+			return columns, NewBadRequestError("invalid order direction %s for %s", direction, entry.Key)
+		}
+		if used[entry.Key] {
+			continue
+		}
+		columns = append(columns, NewCursorOrderColumn(entry.Key, direction))
+		used[entry.Key] = true
+	}
+	primaryKeys := model.GetPrimaryKeyColumns()
+	if len(primaryKeys) == 0 || model.IsReadOnly() {
+		if len(columns) == 0 {
+			return columns, NewBadRequestError("cursor pagination on %s.%s requires _orderBy or a primary key", model.Database, model.Table)
+		}
+		if !model.IsUniqueOrdering(used) {
+			return columns, NewBadRequestError("cursor pagination on %s.%s requires _orderBy to include a primary key or unique column", model.Database, model.Table)
+		}
+		return columns, nil
+	}
+	for _, key := range primaryKeys {
+		if !used[key] {
+			columns = append(columns, NewCursorOrderColumn(key, "ASC"))
+		}
+	}
+	return columns, nil
+}
+
+func (model *Model) IsUniqueOrdering(used map[string]bool) bool {
+	constraints := make(map[string][]string)
+	for _, index := range model.Indexes {
+		if index.Type == "PRIMARY KEY" || index.Type == IndexType(UNIQUE) {
+			constraints[index.Name] = append(constraints[index.Name], index.Column)
+		}
+	}
+	for _, columns := range constraints {
+		unique := true
+		for _, column := range columns {
+			if !used[column] {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return true
+		}
+	}
+	return false
+}
+
+func BuildCursorExpression(columns []CursorOrderColumn, alias string) string {
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		parts = append(parts, fmt.Sprintf("%s.%s", alias, column.Name))
+	}
+	return fmt.Sprintf(`translate(encode(convert_to(json_build_array(%s)::text, 'UTF8'), 'base64'), E'\n', '') AS %s`, strings.Join(parts, ","), CURSOR_KEY)
+}
+
+func DecodeCursor(cursor string, size int) ([]any, error) {
+	payload, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	values := make([]any, 0)
+	err = decoder.Decode(&values)
+	if err != nil || len(values) != size {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	for i, value := range values {
+		switch parsedValue := value.(type) {
+		case json.Number:
+			values[i] = parsedValue.String()
+		case map[string]any, []any:
+			jsonValue, err := json.Marshal(parsedValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cursor")
+			}
+			values[i] = string(jsonValue)
+		}
+	}
+	return values, nil
+}
+
+func BuildCursorCondition(columns []CursorOrderColumn, values []any, alias string, idx *int) (string, []any) {
+	args := make([]any, 0)
+	equalParts := make([]string, 0)
+	orParts := make([]string, 0)
+	for i, column := range columns {
+		columnName := fmt.Sprintf("%s.%s", alias, column.Name)
+		afterPart := "FALSE"
+		equalPart := fmt.Sprintf("%s IS NULL", columnName)
+		if values[i] == nil {
+			if column.NullsFirst {
+				afterPart = fmt.Sprintf("%s IS NOT NULL", columnName)
+			}
+		} else {
+			operator := ">"
+			if column.Descending {
+				operator = "<"
+			}
+			afterPart = fmt.Sprintf("%s %s $%d", columnName, operator, *idx)
+			if !column.NullsFirst {
+				afterPart = fmt.Sprintf("(%s OR %s IS NULL)", afterPart, columnName)
+			}
+			equalPart = fmt.Sprintf("%s = $%d", columnName, *idx)
+			args = append(args, values[i])
+			*idx += 1
+		}
+		orParts = append(orParts, fmt.Sprintf("(%s)", strings.Join(append(append([]string{}, equalParts...), afterPart), " AND ")))
+		equalParts = append(equalParts, equalPart)
+	}
+	return fmt.Sprintf("(%s)", strings.Join(orParts, " OR ")), args
+}
+
+func BuildCursorOrderBy(columns []CursorOrderColumn, reverse bool) string {
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if reverse {
+			column = column.Reverse()
+		}
+		parts = append(parts, column.String())
+	}
+	return fmt.Sprintf(" ORDER BY %s ", strings.Join(parts, ","))
+}
+
+func (model *Model) BuildCursorPagination(body any, alias string, idx *int) (CursorPagination, error) {
+	pagination := CursorPagination{Args: make([]any, 0)}
+	if !IsCursorPagination(body) {
+		return pagination, nil
+	}
+	columns, err := model.GetCursorOrderColumns(body)
+	if err != nil {
+		return pagination, err
+	}
+	after := GetCursorValue(body, CURSOR_AFTER_KEY)
+	before := GetCursorValue(body, CURSOR_BEFORE_KEY)
+
+	pagination.Enabled = true
+	pagination.Columns = columns
+	pagination.Reverse = len(before) > 0 && len(after) == 0
+	pagination.Column = BuildCursorExpression(columns, alias)
+	pagination.OrderBy = BuildCursorOrderBy(columns, pagination.Reverse)
+
+	conditions := make([]string, 0)
+	if len(after) > 0 {
+		values, err := DecodeCursor(after, len(columns))
+		if err != nil {
+			return pagination, err
+		}
+		condition, args := BuildCursorCondition(columns, values, alias, idx)
+		conditions = append(conditions, condition)
+		pagination.Args = append(pagination.Args, args...)
+	}
+	if len(before) > 0 {
+		values, err := DecodeCursor(before, len(columns))
+		if err != nil {
+			return pagination, err
+		}
+		reversedColumns := make([]CursorOrderColumn, 0, len(columns))
+		for _, column := range columns {
+			reversedColumns = append(reversedColumns, column.Reverse())
+		}
+		condition, args := BuildCursorCondition(reversedColumns, values, alias, idx)
+		conditions = append(conditions, condition)
+		pagination.Args = append(pagination.Args, args...)
+	}
+	pagination.Condition = strings.Join(conditions, " AND ")
+	return pagination, nil
+}
+
+func AppendWhereCondition(whereQuery string, condition string) string {
+	if len(condition) == 0 {
+		return whereQuery
+	}
+	existing := strings.TrimSpace(whereQuery)
+	existing = strings.TrimSpace(strings.TrimPrefix(existing, "WHERE"))
+	if len(existing) == 0 {
+		return fmt.Sprintf(" WHERE %s ", condition)
+	}
+	return fmt.Sprintf(" WHERE (%s) AND %s ", existing, condition)
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	cases := []struct {
+		name    string
+		cursor  string
+		size    int
+		want    []any
+		wantErr bool
+	}{
+		{name: "values", cursor: encode(`["a",5,1.5,null,true]`), size: 5, want: []any{"a", "5", "1.5", nil, true}},
+		{name: "large numbers keep precision", cursor: encode(`[9007199254740993]`), size: 1, want: []any{"9007199254740993"}},
+		{name: "json values", cursor: encode(`[{"a":1},[1,2]]`), size: 2, want: []any{`{"a":1}`, `[1,2]`}},
+		{name: "not base64", cursor: "%%%", size: 1, wantErr: true},
+		{name: "not an array", cursor: encode(`{"a":1}`), size: 1, wantErr: true},
+		{name: "wrong size", cursor: encode(`[1,2]`), size: 1, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values, err := DecodeCursor(c.cursor, c.size)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(values, c.want) {
+				t.Errorf("expected %v, got %v", c.want, values)
+			}
+		})
+	}
+}
+
+func TestBuildCursorCondition(t *testing.T) {
+	cases := []struct {
+		name      string
+		columns   []CursorOrderColumn
+		values    []any
+		want      string
+		wantArgs  []any
+		wantIndex int
+	}{
+		{
+			name:      "single column",
+			columns:   []CursorOrderColumn{NewCursorOrderColumn("id", "ASC")},
+			values:    []any{"5"},
+			want:      "(((t.id > $1 OR t.id IS NULL)))",
+			wantArgs:  []any{"5"},
+			wantIndex: 2,
+		},
+		{
+			name:      "descending with tie breaker",
+			columns:   []CursorOrderColumn{NewCursorOrderColumn("created_at", "DESC"), NewCursorOrderColumn("id", "ASC")},
+			values:    []any{"2024-01-01", "5"},
+			want:      "((t.created_at < $1) OR (t.created_at = $1 AND (t.id > $2 OR t.id IS NULL)))",
+			wantArgs:  []any{"2024-01-01", "5"},
+			wantIndex: 3,
+		},
+		{
+			name:      "null with nulls last",
+			columns:   []CursorOrderColumn{NewCursorOrderColumn("name", "ASC"), NewCursorOrderColumn("id", "ASC")},
+			values:    []any{nil, "5"},
+			want:      "((FALSE) OR (t.name IS NULL AND (t.id > $1 OR t.id IS NULL)))",
+			wantArgs:  []any{"5"},
+			wantIndex: 2,
+		},
+		{
+			name:      "null with nulls first",
+			columns:   []CursorOrderColumn{NewCursorOrderColumn("name", "ASC_NULLS_FIRST"), NewCursorOrderColumn("id", "ASC")},
+			values:    []any{nil, "5"},
+			want:      "((t.name IS NOT NULL) OR (t.name IS NULL AND (t.id > $1 OR t.id IS NULL)))",
+			wantArgs:  []any{"5"},
+			wantIndex: 2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			idx := 1
+			condition, args := BuildCursorCondition(c.columns, c.values, "t", &idx)
+			if condition != c.want {
+				t.Errorf("expected condition %q, got %q", c.want, condition)
+			}
+			if !reflect.DeepEqual(args, c.wantArgs) {
+				t.Errorf("expected args %v, got %v", c.wantArgs, args)
+			}
+			if idx != c.wantIndex {
+				t.Errorf("expected index %d, got %d", c.wantIndex, idx)
+			}
+		})
+	}
+}
+
+func TestGetCursorOrderColumns(t *testing.T) {
+	model := &Model{
+		Database:   "public",
+		Table:      "posts",
+		ColumnsMap: ColumnsMap{"id": "integer", "title": "text", "created_at": "timestamp"},
+		Indexes:    []Index{{Type: "PRIMARY KEY", Column: "id"}},
+	}
+	orderBy := NewOrderedMap()
+	orderBy.Set("title", "DESC_NULLS_LAST")
+	orderBy.Set("created_at", "ASC")
+
+	columns, err := model.GetCursorOrderColumns(map[string]any{"_orderBy": orderBy})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := []CursorOrderColumn{
+		{Name: "title", Descending: true, NullsFirst: false},
+		{Name: "created_at", Descending: false, NullsFirst: false},
+		{Name: "id", Descending: false, NullsFirst: false},
+	}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("expected %v, got %v", want, columns)
+	}
+
+	invalid := []map[string]any{
+		{"_orderBy": map[string]any{"author": map[string]any{"name": "ASC"}}},
+		{"_orderBy": map[string]any{"title": "UP"}},
+	}
+	for _, body := range invalid {
+		_, err := model.GetCursorOrderColumns(body)
+		if GetErrorStatusCode(err, 0) != http.StatusBadRequest {
+			t.Errorf("expected a bad request error for %v, got %v", body, err)
+		}
+	}
+
+	_, err = (&Model{Database: "public", Table: "logs"}).GetCursorOrderColumns(map[string]any{})
+	if GetErrorStatusCode(err, 0) != http.StatusBadRequest {
+		t.Errorf("expected a bad request error without primary key, got %v", err)
+	}
+}
+
+func TestGetCursorOrderColumnsRequiresUniqueOrdering(t *testing.T) {
+	logs := &Model{
+		Database:   "public",
+		Table:      "logs",
+		ColumnsMap: ColumnsMap{"code": "text", "level": "text", "created_at": "timestamp"},
+		Indexes: []Index{
+			{Name: "logs_code_level_key", Type: IndexType(UNIQUE), Column: "code"},
+			{Name: "logs_code_level_key", Type: IndexType(UNIQUE), Column: "level"},
+		},
+	}
+	view := &Model{
+		Database:   "public",
+		Table:      "active_posts",
+		Kind:       VIEW_KIND,
+		ColumnsMap: ColumnsMap{"id": "integer", "title": "text"},
+	}
+	cases := []struct {
+		name    string
+		model   *Model
+		orderBy []string
+		valid   bool
+	}{
+		{name: "no primary key and non unique ordering", model: logs, orderBy: []string{"created_at"}, valid: false},
+		{name: "no primary key and partial unique ordering", model: logs, orderBy: []string{"code", "created_at"}, valid: false},
+		{name: "no primary key and unique ordering", model: logs, orderBy: []string{"created_at", "level", "code"}, valid: true},
+		{name: "view without unique columns", model: view, orderBy: []string{"id"}, valid: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			orderBy := NewOrderedMap()
+			for _, column := range c.orderBy {
+				orderBy.Set(column, "ASC")
+			}
+			columns, err := c.model.GetCursorOrderColumns(map[string]any{"_orderBy": orderBy})
+			if c.valid {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if len(columns) != len(c.orderBy) {
+					t.Errorf("expected %d columns, got %v", len(c.orderBy), columns)
+				}
+				return
+			}
+			if GetErrorStatusCode(err, 0) != http.StatusBadRequest {
+				t.Errorf("expected a bad request error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var GRAPHQL_ACTION string = "ACTION"
+
+var GRAPHQL_NAME_PATTERN *regexp.Regexp = regexp.MustCompile("^[_a-zA-Z][_a-zA-Z0-9]*$")
+
+type CustomRestHandlerColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func GetRestHandlerGraphqlName(input CustomRestHandlerInput) string {
+	if len(input.GraphQLName) > 0 {
+		return input.GraphQLName
+	}
+	parts := []string{input.Database}
+	for _, segment := range strings.Split(strings.Trim(input.Endpoint, "/"), "/")[1:] {
+		if _, ok := GetEndpointPathParameter(segment); ok {
+			continue
+		}
+		parts = append(parts, segment)
+	}
+	parts = append(parts, strings.ToLower(input.Method))
+	return strings.Join(parts, "_")
+}
+
+func (engine *Engine) ValidateRestHandlerGraphqlName(input CustomRestHandlerInput) error {
+	if !input.GraphQL {
+		return nil
+	}
+	name := GetRestHandlerGraphqlName(input)
+	if !GRAPHQL_NAME_PATTERN.MatchString(name) {
+		return fmt.Errorf("graphql name %s should contain only letters numbers and underscores", name)
+	}
+	if engine.GraphQL != nil {
+		if config, ok := engine.GraphQL.EngineResolverNameToDatabaseTableConfigMap[name]; ok {
+			if config.Action == nil || fmt.Sprint(config.Action.Id) != fmt.Sprint(input.Id) {
+				return fmt.Errorf("graphql field %s already exists", name)
+			}
+		}
+	}
+	return nil
+}
+
+func IsRestHandlerGraphqlQuery(input CustomRestHandlerInput) bool {
+	return input.Method == GET
+}
+
+func GetGraphqlTypeByDatabaseTypeName(typeName string) string {
+	switch typeName {
+	case "INT2", "INT4", "INT8":
+		return "Int"
+	case "FLOAT4", "FLOAT8", "NUMERIC":
+		return "Float"
+	case "BOOL":
+		return "Boolean"
+	case "JSON", "JSONB":
+		return "Object"
+	default:
+		return "String"
+	}
+}
+
+func GetGraphqlTypeByRestHandlerParamType(paramType string) string {
+	switch paramType {
+	case REST_PARAM_INT:
+		return "Int"
+	case REST_PARAM_FLOAT:
+		return "Float"
+	case REST_PARAM_BOOLEAN:
+		return "Boolean"
+	case REST_PARAM_JSON:
+		return "Object"
+	default:
+		return "String"
+	}
+}
+
+func GetQueryParameterCount(query string) int {
+	count := 0
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != c {
+				j++
+			}
+			i = j
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i = GetBlockCommentEnd(runes, i) - 1
+		case c == '$':
+			if tag, ok := GetDollarQuoteTag(runes, i); ok {
+				i = GetDollarQuoteEnd(runes, tag, i+len(tag)) - 1
+				continue
+			}
+			j := i + 1
+			for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+				j++
+			}
+			if index, err := strconv.Atoi(string(runes[i+1 : j])); err == nil && index > count {
+				count = index
+			}
+			i = j - 1
+		}
+	}
+	return count
+}
+
+func BuildRestHandlerDescribeQuery(query string) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	return fmt.Sprintf("SELECT * FROM (%s\n) _engine_describe LIMIT 0", query)
+}
+
+func InferRestHandlerColumns(db *sql.DB, input CustomRestHandlerInput) ([]CustomRestHandlerColumn, error) {
+	query, _ := CompileNamedQuery(input.Query)
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	args := make([]any, GetQueryParameterCount(query))
+	rows, err := tx.QueryContext(ctx, BuildRestHandlerDescribeQuery(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]CustomRestHandlerColumn, 0)
+	for _, columnType := range columnTypes {
+		if !GRAPHQL_NAME_PATTERN.MatchString(columnType.Name()) {
+			return nil, fmt.Errorf("column %s cannot be exposed as a graphql field", columnType.Name())
+		}
+		columns = append(columns, CustomRestHandlerColumn{
+			Name: columnType.Name(),
+			Type: GetGraphqlTypeByDatabaseTypeName(columnType.DatabaseTypeName()),
+		})
+	}
+	return columns, nil
+}
+
+func BuildRestHandlerGraphqlArgs(input CustomRestHandlerInput) string {
+	args := make([]string, 0)
+	if len(input.Params) > 0 {
+		for _, param := range input.Params {
+			argType := GetGraphqlTypeByRestHandlerParamType(param.Type)
+			if param.Type == REST_PARAM_ARRAY {
+				argType = fmt.Sprintf("[%s]", GetGraphqlTypeByRestHandlerParamType(param.Items))
+			}
+			if param.Required && param.Default == nil {
+				argType += "!"
+			}
+			args = append(args, fmt.Sprintf("%s: %s", param.Name, argType))
+		}
+	} else {
+		_, names := CompileNamedQuery(input.Query)
+		for _, name := range names {
+			args = append(args, fmt.Sprintf("%s: SingleValue", name))
+		}
+		if len(names) == 0 {
+			args = append(args, "args: [SingleValue]")
+		}
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s)", strings.Join(args, ", "))
+}
+
+func BuildRestHandlerGraphqlResultType(input CustomRestHandlerInput) (string, string) {
+	if len(input.Columns) == 0 {
+		return "", "[Object]"
+	}
+	typeName := fmt.Sprintf("%s_result", GetRestHandlerGraphqlName(input))
+	fields := make([]string, 0)
+	for _, column := range input.Columns {
+		fields = append(fields, fmt.Sprintf("%s: %s", column.Name, column.Type))
+	}
+	return fmt.Sprintf("type %s {\n%s\n}", typeName, strings.Join(fields, "\n")), fmt.Sprintf("[%s!]", typeName)
+}
+
+func (e *Engine) GetGraphqlRestHandlers() []CustomRestHandlerInput {
+	handlers := make([]CustomRestHandlerInput, 0)
+	for _, handler := range e.RestHandlers {
+		if handler.GraphQL && handler.Enabled {
+			handlers = append(handlers, handler)
+		}
+	}
+	return handlers
+}
+
+func (e *Engine) BuildRestHandlerGraphqlTypes() []string {
+	types := make([]string, 0)
+	for _, handler := range e.GetGraphqlRestHandlers() {
+		resultType, _ := BuildRestHandlerGraphqlResultType(handler)
+		if len(resultType) > 0 {
+			types = append(types, resultType)
+		}
+	}
+	return types
+}
+
+func (e *Engine) BuildRestHandlerGraphqlFields(isQuery bool) []string {
+	fields := make([]string, 0)
+	for _, handler := range e.GetGraphqlRestHandlers() {
+		if IsRestHandlerGraphqlQuery(handler) != isQuery {
+			continue
+		}
+		_, returnType := BuildRestHandlerGraphqlResultType(handler)
+		fields = append(fields, fmt.Sprintf("%s%s: %s", GetRestHandlerGraphqlName(handler), BuildRestHandlerGraphqlArgs(handler), returnType))
+	}
+	return fields
+}
+
+func FilterRowsBySelection(rows []any, selection any) []any {
+	selectMap, err := IsMapToInterface(selection)
+	if err != nil || len(selectMap) == 0 {
+		return rows
+	}
+	filtered := make([]any, 0, len(rows))
+	for _, row := range rows {
+		parsedRow, err := IsMapToInterface(row)
+		if err != nil {
+			filtered = append(filtered, row)
+			continue
+		}
+		filteredRow := make(map[string]any)
+		for key := range selectMap {
+			filteredRow[key] = parsedRow[key]
+		}
+		filtered = append(filtered, filteredRow)
+	}
+	return filtered
+}
+
+func (e *Engine) ResolveGraphqlAction(config EngineGraphQlDatabaseTableConfig, value any, auth jwt.MapClaims, db *sql.DB) ([]any, error) {
+	if config.Action == nil {
+		return nil, fmt.Errorf("no such relation")
+	}
+	action := *config.Action
+
+	params := make(map[string]any)
+	parsedValue, err := IsMapToInterface(value)
+	if err == nil {
+		for key, entry := range parsedValue {
+			params[key] = entry
+		}
+	}
+	selection := params["_select"]
+	delete(params, "_select")
+
+	var actionAuth jwt.MapClaims
+	if action.Auth {
+		err := CanAccess(config, auth)
+		if err != nil {
+			return nil, err
+		}
+		actionAuth = auth
+	}
+
+	rows, err := e.ExecuteRestHandler(db, actionAuth, action, params)
+	if err != nil {
+		return nil, err
+	}
+	return FilterRowsBySelection(rows, selection), nil
+}
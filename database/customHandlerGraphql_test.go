@@ -0,0 +1,44 @@
+package database
+
+import "testing"
+
+func TestGetQueryParameterCount(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "no parameters", query: "SELECT 1", want: 0},
+		{name: "compiled named parameters", query: "SELECT $1, $2, $1", want: 2},
+		{name: "gaps", query: "SELECT $3", want: 3},
+		{name: "casts", query: "SELECT $1::int", want: 1},
+		{name: "quoted strings", query: `SELECT '$5', "$6", $1`, want: 1},
+		{name: "comments", query: "SELECT $1 -- $9\n/* $8 */", want: 1},
+		{name: "dollar quoted strings", query: "SELECT $$ $7 $$, $tag$ $6 $tag$, $2", want: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if count := GetQueryParameterCount(c.query); count != c.want {
+				t.Errorf("expected %d, got %d", c.want, count)
+			}
+		})
+	}
+}
+
+func TestBuildRestHandlerDescribeQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{query: "SELECT id FROM users", want: "SELECT * FROM (SELECT id FROM users\n) _engine_describe LIMIT 0"},
+		{query: " SELECT id FROM users; ", want: "SELECT * FROM (SELECT id FROM users\n) _engine_describe LIMIT 0"},
+		{query: "SELECT id FROM users -- trailing", want: "SELECT * FROM (SELECT id FROM users -- trailing\n) _engine_describe LIMIT 0"},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			if query := BuildRestHandlerDescribeQuery(c.query); query != c.want {
+				t.Errorf("expected %q, got %q", c.want, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var REST_PARAM_INT string = "int"
+var REST_PARAM_FLOAT string = "float"
+var REST_PARAM_BOOLEAN string = "boolean"
+var REST_PARAM_TEXT string = "text"
+var REST_PARAM_UUID string = "uuid"
+var REST_PARAM_TIMESTAMP string = "timestamp"
+var REST_PARAM_ARRAY string = "array"
+var REST_PARAM_JSON string = "json"
+
+var REST_PARAM_TIMESTAMP_LAYOUTS []string = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+type CustomRestHandlerParam struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Items     string   `json:"items,omitempty"`
+	Required  bool     `json:"required"`
+	Default   any      `json:"default,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Enum      []any    `json:"enum,omitempty"`
+
+	CompiledPattern *regexp.Regexp `json:"-"`
+}
+
+type CustomRestHandlerParamError struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+type CustomRestHandlerValidationError struct {
+	Errors []CustomRestHandlerParamError `json:"errors"`
+}
+
+func (e *CustomRestHandlerValidationError) Error() string {
+	messages := make([]string, 0)
+	for _, paramError := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", paramError.Param, paramError.Message))
+	}
+	return fmt.Sprintf("invalid parameters: %s", strings.Join(messages, ", "))
+}
+
+func IsRestHandlerParamType(paramType string) bool {
+	switch paramType {
+	case REST_PARAM_INT, REST_PARAM_FLOAT, REST_PARAM_BOOLEAN, REST_PARAM_TEXT, REST_PARAM_UUID, REST_PARAM_TIMESTAMP, REST_PARAM_ARRAY, REST_PARAM_JSON:
+		return true
+	default:
+		return false
+	}
+}
+
+func FormatRestHandlerParams(params []CustomRestHandlerParam) []CustomRestHandlerParam {
+	for i := range params {
+		params[i].Name = strings.Trim(params[i].Name, " ")
+		params[i].Type = strings.ToLower(strings.Trim(params[i].Type, " "))
+		params[i].Items = strings.ToLower(strings.Trim(params[i].Items, " "))
+		if params[i].Type == REST_PARAM_ARRAY && len(params[i].Items) == 0 {
+			params[i].Items = REST_PARAM_TEXT
+		}
+	}
+	return params
+}
+
+func CompileRestHandlerParamPatterns(params []CustomRestHandlerParam) error {
+	for i, param := range params {
+		if len(param.Pattern) == 0 {
+			continue
+		}
+		pattern, err := regexp.Compile(param.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for parameter %s: %s", param.Name, err.Error())
+		}
+		params[i].CompiledPattern = pattern
+	}
+	return nil
+}
+
+func ValidateRestHandlerParams(input CustomRestHandlerInput) error {
+	if len(input.Params) == 0 {
+		return nil
+	}
+
+	err := CompileRestHandlerParamPatterns(input.Params)
+	if err != nil {
+		return err
+	}
+
+	_, names := CompileNamedQuery(input.Query)
+	declared := make(map[string]bool)
+	for _, param := range input.Params {
+		if len(param.Name) == 0 || !isNamedParameterStart([]rune(param.Name)[0]) {
+			return fmt.Errorf("invalid parameter name %s", param.Name)
+		}
+		if declared[param.Name] {
+			return fmt.Errorf("parameter %s is declared more than once", param.Name)
+		}
+		declared[param.Name] = true
+
+		if !IsRestHandlerParamType(param.Type) {
+			return fmt.Errorf("not supported type %s for parameter %s", param.Type, param.Name)
+		}
+		if param.Type == REST_PARAM_ARRAY && (!IsRestHandlerParamType(param.Items) || param.Items == REST_PARAM_ARRAY) {
+			return fmt.Errorf("not supported array items type %s for parameter %s", param.Items, param.Name)
+		}
+		if param.Default != nil {
+			_, err := CoerceRestHandlerParam(param, param.Default)
+			if err != nil {
+				return fmt.Errorf("invalid default for parameter %s: %s", param.Name, err.Error())
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, name := range names {
+		used[name] = true
+		if !declared[name] {
+			return fmt.Errorf("parameter %s is used in the query but not declared", name)
+		}
+	}
+	for _, param := range input.Params {
+		if !used[param.Name] {
+			return fmt.Errorf("parameter %s is declared but not used in the query", param.Name)
+		}
+	}
+	return nil
+}
+
+func CoerceRestHandlerInt(value any) (int64, error) {
+	switch value := value.(type) {
+	case float64:
+		if value != float64(int64(value)) {
+			return 0, fmt.Errorf("should be an integer")
+		}
+		return int64(value), nil
+	case int:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case string:
+		parsed, err := strconv.ParseInt(strings.Trim(value, " "), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("should be an integer")
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("should be an integer")
+	}
+}
+
+func CoerceRestHandlerFloat(value any) (float64, error) {
+	switch value := value.(type) {
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.Trim(value, " "), 64)
+		if err != nil {
+			return 0, fmt.Errorf("should be a number")
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("should be a number")
+	}
+}
+
+func CoerceRestHandlerScalar(paramType string, value any) (any, error) {
+	switch paramType {
+	case REST_PARAM_INT:
+		return CoerceRestHandlerInt(value)
+	case REST_PARAM_FLOAT:
+		return CoerceRestHandlerFloat(value)
+	case REST_PARAM_BOOLEAN:
+		switch value := value.(type) {
+		case bool:
+			return value, nil
+		case string:
+			parsed, err := strconv.ParseBool(strings.Trim(value, " "))
+			if err != nil {
+				return nil, fmt.Errorf("should be a boolean")
+			}
+			return parsed, nil
+		default:
+			return nil, fmt.Errorf("should be a boolean")
+		}
+	case REST_PARAM_TEXT:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("should be a string")
+		}
+		return text, nil
+	case REST_PARAM_UUID:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("should be a uuid")
+		}
+		parsed, err := uuid.Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("should be a uuid")
+		}
+		return parsed.String(), nil
+	case REST_PARAM_TIMESTAMP:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("should be a timestamp")
+		}
+		for _, layout := range REST_PARAM_TIMESTAMP_LAYOUTS {
+			parsed, err := time.Parse(layout, text)
+			if err == nil {
+				return parsed, nil
+			}
+		}
+		return nil, fmt.Errorf("should be a timestamp in RFC3339 format")
+	case REST_PARAM_JSON:
+		if text, ok := value.(string); ok && json.Valid([]byte(text)) {
+			return text, nil
+		}
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("should be valid json")
+		}
+		return string(jsonValue), nil
+	default:
+		return nil, fmt.Errorf("not supported type %s", paramType)
+	}
+}
+
+func GetRestHandlerArrayValues(value any) ([]any, error) {
+	switch value := value.(type) {
+	case []any:
+		return value, nil
+	case []string:
+		values := make([]any, 0, len(value))
+		for _, entry := range value {
+			values = append(values, entry)
+		}
+		return values, nil
+	case string:
+		values := make([]any, 0)
+		if len(strings.Trim(value, " ")) == 0 {
+			return values, nil
+		}
+		for _, entry := range strings.Split(value, ",") {
+			values = append(values, strings.Trim(entry, " "))
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("should be an array")
+	}
+}
+
+func CoerceRestHandlerArray(param CustomRestHandlerParam, value any) (any, []any, error) {
+	entries, err := GetRestHandlerArrayValues(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	coerced := make([]any, 0, len(entries))
+	for i, entry := range entries {
+		parsed, err := CoerceRestHandlerScalar(param.Items, entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("item %d %s", i, err.Error())
+		}
+		coerced = append(coerced, parsed)
+	}
+
+	switch param.Items {
+	case REST_PARAM_INT:
+		values := make([]int64, 0, len(coerced))
+		for _, entry := range coerced {
+			values = append(values, entry.(int64))
+		}
+		return pq.Array(values), coerced, nil
+	case REST_PARAM_FLOAT:
+		values := make([]float64, 0, len(coerced))
+		for _, entry := range coerced {
+			values = append(values, entry.(float64))
+		}
+		return pq.Array(values), coerced, nil
+	case REST_PARAM_BOOLEAN:
+		values := make([]bool, 0, len(coerced))
+		for _, entry := range coerced {
+			values = append(values, entry.(bool))
+		}
+		return pq.Array(values), coerced, nil
+	default:
+		values := make([]string, 0, len(coerced))
+		for _, entry := range coerced {
+			if timestamp, ok := entry.(time.Time); ok {
+				values = append(values, timestamp.Format(time.RFC3339Nano))
+				continue
+			}
+			values = append(values, fmt.Sprint(entry))
+		}
+		return pq.Array(values), coerced, nil
+	}
+}
+
+func ValidateRestHandlerParamConstraints(param CustomRestHandlerParam, value any, entries []any) error {
+	if len(param.Enum) > 0 && param.Type != REST_PARAM_ARRAY {
+		allowed := false
+		for _, option := range param.Enum {
+			coerced, err := CoerceRestHandlerScalar(param.Type, option)
+			if err == nil && fmt.Sprint(coerced) == fmt.Sprint(value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("should be one of %v", param.Enum)
+		}
+	}
+
+	var number *float64
+	switch value := value.(type) {
+	case int64:
+		converted := float64(value)
+		number = &converted
+	case float64:
+		number = &value
+	}
+	if number != nil {
+		if param.Min != nil && *number < *param.Min {
+			return fmt.Errorf("should be greater than or equal to %v", *param.Min)
+		}
+		if param.Max != nil && *number > *param.Max {
+			return fmt.Errorf("should be less than or equal to %v", *param.Max)
+		}
+	}
+
+	length := -1
+	if text, ok := value.(string); ok && param.Type == REST_PARAM_TEXT {
+		length = len([]rune(text))
+		if len(param.Pattern) > 0 && (param.CompiledPattern == nil || !param.CompiledPattern.MatchString(text)) {
+			return fmt.Errorf("should match pattern %s", param.Pattern)
+		}
+	}
+	if param.Type == REST_PARAM_ARRAY {
+		length = len(entries)
+	}
+	if length >= 0 {
+		if param.MinLength != nil && length < *param.MinLength {
+			return fmt.Errorf("length should be greater than or equal to %d", *param.MinLength)
+		}
+		if param.MaxLength != nil && length > *param.MaxLength {
+			return fmt.Errorf("length should be less than or equal to %d", *param.MaxLength)
+		}
+	}
+	return nil
+}
+
+func CoerceRestHandlerParam(param CustomRestHandlerParam, value any) (any, error) {
+	if param.Type == REST_PARAM_ARRAY {
+		array, entries, err := CoerceRestHandlerArray(param, value)
+		if err != nil {
+			return nil, err
+		}
+		err = ValidateRestHandlerParamConstraints(param, array, entries)
+		if err != nil {
+			return nil, err
+		}
+		return array, nil
+	}
+
+	coerced, err := CoerceRestHandlerScalar(param.Type, value)
+	if err != nil {
+		return nil, err
+	}
+	err = ValidateRestHandlerParamConstraints(param, coerced, nil)
+	if err != nil {
+		return nil, err
+	}
+	return coerced, nil
+}
+
+func GetValidatedRestHandlerArgs(input CustomRestHandlerInput, params map[string]any) (map[string]any, error) {
+	values := make(map[string]any)
+	validationError := &CustomRestHandlerValidationError{Errors: make([]CustomRestHandlerParamError, 0)}
+	for _, param := range input.Params {
+		value, ok := params[param.Name]
+		if !ok || value == nil {
+			if param.Default != nil {
+				value = param.Default
+			} else if param.Required {
+				validationError.Errors = append(validationError.Errors, CustomRestHandlerParamError{Param: param.Name, Message: "is required"})
+				continue
+			} else {
+				values[param.Name] = nil
+				continue
+			}
+		}
+		coerced, err := CoerceRestHandlerParam(param, value)
+		if err != nil {
+			validationError.Errors = append(validationError.Errors, CustomRestHandlerParamError{Param: param.Name, Message: err.Error()})
+			continue
+		}
+		values[param.Name] = coerced
+	}
+	if len(validationError.Errors) > 0 {
+		return nil, validationError
+	}
+	return values, nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCoerceRestHandlerParam(t *testing.T) {
+	min := 1.0
+	max := 10.0
+	minLength := 2
+	maxLength := 3
+	cases := []struct {
+		name    string
+		param   CustomRestHandlerParam
+		value   any
+		want    any
+		wantErr string
+	}{
+		{name: "int from float", param: CustomRestHandlerParam{Type: REST_PARAM_INT}, value: 5.0, want: int64(5)},
+		{name: "int from string", param: CustomRestHandlerParam{Type: REST_PARAM_INT}, value: " 7 ", want: int64(7)},
+		{name: "int rejects fraction", param: CustomRestHandlerParam{Type: REST_PARAM_INT}, value: 1.5, wantErr: "should be an integer"},
+		{name: "float from string", param: CustomRestHandlerParam{Type: REST_PARAM_FLOAT}, value: "2.5", want: 2.5},
+		{name: "boolean from string", param: CustomRestHandlerParam{Type: REST_PARAM_BOOLEAN}, value: "true", want: true},
+		{name: "boolean rejects text", param: CustomRestHandlerParam{Type: REST_PARAM_BOOLEAN}, value: "yes", wantErr: "should be a boolean"},
+		{name: "text rejects number", param: CustomRestHandlerParam{Type: REST_PARAM_TEXT}, value: 1.0, wantErr: "should be a string"},
+		{name: "uuid", param: CustomRestHandlerParam{Type: REST_PARAM_UUID}, value: "7C9E6679-7425-40DE-944B-E07FC1F90AE7", want: "7c9e6679-7425-40de-944b-e07fc1f90ae7"},
+		{name: "uuid rejects text", param: CustomRestHandlerParam{Type: REST_PARAM_UUID}, value: "abc", wantErr: "should be a uuid"},
+		{name: "json from object", param: CustomRestHandlerParam{Type: REST_PARAM_JSON}, value: map[string]any{"a": 1.0}, want: `{"a":1}`},
+		{name: "json string kept", param: CustomRestHandlerParam{Type: REST_PARAM_JSON}, value: `[1,2]`, want: `[1,2]`},
+		{name: "min", param: CustomRestHandlerParam{Type: REST_PARAM_INT, Min: &min}, value: 0.0, wantErr: "should be greater than or equal to 1"},
+		{name: "max", param: CustomRestHandlerParam{Type: REST_PARAM_FLOAT, Max: &max}, value: 10.5, wantErr: "should be less than or equal to 10"},
+		{name: "within range", param: CustomRestHandlerParam{Type: REST_PARAM_INT, Min: &min, Max: &max}, value: 10.0, want: int64(10)},
+		{name: "min length", param: CustomRestHandlerParam{Type: REST_PARAM_TEXT, MinLength: &minLength}, value: "a", wantErr: "length should be greater than or equal to 2"},
+		{name: "max length counts runes", param: CustomRestHandlerParam{Type: REST_PARAM_TEXT, MaxLength: &maxLength}, value: "äöü", want: "äöü"},
+		{name: "enum", param: CustomRestHandlerParam{Type: REST_PARAM_INT, Enum: []any{1.0, 2.0}}, value: "2", want: int64(2)},
+		{name: "enum rejects value", param: CustomRestHandlerParam{Type: REST_PARAM_TEXT, Enum: []any{"a", "b"}}, value: "c", wantErr: "should be one of [a b]"},
+		{name: "array item error", param: CustomRestHandlerParam{Type: REST_PARAM_ARRAY, Items: REST_PARAM_INT}, value: []any{1.0, "x"}, wantErr: "item 1 should be an integer"},
+		{name: "array length", param: CustomRestHandlerParam{Type: REST_PARAM_ARRAY, Items: REST_PARAM_TEXT, MaxLength: &maxLength}, value: "a,b,c,d", wantErr: "length should be less than or equal to 3"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			value, err := CoerceRestHandlerParam(c.param, c.value)
+			if len(c.wantErr) > 0 {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("expected error %q, got %v", c.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if value != c.want {
+				t.Errorf("expected %v (%T), got %v (%T)", c.want, c.want, value, value)
+			}
+		})
+	}
+}
+
+func TestRestHandlerParamPattern(t *testing.T) {
+	params := []CustomRestHandlerParam{{Name: "code", Type: REST_PARAM_TEXT, Pattern: "^[A-Z]{3}$"}}
+	err := CompileRestHandlerParamPatterns(params)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := CoerceRestHandlerParam(params[0], "ABC"); err != nil {
+		t.Errorf("expected ABC to match, got %v", err)
+	}
+	if _, err := CoerceRestHandlerParam(params[0], "abc"); err == nil {
+		t.Errorf("expected abc to be rejected")
+	}
+
+	invalid := []CustomRestHandlerParam{{Name: "code", Type: REST_PARAM_TEXT, Pattern: "("}}
+	if err := CompileRestHandlerParamPatterns(invalid); err == nil {
+		t.Errorf("expected invalid pattern to be rejected")
+	}
+}
+
+func TestValidateRestHandlerParams(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   CustomRestHandlerInput
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			input: CustomRestHandlerInput{Query: "SELECT :id", Params: []CustomRestHandlerParam{{Name: "id", Type: REST_PARAM_INT}}},
+		},
+		{
+			name:    "undeclared parameter",
+			input:   CustomRestHandlerInput{Query: "SELECT :id, :name", Params: []CustomRestHandlerParam{{Name: "id", Type: REST_PARAM_INT}}},
+			wantErr: "parameter name is used in the query but not declared",
+		},
+		{
+			name:    "unused parameter",
+			input:   CustomRestHandlerInput{Query: "SELECT 1", Params: []CustomRestHandlerParam{{Name: "id", Type: REST_PARAM_INT}}},
+			wantErr: "parameter id is declared but not used in the query",
+		},
+		{
+			name:    "duplicated parameter",
+			input:   CustomRestHandlerInput{Query: "SELECT :id", Params: []CustomRestHandlerParam{{Name: "id", Type: REST_PARAM_INT}, {Name: "id", Type: REST_PARAM_INT}}},
+			wantErr: "parameter id is declared more than once",
+		},
+		{
+			name:    "invalid default",
+			input:   CustomRestHandlerInput{Query: "SELECT :id", Params: []CustomRestHandlerParam{{Name: "id", Type: REST_PARAM_INT, Default: "x"}}},
+			wantErr: "invalid default for parameter id: should be an integer",
+		},
+		{
+			name:    "nested arrays",
+			input:   CustomRestHandlerInput{Query: "SELECT :ids", Params: []CustomRestHandlerParam{{Name: "ids", Type: REST_PARAM_ARRAY, Items: REST_PARAM_ARRAY}}},
+			wantErr: "not supported array items type array for parameter ids",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateRestHandlerParams(c.input)
+			if len(c.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.wantErr {
+				t.Fatalf("expected error %q, got %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGetValidatedRestHandlerArgs(t *testing.T) {
+	input := CustomRestHandlerInput{Params: []CustomRestHandlerParam{
+		{Name: "id", Type: REST_PARAM_INT, Required: true},
+		{Name: "limit", Type: REST_PARAM_INT, Default: 10.0},
+		{Name: "name", Type: REST_PARAM_TEXT},
+	}}
+	values, err := GetValidatedRestHandlerArgs(input, map[string]any{"id": "3"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if values["id"] != int64(3) || values["limit"] != int64(10) || values["name"] != nil {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	_, err = GetValidatedRestHandlerArgs(input, map[string]any{"limit": "x"})
+	var validationError *CustomRestHandlerValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(validationError.Errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", validationError.Errors)
+	}
+}
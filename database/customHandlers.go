@@ -2,21 +2,30 @@ package database
 
 import (
 	"application/environment"
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type CustomRestHandlerInput struct {
-	Database  string `json:"database"`
-	Auth      bool   `json:"auth"`
-	Enabled   bool   `json:"enabled"`
-	Method    string `json:"method"`
-	Endpoint  string `json:"endpoint"`
-	Query     string `json:"query"`
-	Id        any    `json:"id"`
-	CreatedAt string `json:"created_at"`
+	Database    string                    `json:"database"`
+	Auth        bool                      `json:"auth"`
+	Enabled     bool                      `json:"enabled"`
+	Method      string                    `json:"method"`
+	Endpoint    string                    `json:"endpoint"`
+	Query       string                    `json:"query"`
+	Params      []CustomRestHandlerParam  `json:"params"`
+	GraphQL     bool                      `json:"graphql"`
+	GraphQLName string                    `json:"graphql_name"`
+	Columns     []CustomRestHandlerColumn `json:"columns"`
+	Id          any                       `json:"id"`
+	CreatedAt   string                    `json:"created_at"`
 }
 
 var GET string = "GET"
@@ -33,6 +42,120 @@ func FormatQuery(query string) string {
 	return strings.Trim(query, " ")
 }
 
+func FormatMethod(method string) string {
+	return strings.ToUpper(strings.Trim(method, " "))
+}
+
+func isNamedParameterStart(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamedParameterPart(c rune) bool {
+	return isNamedParameterStart(c) || (c >= '0' && c <= '9')
+}
+
+func GetDollarQuoteTag(runes []rune, i int) ([]rune, bool) {
+	j := i + 1
+	for j < len(runes) && runes[j] != '$' {
+		if !isNamedParameterPart(runes[j]) || (j == i+1 && !isNamedParameterStart(runes[j])) {
+			return nil, false
+		}
+		j++
+	}
+	if j == len(runes) {
+		return nil, false
+	}
+	return runes[i : j+1], true
+}
+
+func GetDollarQuoteEnd(runes []rune, tag []rune, start int) int {
+	for j := start; j+len(tag) <= len(runes); j++ {
+		if string(runes[j:j+len(tag)]) == string(tag) {
+			return j + len(tag)
+		}
+	}
+	return len(runes)
+}
+
+func GetBlockCommentEnd(runes []rune, start int) int {
+	depth := 0
+	for j := start; j+1 < len(runes); j++ {
+		if runes[j] == '/' && runes[j+1] == '*' {
+			depth++
+			j++
+		} else if runes[j] == '*' && runes[j+1] == '/' {
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return len(runes)
+}
+
+func CompileNamedQuery(query string) (string, []string) {
+	var builder strings.Builder
+	names := make([]string, 0)
+	indexes := make(map[string]int)
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != c {
+				j++
+			}
+			if j == len(runes) {
+				j--
+			}
+			builder.WriteString(string(runes[i : j+1]))
+			i = j
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			j := i
+			for j < len(runes) && runes[j] != '\n' {
+				j++
+			}
+			builder.WriteString(string(runes[i:j]))
+			i = j - 1
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := GetBlockCommentEnd(runes, i)
+			builder.WriteString(string(runes[i:j]))
+			i = j - 1
+		case c == '$':
+			tag, ok := GetDollarQuoteTag(runes, i)
+			if !ok {
+				builder.WriteRune(c)
+				continue
+			}
+			j := GetDollarQuoteEnd(runes, tag, i+len(tag))
+			builder.WriteString(string(runes[i:j]))
+			i = j - 1
+		case c == ':' && i+1 < len(runes) && runes[i+1] == ':':
+			builder.WriteString("::")
+			i++
+		case c == ':' && i+1 < len(runes) && isNamedParameterStart(runes[i+1]):
+			j := i + 1
+			for j < len(runes) && isNamedParameterPart(runes[j]) {
+				j++
+			}
+			name := string(runes[i+1 : j])
+			index, ok := indexes[name]
+			if !ok {
+				names = append(names, name)
+				index = len(names)
+				indexes[name] = index
+			}
+			builder.WriteString(fmt.Sprintf("$%d", index))
+			i = j - 1
+		default:
+			builder.WriteRune(c)
+		}
+	}
+	return builder.String(), names
+}
+
 func (engine *Engine) ValidateDatabase(input CustomRestHandlerInput) error {
 
 	dbname := FormatDBName(input.Database)
@@ -69,25 +192,240 @@ func (engine *Engine) ValidateMethod(input CustomRestHandlerInput) error {
 }
 
 func (engine *Engine) ValidateEndpoint(input CustomRestHandlerInput) error {
-	pattern := "^/rest(/[a-zA-Z0-9_]+)+$"
+	pattern := "^/rest(/([a-zA-Z0-9_]+|<str:[a-zA-Z_][a-zA-Z0-9_]*>))+$"
 	re := regexp.MustCompile(pattern)
 
 	if !re.MatchString(input.Endpoint) {
-		return fmt.Errorf("endpoint should start with /rest/ and should contain only letters numbers and underscores")
+		return fmt.Errorf("endpoint should start with /rest/ and should contain only letters numbers underscores and <str:name> path parameters")
 	}
 	return nil
 }
 
+func GetEndpointPathParameter(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "<str:") && strings.HasSuffix(segment, ">") {
+		return strings.TrimSuffix(strings.TrimPrefix(segment, "<str:"), ">"), true
+	}
+	return "", false
+}
+
+func MatchRestHandlerEndpoint(endpoint string, path string) (map[string]string, bool) {
+	endpointSegments := strings.Split(strings.Trim(endpoint, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(endpointSegments) != len(pathSegments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range endpointSegments {
+		if name, ok := GetEndpointPathParameter(segment); ok {
+			if len(pathSegments[i]) == 0 {
+				return nil, false
+			}
+			params[name] = pathSegments[i]
+			continue
+		}
+		if segment != pathSegments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (engine *Engine) MatchRestHandler(method string, path string) (CustomRestHandlerInput, map[string]string, bool) {
+	handlers, ok := engine.RestHandlersMap[method]
+	if !ok {
+		return CustomRestHandlerInput{}, nil, false
+	}
+	path = "/" + strings.Trim(path, "/")
+	if handler, ok := handlers[path]; ok {
+		return handler, map[string]string{}, true
+	}
+	endpoints := make([]string, 0, len(handlers))
+	for endpoint := range handlers {
+		endpoints = append(endpoints, endpoint)
+	}
+	SortRestHandlerEndpoints(endpoints)
+	for _, endpoint := range endpoints {
+		params, ok := MatchRestHandlerEndpoint(endpoint, path)
+		if ok {
+			return handlers[endpoint], params, true
+		}
+	}
+	return CustomRestHandlerInput{}, nil, false
+}
+
+// SortRestHandlerEndpoints orders endpoints so that, segment by segment,
+// literals come before path parameters and longer endpoints come first.
+func SortRestHandlerEndpoints(endpoints []string) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a := strings.Split(strings.Trim(endpoints[i], "/"), "/")
+		b := strings.Split(strings.Trim(endpoints[j], "/"), "/")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			_, aIsParam := GetEndpointPathParameter(a[k])
+			_, bIsParam := GetEndpointPathParameter(b[k])
+			if aIsParam != bIsParam {
+				return bIsParam
+			}
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return endpoints[i] < endpoints[j]
+	})
+}
+
+func GetRestHandlerArgValue(value any) (any, error) {
+	switch value.(type) {
+	case map[string]any, []any:
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(jsonValue), nil
+	default:
+		return value, nil
+	}
+}
+
+func (engine *Engine) GetRestHandlerArgs(input CustomRestHandlerInput, params map[string]any) (string, []any, error) {
+	query, names := CompileNamedQuery(input.Query)
+	args := make([]any, 0)
+	if len(input.Params) > 0 {
+		values, err := GetValidatedRestHandlerArgs(input, params)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, name := range names {
+			args = append(args, values[name])
+		}
+		return query, args, nil
+	}
+	if len(names) == 0 {
+		if positional, ok := params["args"]; ok {
+			parsedArgs, err := IsArray(positional)
+			if err != nil {
+				return "", nil, fmt.Errorf("args should be an array")
+			}
+			for _, arg := range parsedArgs {
+				value, err := GetRestHandlerArgValue(arg)
+				if err != nil {
+					return "", nil, err
+				}
+				args = append(args, value)
+			}
+		}
+		return query, args, nil
+	}
+	for _, name := range names {
+		value, err := GetRestHandlerArgValue(params[name])
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, value)
+	}
+	return query, args, nil
+}
+
+func ScanRowsIntoMaps(cb func(func(rows *sql.Rows) error) error) ([]any, error) {
+	results := make([]any, 0)
+	scanner := func(rows *sql.Rows) error {
+		columnTypes, err := rows.ColumnTypes()
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(columnTypes))
+		ptrs := make([]interface{}, len(columnTypes))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		err = rows.Scan(ptrs...)
+		if err != nil {
+			return err
+		}
+		row := make(map[string]any)
+		for i, columnType := range columnTypes {
+			bytes, ok := values[i].([]byte)
+			if !ok {
+				row[columnType.Name()] = values[i]
+				continue
+			}
+			switch columnType.DatabaseTypeName() {
+			case "JSON", "JSONB":
+				var value any
+				err := json.Unmarshal(bytes, &value)
+				if err != nil {
+					return err
+				}
+				row[columnType.Name()] = value
+			default:
+				row[columnType.Name()] = string(bytes)
+			}
+		}
+		results = append(results, row)
+		return nil
+	}
+
+	err := cb(scanner)
+
+	return results, err
+}
+
+func (engine *Engine) ExecuteRestHandler(db *sql.DB, auth jwt.MapClaims, input CustomRestHandlerInput, params map[string]any) ([]any, error) {
+	query, args, err := engine.GetRestHandlerArgs(input, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = engine.SetTransactionRLSPolicyInput(ctx, tx, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := ScanRowsIntoMaps(QueryContext(ctx, tx, query, args...))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (engine *Engine) LoadRestHandlers(db *sql.DB) {
-	query := fmt.Sprintf("SELECT id,method,endpoint,db,query,enabled,auth,created_at FROM %s.engine_rest_actions", environment.GetEnvValue("INTERNAL_SCHEMA_NAME"))
+	query := fmt.Sprintf("SELECT id,method,endpoint,db,query,enabled,auth,created_at,params,graphql,graphql_name FROM %s.engine_rest_actions", environment.GetEnvValue("INTERNAL_SCHEMA_NAME"))
 	var restHandlers []CustomRestHandlerInput = make([]CustomRestHandlerInput, 0)
 	scanner := Query(db, query)
 	cb := func(rows *sql.Rows) error {
 		var restHandler CustomRestHandlerInput
-		err := rows.Scan(&restHandler.Id, &restHandler.Method, &restHandler.Endpoint, &restHandler.Database, &restHandler.Query, &restHandler.Enabled, &restHandler.Auth, &restHandler.CreatedAt)
+		var params []byte
+		var graphql sql.NullBool
+		var graphqlName sql.NullString
+		err := rows.Scan(&restHandler.Id, &restHandler.Method, &restHandler.Endpoint, &restHandler.Database, &restHandler.Query, &restHandler.Enabled, &restHandler.Auth, &restHandler.CreatedAt, &params, &graphql, &graphqlName)
 		if err != nil {
 			panic(err)
 		}
+		restHandler.GraphQL = graphql.Bool
+		restHandler.GraphQLName = graphqlName.String
+		restHandler.Params = make([]CustomRestHandlerParam, 0)
+		if len(params) > 0 {
+			err = json.Unmarshal(params, &restHandler.Params)
+			if err != nil {
+				return err
+			}
+		}
+		err = CompileRestHandlerParamPatterns(restHandler.Params)
+		if err != nil {
+			fmt.Printf("skipping rest handler [%s]: %s: %s\n", restHandler.Method, restHandler.Endpoint, err.Error())
+			return nil
+		}
 		restHandlers = append(restHandlers, restHandler)
 		return err
 	}
@@ -96,6 +434,19 @@ func (engine *Engine) LoadRestHandlers(db *sql.DB) {
 		panic(err)
 	}
 
+	for i, handler := range restHandlers {
+		restHandlers[i].Columns = make([]CustomRestHandlerColumn, 0)
+		if !handler.GraphQL || !handler.Enabled {
+			continue
+		}
+		columns, err := InferRestHandlerColumns(db, handler)
+		if err != nil {
+			fmt.Printf("could not infer result columns for rest handler [%s]: %s: %s\n", handler.Method, handler.Endpoint, err.Error())
+			continue
+		}
+		restHandlers[i].Columns = columns
+	}
+
 	engine.RestHandlers = restHandlers
 	engine.RestHandlersMap = map[string]map[string]CustomRestHandlerInput{}
 	for _, handler := range restHandlers {
@@ -113,6 +464,9 @@ func (engine *Engine) LoadRestHandlers(db *sql.DB) {
 func (engine *Engine) CreateRestHandler(db *sql.DB, customHandlerInput CustomRestHandlerInput) error {
 	customHandlerInput.Database = FormatDBName(customHandlerInput.Database)
 	customHandlerInput.Query = FormatQuery(customHandlerInput.Query)
+	customHandlerInput.Method = FormatMethod(customHandlerInput.Method)
+	customHandlerInput.Params = FormatRestHandlerParams(customHandlerInput.Params)
+	customHandlerInput.GraphQLName = strings.Trim(customHandlerInput.GraphQLName, " ")
 	err := engine.ValidateDatabase(customHandlerInput)
 	if err != nil {
 		return err
@@ -128,13 +482,29 @@ func (engine *Engine) CreateRestHandler(db *sql.DB, customHandlerInput CustomRes
 		return err
 	}
 
-	err = CheckSQLStringValidity(db, customHandlerInput.Query)
+	compiledQuery, _ := CompileNamedQuery(customHandlerInput.Query)
+	err = CheckSQLStringValidity(db, compiledQuery)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateRestHandlerParams(customHandlerInput)
+	if err != nil {
+		return err
+	}
+
+	err = engine.ValidateRestHandlerGraphqlName(customHandlerInput)
+	if err != nil {
+		return err
+	}
+
+	params, err := json.Marshal(customHandlerInput.Params)
 	if err != nil {
 		return err
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s.engine_rest_actions(endpoint,method,db,query,enabled,auth) VALUES($1,$2,$3,$4,$5,$6)", environment.GetEnvValue("INTERNAL_SCHEMA_NAME"))
-	_, err = db.Exec(query, customHandlerInput.Endpoint, customHandlerInput.Method, customHandlerInput.Database, customHandlerInput.Query, customHandlerInput.Enabled, customHandlerInput.Auth)
+	query := fmt.Sprintf("INSERT INTO %s.engine_rest_actions(endpoint,method,db,query,enabled,auth,params,graphql,graphql_name) VALUES($1,$2,$3,$4,$5,$6,$7,$8,NULLIF($9,''))", environment.GetEnvValue("INTERNAL_SCHEMA_NAME"))
+	_, err = db.Exec(query, customHandlerInput.Endpoint, customHandlerInput.Method, customHandlerInput.Database, customHandlerInput.Query, customHandlerInput.Enabled, customHandlerInput.Auth, string(params), customHandlerInput.GraphQL, customHandlerInput.GraphQLName)
 
 	if err != nil {
 		return err
@@ -149,6 +519,9 @@ func (engine *Engine) UpdateRestHandler(db *sql.DB, customHandlerInput CustomRes
 	}
 	customHandlerInput.Database = FormatDBName(customHandlerInput.Database)
 	customHandlerInput.Query = FormatQuery(customHandlerInput.Query)
+	customHandlerInput.Method = FormatMethod(customHandlerInput.Method)
+	customHandlerInput.Params = FormatRestHandlerParams(customHandlerInput.Params)
+	customHandlerInput.GraphQLName = strings.Trim(customHandlerInput.GraphQLName, " ")
 	err := engine.ValidateDatabase(customHandlerInput)
 	if err != nil {
 		return err
@@ -164,13 +537,29 @@ func (engine *Engine) UpdateRestHandler(db *sql.DB, customHandlerInput CustomRes
 		return err
 	}
 
-	err = CheckSQLStringValidity(db, customHandlerInput.Query)
+	compiledQuery, _ := CompileNamedQuery(customHandlerInput.Query)
+	err = CheckSQLStringValidity(db, compiledQuery)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateRestHandlerParams(customHandlerInput)
+	if err != nil {
+		return err
+	}
+
+	err = engine.ValidateRestHandlerGraphqlName(customHandlerInput)
+	if err != nil {
+		return err
+	}
+
+	params, err := json.Marshal(customHandlerInput.Params)
 	if err != nil {
 		return err
 	}
 
-	query := fmt.Sprintf("UPDATE %s.engine_rest_actions SET endpoint = $1, method = $2, db = $3, query = $4, enabled = $5, auth = $6 WHERE id = $7", environment.GetEnvValue("INTERNAL_SCHEMA_NAME"))
-	_, err = db.Exec(query, customHandlerInput.Endpoint, customHandlerInput.Method, customHandlerInput.Database, customHandlerInput.Query, customHandlerInput.Enabled, customHandlerInput.Auth, customHandlerInput.Id)
+	query := fmt.Sprintf("UPDATE %s.engine_rest_actions SET endpoint = $1, method = $2, db = $3, query = $4, enabled = $5, auth = $6, params = $8, graphql = $9, graphql_name = NULLIF($10,'') WHERE id = $7", environment.GetEnvValue("INTERNAL_SCHEMA_NAME"))
+	_, err = db.Exec(query, customHandlerInput.Endpoint, customHandlerInput.Method, customHandlerInput.Database, customHandlerInput.Query, customHandlerInput.Enabled, customHandlerInput.Auth, customHandlerInput.Id, string(params), customHandlerInput.GraphQL, customHandlerInput.GraphQLName)
 
 	if err != nil {
 		return err
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompileNamedQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		want  string
+		names []string
+	}{
+		{
+			name:  "named parameters",
+			query: "SELECT * FROM users WHERE id = :id AND name = :name",
+			want:  "SELECT * FROM users WHERE id = $1 AND name = $2",
+			names: []string{"id", "name"},
+		},
+		{
+			name:  "repeated parameter",
+			query: "SELECT :id, :id",
+			want:  "SELECT $1, $1",
+			names: []string{"id"},
+		},
+		{
+			name:  "casts",
+			query: "SELECT :value::int",
+			want:  "SELECT $1::int",
+			names: []string{"value"},
+		},
+		{
+			name:  "quoted strings",
+			query: `SELECT ':skip', "col:skip" FROM t WHERE a = :a`,
+			want:  `SELECT ':skip', "col:skip" FROM t WHERE a = $1`,
+			names: []string{"a"},
+		},
+		{
+			name:  "line comments",
+			query: "SELECT :a -- :skip\nFROM t",
+			want:  "SELECT $1 -- :skip\nFROM t",
+			names: []string{"a"},
+		},
+		{
+			name:  "block comments",
+			query: "SELECT /* :skip /* :nested */ :skip */ :a",
+			want:  "SELECT /* :skip /* :nested */ :skip */ $1",
+			names: []string{"a"},
+		},
+		{
+			name:  "dollar quoted strings",
+			query: "SELECT $$ :skip $$, $body$ :skip $$ $body$, :a",
+			want:  "SELECT $$ :skip $$, $body$ :skip $$ $body$, $1",
+			names: []string{"a"},
+		},
+		{
+			name:  "positional parameters",
+			query: "SELECT $1, :a",
+			want:  "SELECT $1, $1",
+			names: []string{"a"},
+		},
+		{
+			name:  "unterminated dollar quote",
+			query: "SELECT $tag$ :skip",
+			want:  "SELECT $tag$ :skip",
+			names: []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, names := CompileNamedQuery(c.query)
+			if query != c.want {
+				t.Errorf("expected query %q, got %q", c.want, query)
+			}
+			if !reflect.DeepEqual(names, c.names) {
+				t.Errorf("expected names %v, got %v", c.names, names)
+			}
+		})
+	}
+}
+
+func TestMatchRestHandlerEndpoint(t *testing.T) {
+	cases := []struct {
+		name     string
+		endpoint string
+		path     string
+		params   map[string]string
+		ok       bool
+	}{
+		{name: "static", endpoint: "/users", path: "/users", params: map[string]string{}, ok: true},
+		{name: "trailing slash", endpoint: "/users/", path: "users", params: map[string]string{}, ok: true},
+		{name: "parameter", endpoint: "/users/<str:id>", path: "/users/42", params: map[string]string{"id": "42"}, ok: true},
+		{name: "several parameters", endpoint: "/users/<str:id>/posts/<str:post>", path: "/users/1/posts/2", params: map[string]string{"id": "1", "post": "2"}, ok: true},
+		{name: "different segment", endpoint: "/users/<str:id>", path: "/posts/42", ok: false},
+		{name: "different length", endpoint: "/users/<str:id>", path: "/users/42/posts", ok: false},
+		{name: "empty parameter", endpoint: "/users/<str:id>/posts", path: "/users//posts", ok: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			params, ok := MatchRestHandlerEndpoint(c.endpoint, c.path)
+			if ok != c.ok {
+				t.Fatalf("expected match %t, got %t", c.ok, ok)
+			}
+			if ok && !reflect.DeepEqual(params, c.params) {
+				t.Errorf("expected params %v, got %v", c.params, params)
+			}
+		})
+	}
+}
+
+func TestMatchRestHandler(t *testing.T) {
+	endpoints := []string{
+		"/rest/users/<str:id>/<str:tab>",
+		"/rest/users/<str:id>/posts",
+		"/rest/users/me/<str:tab>",
+		"/rest/users/me/posts",
+		"/rest/<str:section>/me/posts",
+	}
+	engine := &Engine{RestHandlersMap: map[string]map[string]CustomRestHandlerInput{GET: {}}}
+	for _, endpoint := range endpoints {
+		engine.RestHandlersMap[GET][endpoint] = CustomRestHandlerInput{Method: GET, Endpoint: endpoint}
+	}
+	cases := []struct {
+		name     string
+		path     string
+		endpoint string
+		params   map[string]string
+	}{
+		{name: "static endpoint", path: "/rest/users/me/posts", endpoint: "/rest/users/me/posts", params: map[string]string{}},
+		{name: "leftmost literal wins", path: "/rest/users/me/likes", endpoint: "/rest/users/me/<str:tab>", params: map[string]string{"tab": "likes"}},
+		{name: "literal beats parameter", path: "/rest/users/42/posts", endpoint: "/rest/users/<str:id>/posts", params: map[string]string{"id": "42"}},
+		{name: "parameters only", path: "/rest/users/42/likes", endpoint: "/rest/users/<str:id>/<str:tab>", params: map[string]string{"id": "42", "tab": "likes"}},
+		{name: "leading parameter", path: "/rest/groups/me/posts", endpoint: "/rest/<str:section>/me/posts", params: map[string]string{"section": "groups"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				handler, params, ok := engine.MatchRestHandler(GET, c.path)
+				if !ok {
+					t.Fatalf("expected %s to match", c.path)
+				}
+				if handler.Endpoint != c.endpoint {
+					t.Fatalf("expected endpoint %s, got %s", c.endpoint, handler.Endpoint)
+				}
+				if !reflect.DeepEqual(params, c.params) {
+					t.Fatalf("expected params %v, got %v", c.params, params)
+				}
+			}
+		})
+	}
+}
+
+func TestSortRestHandlerEndpoints(t *testing.T) {
+	endpoints := []string{"/rest/<str:a>", "/rest/a/<str:b>", "/rest/a", "/rest/a/b"}
+	SortRestHandlerEndpoints(endpoints)
+	expected := []string{"/rest/a/b", "/rest/a/<str:b>", "/rest/a", "/rest/<str:a>"}
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("expected %v, got %v", expected, endpoints)
+	}
+}
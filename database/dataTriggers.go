@@ -8,16 +8,23 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 var RestDataTrigger string = "REST"
 var GraphQLDataTrigger string = "GRAPHQL"
 
 type DataTriggerPayload struct {
-	Database  string `json:"database"`
-	Table     string `json:"table"`
-	Data      any    `json:"data"`
-	Operation string `json:"operation"`
+	Database  string        `json:"database"`
+	Table     string        `json:"table"`
+	Data      any           `json:"data"`
+	Operation string        `json:"operation"`
+	Old       any           `json:"old"`
+	New       any           `json:"new"`
+	RequestId string        `json:"request_id"`
+	Claims    jwt.MapClaims `json:"claims"`
+	Timestamp string        `json:"timestamp"`
 }
 
 type DataTriggerInput struct {
@@ -26,24 +33,45 @@ type DataTriggerInput struct {
 	Type      string `json:"type"`
 	Operation string `json:"operation"`
 	Auth      string
-	Payload   any `json:"payload"`
+	Payload   any           `json:"payload"`
+	Old       any           `json:"old"`
+	New       any           `json:"new"`
+	RequestId string        `json:"request_id"`
+	Claims    jwt.MapClaims `json:"-"`
+	Timestamp string        `json:"timestamp"`
 }
 
 type TriggerConfig struct {
-	RestEnabled    bool
-	GraphQLEnabled bool
-	InsertEnabled  bool
-	UpdateEnabled  bool
-	DeleteEnabled  bool
-	ErrorEnabled   bool
+	RestEnabled    bool `json:"rest"`
+	GraphQLEnabled bool `json:"graphql"`
+	InsertEnabled  bool `json:"insert"`
+	UpdateEnabled  bool `json:"update"`
+	DeleteEnabled  bool `json:"delete"`
+	ErrorEnabled   bool `json:"error"`
 }
 
 type DataTrigger struct {
-	Id            string
-	CreatedAt     string
-	Database      string
-	Table         string
-	TriggerConfig TriggerConfig
+	Id            string        `json:"id"`
+	CreatedAt     string        `json:"created_at"`
+	Database      string        `json:"database"`
+	Table         string        `json:"table"`
+	TriggerConfig TriggerConfig `json:"trigger_config"`
+}
+
+type DataTriggerConfigInput struct {
+	Id            int64                  `json:"id"`
+	Database      string                 `json:"database"`
+	Table         string                 `json:"table"`
+	TriggerConfig map[string]interface{} `json:"trigger_config"`
+}
+
+var TRIGGER_CONFIG_KEYS = map[string]bool{
+	"insert":  true,
+	"update":  true,
+	"delete":  true,
+	"error":   true,
+	"rest":    true,
+	"graphql": true,
 }
 
 func ParseTriggerConfigBooleanValue(key string, value map[string]interface{}) bool {
@@ -60,29 +88,106 @@ func ParseTriggerConfigBooleanValue(key string, value map[string]interface{}) bo
 	return booleanValue
 }
 
-func (enigne *Engine) CreateDataTrigger(db *sql.DB, input DataTriggerInput) error {
-	// NOT IMPLEMENTED
+func ValidateTriggerConfig(config map[string]interface{}) error {
+	if len(config) == 0 {
+		return fmt.Errorf("trigger_config was not provided")
+	}
+	for key, value := range config {
+		if _, ok := TRIGGER_CONFIG_KEYS[key]; !ok {
+			return fmt.Errorf("not supported trigger_config key %s", key)
+		}
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("trigger_config key %s should be a boolean", key)
+		}
+	}
 	return nil
 }
 
-func (enigne *Engine) DeleteDataTriggerByID(db *sql.DB, input DataTriggerInput) error {
-	// NOT IMPLEMENTED
-	return nil
+func FormatDataTriggerConfigInput(input DataTriggerConfigInput) DataTriggerConfigInput {
+	input.Database = FormatDBName(input.Database)
+	input.Table = strings.ToLower(strings.Trim(input.Table, " "))
+	return input
 }
 
-func (enigne *Engine) DeleteDataTriggerByDatabaseTable(db *sql.DB, input DataTriggerInput) error {
-	// NOT IMPLEMENTED
-	return nil
+func (engine *Engine) GetDataTriggersList() []DataTrigger {
+	dataTriggers := make([]DataTrigger, 0)
+	for _, tables := range engine.DataTriggers {
+		for _, dataTrigger := range tables {
+			dataTriggers = append(dataTriggers, dataTrigger)
+		}
+	}
+	return dataTriggers
 }
 
-func (enigne *Engine) DeleteDataTriggerByDatabase(db *sql.DB, input DataTriggerInput) error {
-	// NOT IMPLEMENTED
-	return nil
+func (engine *Engine) CreateDataTrigger(db *sql.DB, input DataTriggerConfigInput) error {
+	input = FormatDataTriggerConfigInput(input)
+	model, err := engine.GetModelByKey(input.Database, input.Table)
+	if err != nil {
+		return fmt.Errorf("table %s doesn't exist for database %s", input.Table, input.Database)
+	}
+	if model.IsReadOnly() {
+		return fmt.Errorf("%s %s is read-only", input.Database, input.Table)
+	}
+
+	if _, err := engine.GetDatabaseTableDataTrigger(input.Database, input.Table); err == nil {
+		return fmt.Errorf("data trigger for table %s of database %s already exists", input.Table, input.Database)
+	}
+
+	err = ValidateTriggerConfig(input.TriggerConfig)
+	if err != nil {
+		return err
+	}
+
+	triggerConfig, err := json.Marshal(input.TriggerConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(CREATE_DATA_TRIGGER, input.Database, input.Table, string(triggerConfig))
+	return err
 }
 
-func (enigne *Engine) UpdateDataTriggerByID(db *sql.DB, input DataTriggerInput) error {
-	// NOT IMPLEMENTED
-	return nil
+func (engine *Engine) DeleteDataTriggerByID(db *sql.DB, input DataTriggerConfigInput) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("data trigger id was not provided")
+	}
+	result, err := db.Exec(DELETE_DATA_TRIGGER_BY_ID, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("data trigger %d doesn't exist", input.Id))
+}
+
+func (engine *Engine) DeleteDataTriggerByDatabaseTable(db *sql.DB, input DataTriggerConfigInput) error {
+	_, err := db.Exec(DELETE_DATA_TRIGGERS_BY_DATABASE_TABLE_NAME, input.Database, input.Table)
+	return err
+}
+
+func (engine *Engine) DeleteDataTriggerByDatabase(db *sql.DB, input DataTriggerConfigInput) error {
+	_, err := db.Exec(DELETE_DATA_TRIGGERS_BY_DATABASE_NAME, input.Database)
+	return err
+}
+
+func (engine *Engine) UpdateDataTriggerByID(db *sql.DB, input DataTriggerConfigInput) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("data trigger id was not provided")
+	}
+
+	err := ValidateTriggerConfig(input.TriggerConfig)
+	if err != nil {
+		return err
+	}
+
+	triggerConfig, err := json.Marshal(input.TriggerConfig)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.Exec(UPDATE_DATA_TRIGGER_BY_ID, string(triggerConfig), input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("data trigger %d doesn't exist", input.Id))
 }
 
 func (enigne *Engine) LoadDataTriggers(db *sql.DB) map[string]map[string]DataTrigger {
@@ -156,7 +261,10 @@ func (engine *Engine) HandleEvent(input DataTriggerInput) {
 
 	switch engine.DataTriggerProtocol {
 	case "WEBSOCKET":
-		engine.WebSocketEvent(input)
+		err := engine.EventBus.Publish(input)
+		if err != nil {
+			fmt.Println(err)
+		}
 	case "HTTP":
 		engine.PostEvent(input)
 	default:
@@ -172,6 +280,11 @@ func (engine *Engine) PostEvent(input DataTriggerInput) {
 		Table:     input.Table,
 		Data:      input.Payload,
 		Operation: strings.ToLower(input.Operation),
+		Old:       input.Old,
+		New:       input.New,
+		RequestId: input.RequestId,
+		Claims:    input.Claims,
+		Timestamp: input.Timestamp,
 	}
 
 	jsonBody, err := json.Marshal(payload)
@@ -201,8 +314,12 @@ func (engine *Engine) PostEvent(input DataTriggerInput) {
 	defer res.Body.Close()
 }
 
+func GetDataTriggerEventName(database string, table string, operation string) string {
+	return fmt.Sprintf("%s.%s.%s", database, table, operation)
+}
+
 func (engine *Engine) WebSocketEvent(input DataTriggerInput) {
-	engine.EventEmitter.Emit(input.Operation, input)
+	engine.EventEmitter.Emit(GetDataTriggerEventName(input.Database, input.Table, input.Operation), input)
 }
 
 func (engine *Engine) ExecuteDataTrigger(dataTriggerInput DataTriggerInput) {
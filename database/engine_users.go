@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,10 +11,10 @@ import (
 )
 
 type EngineRole struct {
-	Id          int    `json:"id"`
-	RoleName    string `json:"role_name"`
-	CreatedAt   string `json:"created_at"`
-	Permissions []byte `json:"permissions"`
+	Id          int             `json:"id"`
+	RoleName    string          `json:"role_name"`
+	CreatedAt   string          `json:"created_at"`
+	Permissions json.RawMessage `json:"permissions"`
 }
 
 type EngineUserInput struct {
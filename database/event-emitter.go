@@ -1,19 +1,120 @@
 package database
 
-import "github.com/google/uuid"
+import (
+	"application/environment"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+var EVENT_OVERFLOW_DROP string = "DROP"
+var EVENT_OVERFLOW_BLOCK string = "BLOCK"
+var EVENT_OVERFLOW_DISCONNECT string = "DISCONNECT"
 
 type Listener struct {
-	id      string
-	handler func(args ...any)
+	id        string
+	eventName string
+	handler   func(args ...any)
+	internal  bool
+	events    chan []any
+	done      chan struct{}
+	closeOnce sync.Once
+	delivered atomic.Int64
+	dropped   atomic.Int64
+}
+
+type ListenerMetrics struct {
+	Id        string `json:"id"`
+	EventName string `json:"event_name"`
+	Pending   int    `json:"pending"`
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"`
+	Internal  bool   `json:"internal"`
+}
+
+type EventEmitterMetrics struct {
+	OverflowPolicy string            `json:"overflow_policy"`
+	BufferSize     int               `json:"buffer_size"`
+	Emitted        int64             `json:"emitted"`
+	Delivered      int64             `json:"delivered"`
+	Dropped        int64             `json:"dropped"`
+	Disconnected   int64             `json:"disconnected"`
+	Listeners      []ListenerMetrics `json:"listeners"`
 }
 
 type EventEmitter struct {
-	EventMap map[string]map[string]Listener
+	EventMap       map[string]map[string]*Listener
+	BufferSize     int
+	OverflowPolicy string
+	mutex          sync.RWMutex
+	emitted        atomic.Int64
+	dropped        atomic.Int64
+	disconnected   atomic.Int64
+	delivered      atomic.Int64
+}
+
+func GetEventEmitterOverflowPolicy() string {
+	policy := strings.ToUpper(environment.GetEnvValueToStringWithDefault("EVENT_EMITTER_OVERFLOW_POLICY", EVENT_OVERFLOW_DROP))
+	switch policy {
+	case EVENT_OVERFLOW_DROP, EVENT_OVERFLOW_BLOCK, EVENT_OVERFLOW_DISCONNECT:
+		return policy
+	default:
+		fmt.Printf("not supported event emitter overflow policy %s, falling back to %s\n", policy, EVENT_OVERFLOW_DROP)
+		return EVENT_OVERFLOW_DROP
+	}
 }
 
 func NewEventEmitter() *EventEmitter {
+	bufferSize := environment.GetEnvValueToIntWithDefault("EVENT_EMITTER_BUFFER_SIZE", 256)
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
 	return &EventEmitter{
-		EventMap: make(map[string]map[string]Listener),
+		EventMap:       make(map[string]map[string]*Listener),
+		BufferSize:     bufferSize,
+		OverflowPolicy: GetEventEmitterOverflowPolicy(),
+	}
+}
+
+func (emitter *EventEmitter) NewListener(eventName string, handler func(args ...any)) *Listener {
+	return &Listener{
+		id:        emitter.MakeUniqueListenerId(),
+		eventName: eventName,
+		handler:   handler,
+		events:    make(chan []any, emitter.BufferSize),
+		done:      make(chan struct{}),
+	}
+}
+
+func (listener *Listener) stop() {
+	listener.closeOnce.Do(func() {
+		close(listener.done)
+	})
+}
+
+func (listener *Listener) handle(emitter *EventEmitter, args []any) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("event listener %s for %s panicked: %v\n", listener.id, listener.eventName, r)
+		}
+	}()
+	listener.handler(args...)
+	listener.delivered.Add(1)
+	emitter.delivered.Add(1)
+}
+
+func (listener *Listener) run(emitter *EventEmitter) {
+	for {
+		select {
+		case <-listener.done:
+			return
+		case args := <-listener.events:
+			listener.handle(emitter, args)
+		}
 	}
 }
 
@@ -21,62 +122,178 @@ func (emitter *EventEmitter) MakeUniqueListenerId() string {
 	return uuid.New().String()
 }
 
+func MatchEventName(pattern string, eventName string) bool {
+	if pattern == eventName {
+		return true
+	}
+	if !strings.Contains(pattern, "*") {
+		return false
+	}
+	matched, err := path.Match(strings.ReplaceAll(pattern, ".", "/"), strings.ReplaceAll(eventName, ".", "/"))
+	return err == nil && matched
+}
+
 func (emitter *EventEmitter) EventNameSpaceExists(eventName string) bool {
+	emitter.mutex.RLock()
+	defer emitter.mutex.RUnlock()
 	_, ok := emitter.EventMap[eventName]
 	return ok
 }
 
 func (emitter *EventEmitter) EventNameSpaceListenerExists(eventName string, listenerId string) bool {
-	if emitter.EventNameSpaceExists(eventName) {
-		_, ok := emitter.EventMap[eventName][listenerId]
+	emitter.mutex.RLock()
+	defer emitter.mutex.RUnlock()
+	if listeners, ok := emitter.EventMap[eventName]; ok {
+		_, ok := listeners[listenerId]
 		return ok
 	}
 
 	return false
 }
 
-func (emitter *EventEmitter) AddListener(eventName string, listener Listener) {
-
+func (emitter *EventEmitter) AddListener(eventName string, listener *Listener) {
+	emitter.mutex.Lock()
+	defer emitter.mutex.Unlock()
+	if _, ok := emitter.EventMap[eventName]; !ok {
+		emitter.EventMap[eventName] = make(map[string]*Listener)
+	}
+	listener.eventName = eventName
+	emitter.EventMap[eventName][listener.id] = listener
+	go listener.run(emitter)
 }
 
 func (emitter *EventEmitter) RemoveListener(eventName, listenerId string) {
-	if emitter.EventNameSpaceListenerExists(eventName, listenerId) {
-		delete(emitter.EventMap[eventName], listenerId)
+	emitter.mutex.Lock()
+	defer emitter.mutex.Unlock()
+	listeners, ok := emitter.EventMap[eventName]
+	if !ok {
+		return
+	}
+	if listener, ok := listeners[listenerId]; ok {
+		listener.stop()
+		delete(listeners, listenerId)
+	}
+	if len(listeners) == 0 {
+		delete(emitter.EventMap, eventName)
 	}
 }
 
 func (emitter *EventEmitter) RemoveAllListeners(eventName string) {
-	if emitter.EventNameSpaceExists(eventName) {
-		delete(emitter.EventMap, eventName)
+	emitter.mutex.Lock()
+	defer emitter.mutex.Unlock()
+	for _, listener := range emitter.EventMap[eventName] {
+		listener.stop()
 	}
+	delete(emitter.EventMap, eventName)
 }
 
-func (emitter *EventEmitter) Emit(eventName string, args ...any) {
-	if emitter.EventNameSpaceExists(eventName) {
-		listeners := emitter.EventMap[eventName]
-		if len(listeners) == 0 {
-			return
+func (emitter *EventEmitter) RemoveAll() {
+	emitter.mutex.Lock()
+	defer emitter.mutex.Unlock()
+	for eventName, listeners := range emitter.EventMap {
+		for _, listener := range listeners {
+			listener.stop()
 		}
+		delete(emitter.EventMap, eventName)
+	}
+}
 
+func (emitter *EventEmitter) GetMatchingListeners(eventName string) []*Listener {
+	emitter.mutex.RLock()
+	defer emitter.mutex.RUnlock()
+	matching := make([]*Listener, 0)
+	for pattern, listeners := range emitter.EventMap {
+		if !MatchEventName(pattern, eventName) {
+			continue
+		}
 		for _, listener := range listeners {
-			listener.handler(args...)
+			matching = append(matching, listener)
 		}
 	}
+	return matching
 }
 
-func (emitter *EventEmitter) Subscribe(eventName string, listener func(args ...any)) func() {
-	if _, ok := emitter.EventMap[eventName]; !ok {
-		emitter.EventMap[eventName] = make(map[string]Listener)
+func (emitter *EventEmitter) deliver(listener *Listener, args []any) {
+	select {
+	case <-listener.done:
+		return
+	case listener.events <- args:
+		return
+	default:
 	}
 
-	listenerId := emitter.MakeUniqueListenerId()
+	switch emitter.OverflowPolicy {
+	case EVENT_OVERFLOW_BLOCK:
+		select {
+		case <-listener.done:
+		case listener.events <- args:
+		}
+	case EVENT_OVERFLOW_DISCONNECT:
+		if listener.internal {
+			listener.dropped.Add(1)
+			emitter.dropped.Add(1)
+			return
+		}
+		listener.dropped.Add(1)
+		emitter.dropped.Add(1)
+		emitter.disconnected.Add(1)
+		fmt.Printf("event listener %s for %s was disconnected due to buffer overflow\n", listener.id, listener.eventName)
+		emitter.RemoveListener(listener.eventName, listener.id)
+	default:
+		listener.dropped.Add(1)
+		emitter.dropped.Add(1)
+	}
+}
 
-	emitter.EventMap[eventName][listenerId] = Listener{
-		id:      listenerId,
-		handler: listener,
+func (emitter *EventEmitter) Emit(eventName string, args ...any) {
+	emitter.emitted.Add(1)
+	for _, listener := range emitter.GetMatchingListeners(eventName) {
+		emitter.deliver(listener, args)
 	}
+}
+
+func (emitter *EventEmitter) Subscribe(eventName string, listener func(args ...any)) func() {
+	entry := emitter.NewListener(eventName, listener)
+	emitter.AddListener(eventName, entry)
 
 	return func() {
-		emitter.RemoveListener(eventName, listenerId)
+		emitter.RemoveListener(eventName, entry.id)
+	}
+}
+
+func (emitter *EventEmitter) SubscribeInternal(eventName string, listener func(args ...any)) func() {
+	entry := emitter.NewListener(eventName, listener)
+	entry.internal = true
+	emitter.AddListener(eventName, entry)
+
+	return func() {
+		emitter.RemoveListener(eventName, entry.id)
+	}
+}
+
+func (emitter *EventEmitter) Metrics() EventEmitterMetrics {
+	emitter.mutex.RLock()
+	defer emitter.mutex.RUnlock()
+	metrics := EventEmitterMetrics{
+		OverflowPolicy: emitter.OverflowPolicy,
+		BufferSize:     emitter.BufferSize,
+		Emitted:        emitter.emitted.Load(),
+		Delivered:      emitter.delivered.Load(),
+		Dropped:        emitter.dropped.Load(),
+		Disconnected:   emitter.disconnected.Load(),
+		Listeners:      make([]ListenerMetrics, 0),
+	}
+	for eventName, listeners := range emitter.EventMap {
+		for _, listener := range listeners {
+			metrics.Listeners = append(metrics.Listeners, ListenerMetrics{
+				Id:        listener.id,
+				EventName: eventName,
+				Pending:   len(listener.events),
+				Delivered: listener.delivered.Load(),
+				Dropped:   listener.dropped.Load(),
+				Internal:  listener.internal,
+			})
+		}
 	}
+	return metrics
 }
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMatchEventName(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		eventName string
+		want      bool
+	}{
+		{pattern: "db.users.insert", eventName: "db.users.insert", want: true},
+		{pattern: "db.users.insert", eventName: "db.users.update", want: false},
+		{pattern: "db.users.*", eventName: "db.users.insert", want: true},
+		{pattern: "db.*.insert", eventName: "db.posts.insert", want: true},
+		{pattern: "db.*.insert", eventName: "db.posts.update", want: false},
+		{pattern: "db.*", eventName: "db.users.insert", want: false},
+		{pattern: "*", eventName: "users", want: true},
+		{pattern: "*", eventName: "db.users", want: false},
+		{pattern: "db.users_*.insert", eventName: "db.users_archive.insert", want: true},
+		{pattern: "db.[.insert", eventName: "db.[.insert", want: true},
+		{pattern: "db.[*.insert", eventName: "db.[a.insert", want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.pattern+" "+c.eventName, func(t *testing.T) {
+			if got := MatchEventName(c.pattern, c.eventName); got != c.want {
+				t.Errorf("expected %t, got %t", c.want, got)
+			}
+		})
+	}
+}
+
+func NewTestEventEmitter(policy string, bufferSize int) *EventEmitter {
+	return &EventEmitter{
+		EventMap:       make(map[string]map[string]*Listener),
+		BufferSize:     bufferSize,
+		OverflowPolicy: policy,
+	}
+}
+
+func WaitForEvents(t *testing.T, events chan any, count int) []any {
+	received := make([]any, 0, count)
+	for len(received) < count {
+		select {
+		case event := <-events:
+			received = append(received, event)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %d events, got %d", count, len(received))
+		}
+	}
+	return received
+}
+
+func TestEventEmitterOverflowPolicies(t *testing.T) {
+	cases := []struct {
+		policy       string
+		internal     bool
+		delivered    int
+		maxDelivered int
+		dropped      int64
+		disconnected int64
+		subscribed   bool
+	}{
+		{policy: EVENT_OVERFLOW_DROP, delivered: 2, maxDelivered: 2, dropped: 2, subscribed: true},
+		{policy: EVENT_OVERFLOW_BLOCK, delivered: 4, maxDelivered: 4, subscribed: true},
+		{policy: EVENT_OVERFLOW_DISCONNECT, delivered: 1, maxDelivered: 2, dropped: 1, disconnected: 1, subscribed: false},
+		{policy: EVENT_OVERFLOW_DISCONNECT, internal: true, delivered: 2, maxDelivered: 2, dropped: 2, subscribed: true},
+	}
+	for _, c := range cases {
+		name := c.policy
+		if c.internal {
+			name += " internal"
+		}
+		t.Run(name, func(t *testing.T) {
+			emitter := NewTestEventEmitter(c.policy, 1)
+			release := make(chan struct{})
+			events := make(chan any, 10)
+			handler := func(args ...any) {
+				<-release
+				events <- args[0]
+			}
+			subscribe := emitter.Subscribe
+			if c.internal {
+				subscribe = emitter.SubscribeInternal
+			}
+			unsubscribe := subscribe("db.users.*", handler)
+			defer unsubscribe()
+
+			emitter.Emit("db.users.insert", 1)
+			deadline := time.Now().Add(2 * time.Second)
+			for len(emitter.Metrics().Listeners) > 0 && emitter.Metrics().Listeners[0].Pending > 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			emitter.Emit("db.users.insert", 2)
+
+			done := make(chan struct{})
+			go func() {
+				emitter.Emit("db.users.insert", 3)
+				emitter.Emit("db.users.insert", 4)
+				close(done)
+			}()
+			if c.policy != EVENT_OVERFLOW_BLOCK {
+				<-done
+			}
+			close(release)
+			<-done
+
+			received := WaitForEvents(t, events, c.delivered)
+			time.Sleep(50 * time.Millisecond)
+			received = append(received, WaitForEvents(t, events, len(events))...)
+			if len(received) > c.maxDelivered {
+				t.Errorf("expected at most %d events, got %v", c.maxDelivered, received)
+			}
+
+			metrics := emitter.Metrics()
+			if metrics.Dropped != c.dropped {
+				t.Errorf("expected %d dropped events, got %d", c.dropped, metrics.Dropped)
+			}
+			if metrics.Disconnected != c.disconnected {
+				t.Errorf("expected %d disconnected listeners, got %d", c.disconnected, metrics.Disconnected)
+			}
+			if subscribed := len(metrics.Listeners) == 1; subscribed != c.subscribed {
+				t.Errorf("expected subscribed %t, got %t", c.subscribed, subscribed)
+			}
+		})
+	}
+}
+
+func TestEventEmitterConcurrentUse(t *testing.T) {
+	for _, policy := range []string{EVENT_OVERFLOW_DROP, EVENT_OVERFLOW_BLOCK, EVENT_OVERFLOW_DISCONNECT} {
+		t.Run(policy, func(t *testing.T) {
+			emitter := NewTestEventEmitter(policy, 4)
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						unsubscribe := emitter.Subscribe("db.*.insert", func(args ...any) {})
+						emitter.Emit("db.users.insert", j)
+						emitter.Metrics()
+						unsubscribe()
+					}
+				}()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 50; j++ {
+						emitter.Emit("db.posts.insert", j)
+					}
+				}()
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					emitter.RemoveAllListeners("db.*.insert")
+				}
+			}()
+			wg.Wait()
+			emitter.RemoveAll()
+			if len(emitter.Metrics().Listeners) != 0 {
+				t.Errorf("expected no listeners, got %v", emitter.Metrics().Listeners)
+			}
+		})
+	}
+}
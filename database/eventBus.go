@@ -0,0 +1,199 @@
+package database
+
+import (
+	"application/environment"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var LOCAL_EVENT_BUS string = "LOCAL"
+var POSTGRES_EVENT_BUS string = "POSTGRES"
+
+var EVENT_BUS_MAX_NOTIFY_PAYLOAD int = 7900
+
+type EventBus interface {
+	Start() error
+	Publish(input DataTriggerInput) error
+	Close() error
+}
+
+type EventBusMessage struct {
+	InstanceId string            `json:"instance_id"`
+	PayloadId  int64             `json:"payload_id,omitempty"`
+	Event      *DataTriggerInput `json:"event,omitempty"`
+	Claims     jwt.MapClaims     `json:"claims,omitempty"`
+}
+
+type LocalEventBus struct {
+	handler func(input DataTriggerInput)
+}
+
+type PostgresEventBus struct {
+	db         *sql.DB
+	instanceId string
+	handler    func(input DataTriggerInput)
+	listener   *pq.Listener
+	done       chan struct{}
+}
+
+func NewEventBus(db *sql.DB, handler func(input DataTriggerInput)) EventBus {
+	bus := strings.ToUpper(environment.GetEnvValueToStringWithDefault("EVENT_BUS", LOCAL_EVENT_BUS))
+	switch bus {
+	case POSTGRES_EVENT_BUS:
+		return NewPostgresEventBus(db, handler)
+	case LOCAL_EVENT_BUS:
+		return NewLocalEventBus(handler)
+	default:
+		fmt.Printf("not supported event bus %s, falling back to %s\n", bus, LOCAL_EVENT_BUS)
+		return NewLocalEventBus(handler)
+	}
+}
+
+func NewLocalEventBus(handler func(input DataTriggerInput)) *LocalEventBus {
+	return &LocalEventBus{handler: handler}
+}
+
+func (bus *LocalEventBus) Start() error {
+	return nil
+}
+
+func (bus *LocalEventBus) Publish(input DataTriggerInput) error {
+	bus.handler(input)
+	return nil
+}
+
+func (bus *LocalEventBus) Close() error {
+	return nil
+}
+
+func NewPostgresEventBus(db *sql.DB, handler func(input DataTriggerInput)) *PostgresEventBus {
+	return &PostgresEventBus{
+		db:         db,
+		instanceId: uuid.New().String(),
+		handler:    handler,
+		done:       make(chan struct{}),
+	}
+}
+
+func (bus *PostgresEventBus) Start() error {
+	connStr := environment.GetEnvValue("CONNECTION_STRING")
+	bus.listener = pq.NewListener(connStr, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+
+	err := bus.listener.Listen(EVENT_BUS_CHANNEL)
+	if err != nil {
+		bus.listener.Close()
+		return err
+	}
+
+	retention := environment.GetEnvValueToIntWithDefault("EVENT_BUS_PAYLOAD_RETENTION_IN_SECONDS", 300)
+	cleanup := time.NewTicker(time.Duration(retention) * time.Second)
+	go func() {
+		defer cleanup.Stop()
+		for {
+			select {
+			case <-bus.done:
+				return
+			case notification := <-bus.listener.Notify:
+				if notification == nil {
+					continue
+				}
+				bus.HandleNotification(notification.Extra)
+			case <-cleanup.C:
+				_, err := bus.db.Exec(DELETE_EXPIRED_EVENT_BUS_PAYLOADS, retention)
+				if err != nil {
+					fmt.Println(err)
+				}
+			case <-time.After(90 * time.Second):
+				go bus.listener.Ping()
+			}
+		}
+	}()
+	return nil
+}
+
+func NewEventBusMessage(instanceId string, input DataTriggerInput) EventBusMessage {
+	input.Auth = ""
+	return EventBusMessage{InstanceId: instanceId, Event: &input, Claims: input.Claims}
+}
+
+func (message EventBusMessage) GetEvent() DataTriggerInput {
+	event := *message.Event
+	event.Claims = message.Claims
+	return event
+}
+
+func (bus *PostgresEventBus) Publish(input DataTriggerInput) error {
+	bus.handler(input)
+
+	message := NewEventBusMessage(bus.instanceId, input)
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > EVENT_BUS_MAX_NOTIFY_PAYLOAD {
+		message = EventBusMessage{InstanceId: bus.instanceId}
+		err = bus.db.QueryRow(CREATE_EVENT_BUS_PAYLOAD, string(payload)).Scan(&message.PayloadId)
+		if err != nil {
+			return err
+		}
+		payload, err = json.Marshal(message)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = bus.db.Exec(PUBLISH_EVENT_BUS_MESSAGE, EVENT_BUS_CHANNEL, string(payload))
+	return err
+}
+
+func (bus *PostgresEventBus) HandleNotification(payload string) {
+	var message EventBusMessage
+	err := json.Unmarshal([]byte(payload), &message)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if message.InstanceId == bus.instanceId {
+		return
+	}
+
+	if message.Event == nil {
+		var event string
+		err := bus.db.QueryRow(GET_EVENT_BUS_PAYLOAD, message.PayloadId).Scan(&event)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		err = json.Unmarshal([]byte(event), &message)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if message.Event == nil {
+			fmt.Printf("event bus payload %d has no event\n", message.PayloadId)
+			return
+		}
+	}
+
+	bus.handler(message.GetEvent())
+}
+
+func (bus *PostgresEventBus) Close() error {
+	close(bus.done)
+	if bus.listener == nil {
+		return nil
+	}
+	return bus.listener.Close()
+}
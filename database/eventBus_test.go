@@ -0,0 +1,43 @@
+package database
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestEventBusMessageKeepsClaims(t *testing.T) {
+	claims := jwt.MapClaims{"sub": "42", "role": "editor"}
+	input := DataTriggerInput{Database: "public", Table: "posts", Operation: INSERT_OPERATION, Auth: "token", Claims: claims}
+	payload, err := json.Marshal(NewEventBusMessage("sender", input))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	var received []DataTriggerInput
+	bus := NewPostgresEventBus(nil, func(input DataTriggerInput) {
+		received = append(received, input)
+	})
+	bus.HandleNotification(string(payload))
+	if len(received) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(received))
+	}
+	if !reflect.DeepEqual(received[0].Claims, claims) {
+		t.Errorf("expected claims %v, got %v", claims, received[0].Claims)
+	}
+	if received[0].Auth != "" {
+		t.Errorf("expected auth to be dropped, got %q", received[0].Auth)
+	}
+
+	event, err := json.Marshal(received[0])
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var decoded map[string]any
+	json.Unmarshal(event, &decoded)
+	if _, ok := decoded["claims"]; ok {
+		t.Errorf("expected claims to stay out of the event payload, got %s", event)
+	}
+}
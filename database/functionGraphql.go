@@ -0,0 +1,153 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var MODEL_GRAPHQL_SUFFIXES = []string{"", "_aggregate", PAGINATED_SUFFIX, "_connection", "_insert", "_update", "_delete"}
+
+func GetFunctionGraphqlName(function DatabaseFunction) string {
+	return fmt.Sprintf("%s_%s", function.Database, function.Name)
+}
+
+func GetFunctionGraphqlArgsTypeName(function DatabaseFunction) string {
+	return fmt.Sprintf("%s_args", GetFunctionGraphqlName(function))
+}
+
+func GetGraphqlTypeByFunctionTypeName(typeName string) string {
+	if strings.HasPrefix(typeName, "_") {
+		return fmt.Sprintf("[%s]", GetGraphqlTypeByDatabaseTypeName(strings.TrimPrefix(typeName, "_")))
+	}
+	if typeName == "VOID" {
+		return "Object"
+	}
+	return GetGraphqlTypeByDatabaseTypeName(typeName)
+}
+
+func (e *Engine) IsFunctionGraphqlNameAvailable(function DatabaseFunction) bool {
+	for table := range e.DatabaseToTableToModelMap[function.Database] {
+		if GetFunctionGraphqlArgsTypeName(function) == fmt.Sprintf("%s_%s", function.Database, table) {
+			return false
+		}
+		for _, suffix := range MODEL_GRAPHQL_SUFFIXES {
+			if function.Name == table+suffix {
+				return false
+			}
+		}
+	}
+	name := GetFunctionGraphqlName(function)
+	for _, handler := range e.GetGraphqlRestHandlers() {
+		if GetRestHandlerGraphqlName(handler) == name {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Engine) GetGraphqlFunctions() []DatabaseFunction {
+	functions := make([]DatabaseFunction, 0)
+	for _, function := range e.GetFunctionsList() {
+		if e.IsFunctionGraphqlNameAvailable(function) {
+			functions = append(functions, function)
+		}
+	}
+	return functions
+}
+
+func BuildFunctionGraphqlArgsType(function DatabaseFunction) string {
+	if len(function.Args) == 0 {
+		return ""
+	}
+	fields := make([]string, 0)
+	for _, arg := range function.Args {
+		argType := GetGraphqlTypeByFunctionTypeName(arg.TypeName)
+		if !arg.HasDefault {
+			argType += "!"
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s", arg.Name, argType))
+	}
+	return fmt.Sprintf("input %s {\n%s\n}", GetFunctionGraphqlArgsTypeName(function), strings.Join(fields, "\n"))
+}
+
+func (e *Engine) BuildFunctionGraphqlTypes() []string {
+	types := make([]string, 0)
+	for _, function := range e.GetGraphqlFunctions() {
+		argsType := BuildFunctionGraphqlArgsType(function)
+		if len(argsType) > 0 {
+			types = append(types, argsType)
+		}
+	}
+	return types
+}
+
+func (e *Engine) BuildFunctionGraphqlField(function DatabaseFunction) string {
+	args := make([]string, 0)
+	if len(function.Args) > 0 {
+		argsType := GetFunctionGraphqlArgsTypeName(function)
+		for _, arg := range function.Args {
+			if !arg.HasDefault {
+				argsType += "!"
+				break
+			}
+		}
+		args = append(args, fmt.Sprintf("args: %s", argsType))
+	}
+
+	returnType := GetGraphqlTypeByFunctionTypeName(function.ReturnType)
+	if function.ReturnsRow {
+		returnType = "Object"
+	}
+	if function.ReturnsSet {
+		returnType = fmt.Sprintf("[%s]", returnType)
+	}
+	if model, err := e.GetFunctionModel(function); err == nil {
+		args = append(args, strings.TrimSuffix(strings.TrimPrefix(BuildSelectTypeArgs(model), "("), ")"))
+		returnType = fmt.Sprintf("[%s_%s!]", model.Database, model.Table)
+	}
+
+	fieldArgs := ""
+	if len(args) > 0 {
+		fieldArgs = fmt.Sprintf("(%s)", strings.Join(args, ", "))
+	}
+	return fmt.Sprintf("%s%s: %s", GetFunctionGraphqlName(function), fieldArgs, returnType)
+}
+
+func (e *Engine) BuildFunctionGraphqlFields(isQuery bool) []string {
+	fields := make([]string, 0)
+	for _, function := range e.GetGraphqlFunctions() {
+		if function.IsMutation() == isQuery {
+			continue
+		}
+		fields = append(fields, e.BuildFunctionGraphqlField(function))
+	}
+	return fields
+}
+
+func (e *Engine) ResolveGraphqlFunction(config EngineGraphQlDatabaseTableConfig, value any, auth jwt.MapClaims, db *sql.DB) (any, error) {
+	if config.Function == nil {
+		return nil, fmt.Errorf("no such relation")
+	}
+	err := CanAccess(config, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(map[string]any)
+	selectBody := make(map[string]any)
+	parsedValue, err := IsMapToInterface(value)
+	if err == nil {
+		for key, entry := range parsedValue {
+			selectBody[key] = entry
+		}
+	}
+	if functionArgs, err := IsMapToInterface(selectBody[FUNCTION_ARGS_KEY]); err == nil {
+		args = functionArgs
+	}
+	delete(selectBody, FUNCTION_ARGS_KEY)
+
+	return e.ExecuteFunction(auth, db, *config.Function, args, selectBody, true)
+}
@@ -0,0 +1,458 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lib/pq"
+)
+
+var GRAPHQL_FUNCTION string = "FUNCTION"
+var FUNCTION_ARGS_KEY string = "args"
+
+var FUNCTION_VOLATILE string = "VOLATILE"
+var FUNCTION_STABLE string = "STABLE"
+var FUNCTION_IMMUTABLE string = "IMMUTABLE"
+
+var FUNCTION_VOLATILITY = map[string]string{
+	"v": FUNCTION_VOLATILE,
+	"s": FUNCTION_STABLE,
+	"i": FUNCTION_IMMUTABLE,
+}
+
+type DatabaseFunctionArg struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	TypeName   string `json:"type_name"`
+	HasDefault bool   `json:"has_default"`
+}
+
+type DatabaseFunction struct {
+	Database    string                `json:"database"`
+	Name        string                `json:"name"`
+	Args        []DatabaseFunctionArg `json:"args"`
+	ReturnType  string                `json:"return_type"`
+	ReturnsSet  bool                  `json:"returns_set"`
+	ReturnsRow  bool                  `json:"returns_row"`
+	ReturnTable string                `json:"return_table"`
+	Volatility  string                `json:"volatility"`
+	Roles       []string              `json:"roles"`
+}
+
+type ExposedFunction struct {
+	Id        int64    `json:"id"`
+	Database  string   `json:"database"`
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles"`
+	CreatedAt string   `json:"created_at"`
+}
+
+func GetDatabaseFunctions(db *sql.DB, database string) ([]DatabaseFunction, error) {
+	functions := make([]DatabaseFunction, 0)
+	scanner := Query(db, GET_DATABASE_FUNCTIONS, database)
+	cb := func(rows *sql.Rows) error {
+		function := DatabaseFunction{Database: database}
+		var volatility string
+		var defaults int
+		var args []byte
+		err := rows.Scan(&function.Name, &volatility, &function.ReturnsSet, &function.ReturnType, &function.ReturnsRow, &function.ReturnTable, &defaults, &args)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(args, &function.Args)
+		if err != nil {
+			return err
+		}
+		for i := range function.Args {
+			function.Args[i].HasDefault = i >= len(function.Args)-defaults
+		}
+		function.Volatility = FUNCTION_VOLATILITY[volatility]
+		functions = append(functions, function)
+		return nil
+	}
+	err := scanner(cb)
+	return functions, err
+}
+
+func IsFunctionExposable(function DatabaseFunction) bool {
+	if !GRAPHQL_NAME_PATTERN.MatchString(function.Name) || function.Name != strings.ToLower(function.Name) {
+		return false
+	}
+	for _, arg := range function.Args {
+		if !GRAPHQL_NAME_PATTERN.MatchString(arg.Name) || arg.Name != strings.ToLower(arg.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func GetEngineExposedFunctions(db *sql.DB) ([]ExposedFunction, error) {
+	exposedFunctions := make([]ExposedFunction, 0)
+	scanner := Query(db, GET_ENGINE_EXPOSED_FUNCTIONS)
+	cb := func(rows *sql.Rows) error {
+		var exposedFunction ExposedFunction
+		var roles []byte
+		err := rows.Scan(&exposedFunction.Id, &exposedFunction.Database, &exposedFunction.Name, &roles, &exposedFunction.CreatedAt)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(roles, &exposedFunction.Roles)
+		if err != nil {
+			return err
+		}
+		exposedFunctions = append(exposedFunctions, exposedFunction)
+		return nil
+	}
+	err := scanner(cb)
+	return exposedFunctions, err
+}
+
+func (e *Engine) LoadFunctions(db *sql.DB) {
+	functions := make(map[string]map[string]DatabaseFunction)
+	exposedFunctions, err := GetEngineExposedFunctions(db)
+	if err != nil {
+		fmt.Println(err)
+		e.Functions = functions
+		return
+	}
+	databaseToExposedFunctions := make(map[string]map[string]ExposedFunction)
+	for _, exposedFunction := range exposedFunctions {
+		if _, ok := databaseToExposedFunctions[exposedFunction.Database]; !ok {
+			databaseToExposedFunctions[exposedFunction.Database] = make(map[string]ExposedFunction)
+		}
+		databaseToExposedFunctions[exposedFunction.Database][exposedFunction.Name] = exposedFunction
+	}
+	for _, database := range e.Databases {
+		if database == e.InternalSchemaName || len(databaseToExposedFunctions[database]) == 0 {
+			continue
+		}
+		databaseFunctions, err := GetDatabaseFunctions(db, database)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		functions[database] = make(map[string]DatabaseFunction)
+		for _, function := range databaseFunctions {
+			exposedFunction, ok := databaseToExposedFunctions[database][function.Name]
+			if !ok || !IsFunctionExposable(function) {
+				continue
+			}
+			if _, ok := functions[database][function.Name]; ok {
+				continue
+			}
+			function.Roles = exposedFunction.Roles
+			functions[database][function.Name] = function
+		}
+	}
+	e.Functions = functions
+}
+
+func (e *Engine) ValidateExposedFunction(db *sql.DB, input ExposedFunction) (ExposedFunction, error) {
+	if !e.DatabaseExists(input.Database) || input.Database == e.InternalSchemaName {
+		return input, fmt.Errorf("database %s doesn't exist", input.Database)
+	}
+	if !GRAPHQL_NAME_PATTERN.MatchString(input.Name) {
+		return input, fmt.Errorf("please provide a valid function")
+	}
+	databaseFunctions, err := GetDatabaseFunctions(db, input.Database)
+	if err != nil {
+		return input, err
+	}
+	exists := false
+	for _, function := range databaseFunctions {
+		if function.Name != input.Name {
+			continue
+		}
+		if !IsFunctionExposable(function) {
+			return input, fmt.Errorf("function %s.%s should have a lowercase name and named lowercase arguments", input.Database, input.Name)
+		}
+		exists = true
+	}
+	if !exists {
+		return input, fmt.Errorf("function %s doesn't exist for database %s", input.Name, input.Database)
+	}
+	if input.Roles == nil {
+		input.Roles = make([]string, 0)
+	}
+	roles, err := GetEngineRoles(db)
+	if err != nil {
+		return input, err
+	}
+	roleExists := make(map[string]bool)
+	for _, role := range roles {
+		roleExists[role.RoleName] = true
+	}
+	for _, role := range input.Roles {
+		if !roleExists[role] {
+			return input, fmt.Errorf("role %s doesn't exist", role)
+		}
+	}
+	return input, nil
+}
+
+func (e *Engine) CreateExposedFunction(db *sql.DB, input ExposedFunction) error {
+	roles, err := json.Marshal(input.Roles)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(CREATE_EXPOSED_FUNCTION, input.Database, input.Name, string(roles))
+	return err
+}
+
+func (e *Engine) UpdateExposedFunctionByID(db *sql.DB, input ExposedFunction) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("exposed function id was not provided")
+	}
+	roles, err := json.Marshal(input.Roles)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(UPDATE_EXPOSED_FUNCTION_BY_ID, input.Database, input.Name, string(roles), input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("exposed function %d doesn't exist", input.Id))
+}
+
+func (e *Engine) DeleteExposedFunctionByID(db *sql.DB, input ExposedFunction) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("exposed function id was not provided")
+	}
+	result, err := db.Exec(DELETE_EXPOSED_FUNCTION_BY_ID, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("exposed function %d doesn't exist", input.Id))
+}
+
+func (e *Engine) DeleteExposedFunctionsByDatabase(db *sql.DB, input ExposedFunction) error {
+	_, err := db.Exec(DELETE_EXPOSED_FUNCTIONS_BY_DATABASE_NAME, input.Database)
+	return err
+}
+
+func (e *Engine) GetFunctionsList() []DatabaseFunction {
+	functions := make([]DatabaseFunction, 0)
+	for _, databaseFunctions := range e.Functions {
+		for _, function := range databaseFunctions {
+			functions = append(functions, function)
+		}
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Database == functions[j].Database {
+			return functions[i].Name < functions[j].Name
+		}
+		return functions[i].Database < functions[j].Database
+	})
+	return functions
+}
+
+func (e *Engine) GetDatabaseFunction(database string, name string) (*DatabaseFunction, error) {
+	function, ok := e.Functions[database][name]
+	if !ok {
+		return nil, fmt.Errorf("function %s doesn't exist for database %s", name, database)
+	}
+	return &function, nil
+}
+
+func (function DatabaseFunction) CanExecute(role string) error {
+	if role == ADMIN_ROLE {
+		return nil
+	}
+	if len(role) == 0 {
+		return NewForbiddenError("no %s claim was provided", GetRoleClaimKey())
+	}
+	for _, entry := range function.Roles {
+		if entry == role {
+			return nil
+		}
+	}
+	return NewForbiddenError("role %s can't execute function %s.%s", role, function.Database, function.Name)
+}
+
+func (function DatabaseFunction) IsMutation() bool {
+	return function.Volatility == FUNCTION_VOLATILE
+}
+
+func (function DatabaseFunction) IsArgument(key string) bool {
+	for _, arg := range function.Args {
+		if arg.Name == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) GetFunctionModel(function DatabaseFunction) (*Model, error) {
+	if !function.ReturnsSet || len(function.ReturnTable) == 0 {
+		return nil, fmt.Errorf("function %s doesn't return a set of table rows", function.Name)
+	}
+	return e.GetModelByKey(function.Database, function.ReturnTable)
+}
+
+func (e *Engine) IsSelectableFunction(function DatabaseFunction) bool {
+	_, err := e.GetFunctionModel(function)
+	return err == nil
+}
+
+func (model *Model) From() string {
+	if len(model.Source) > 0 {
+		return model.Source
+	}
+	return fmt.Sprintf("%s.%s", model.Database, model.Table)
+}
+
+func GetFunctionArgValue(arg DatabaseFunctionArg, value any) (any, error) {
+	if strings.HasPrefix(arg.TypeName, "_") {
+		if values, err := IsArray(value); err == nil {
+			return pq.Array(values), nil
+		}
+	}
+	return GetRestHandlerArgValue(value)
+}
+
+func (function DatabaseFunction) BuildCall(args map[string]any, idx *int) (string, []any, error) {
+	parts := make([]string, 0)
+	values := make([]any, 0)
+	for _, arg := range function.Args {
+		value, ok := args[arg.Name]
+		if !ok || (value == nil && arg.HasDefault) {
+			if !arg.HasDefault {
+				return "", nil, fmt.Errorf("argument %s of function %s was not provided", arg.Name, function.Name)
+			}
+			continue
+		}
+		parsedValue, err := GetFunctionArgValue(arg, value)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, fmt.Sprintf("%s => $%d::%s", arg.Name, *idx, arg.Type))
+		values = append(values, parsedValue)
+		*idx += 1
+	}
+	return fmt.Sprintf("%s.%s(%s)", function.Database, function.Name, strings.Join(parts, ", ")), values, nil
+}
+
+func (e *Engine) SplitFunctionBody(function DatabaseFunction, body any) (map[string]any, map[string]any, error) {
+	args := make(map[string]any)
+	selectBody := make(map[string]any)
+	if body == nil {
+		return args, selectBody, nil
+	}
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("function arguments should be an object")
+	}
+	selectable := e.IsSelectableFunction(function)
+	for key, value := range parsedBody {
+		if function.IsArgument(key) {
+			args[key] = value
+			continue
+		}
+		if !selectable {
+			return nil, nil, fmt.Errorf("function %s has no argument %s", function.Name, key)
+		}
+		selectBody[key] = value
+	}
+	return args, selectBody, nil
+}
+
+func (function DatabaseFunction) FormatResult(rows []any) any {
+	if !function.ReturnsRow {
+		values := make([]any, 0, len(rows))
+		for _, row := range rows {
+			parsedRow, err := IsMapToInterface(row)
+			if err != nil {
+				continue
+			}
+			for _, value := range parsedRow {
+				values = append(values, value)
+			}
+		}
+		rows = values
+	}
+	if function.ReturnsSet {
+		return rows
+	}
+	if len(rows) == 0 || function.ReturnType == "VOID" {
+		return nil
+	}
+	return rows[0]
+}
+
+func (e *Engine) ExecuteFunction(auth jwt.MapClaims, db *sql.DB, function DatabaseFunction, args map[string]any, selectBody map[string]any, isGraphQL bool) (any, error) {
+	err := function.CanExecute(GetClaimsRole(auth))
+	if err != nil {
+		return nil, err
+	}
+	idx := 1
+	call, values, err := function.BuildCall(args, &idx)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT * FROM %s", call)
+	selectable := e.IsSelectableFunction(function)
+	if selectable {
+		model, err := e.GetFunctionModel(function)
+		if err != nil {
+			return nil, err
+		}
+		source := *model
+		source.Source = call
+		selectQuery, selectArgs, err := source.Select(auth, selectBody, 0, &idx, nil, fmt.Sprintf("_0_%s", source.Table), isGraphQL)
+		if err != nil {
+			return nil, err
+		}
+		query = selectQuery
+		values = append(values, selectArgs...)
+	}
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = e.SetTransactionRLSPolicyInput(ctx, tx, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	LogSql(query)
+	var result any
+	if selectable {
+		var rows []byte
+		err = tx.QueryRowContext(ctx, query, values...).Scan(&rows)
+		result = json.RawMessage(rows)
+	} else {
+		var rows []any
+		rows, err = ScanRowsIntoMaps(QueryContext(ctx, tx, query, values...))
+		result = function.FormatResult(rows)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (e *Engine) CallFunction(auth jwt.MapClaims, db *sql.DB, database string, name string, body any) (any, error) {
+	function, err := e.GetDatabaseFunction(database, name)
+	if err != nil {
+		return nil, err
+	}
+	args, selectBody, err := e.SplitFunctionBody(*function, body)
+	if err != nil {
+		return nil, err
+	}
+	return e.ExecuteFunction(auth, db, *function, args, selectBody, false)
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestDatabaseFunctionCanExecute(t *testing.T) {
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	function := DatabaseFunction{Database: "public", Name: "archive_posts", Volatility: FUNCTION_VOLATILE, Roles: []string{"editor"}}
+	cases := []struct {
+		role    string
+		allowed bool
+	}{
+		{role: ADMIN_ROLE, allowed: true},
+		{role: "editor", allowed: true},
+		{role: "viewer"},
+		{role: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.role, func(t *testing.T) {
+			err := function.CanExecute(c.role)
+			if c.allowed {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if GetErrorStatusCode(err, 0) != http.StatusForbidden {
+				t.Errorf("expected a forbidden error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteFunctionChecksRole(t *testing.T) {
+	t.Setenv("DISABLE_AUTH", "")
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	engine := &Engine{}
+	function := DatabaseFunction{Database: "public", Name: "archive_posts", Volatility: FUNCTION_VOLATILE, Roles: []string{"editor"}}
+	_, err := engine.ExecuteFunction(jwt.MapClaims{"role": "viewer"}, nil, function, map[string]any{}, map[string]any{}, false)
+	if GetErrorStatusCode(err, 0) != http.StatusForbidden {
+		t.Errorf("expected a forbidden error, got %v", err)
+	}
+}
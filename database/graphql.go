@@ -3,6 +3,7 @@ package database
 import (
 	"application/environment"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -57,6 +58,8 @@ type EngineGraphQlDatabaseTableConfig struct {
 	Database   string
 	Table      string
 	ActionType string
+	Action     *CustomRestHandlerInput
+	Function   *DatabaseFunction
 }
 
 type GraphQLEntity struct {
@@ -212,7 +215,8 @@ input _key_exists_all {
  _key_exists_all: [String]
 } 
 input _text_search {
- _text_search: SingleValue
+ _text_search: String
+ _text_search_config: String
 } 
 input column_input {
 _in: [SingleValue!]
@@ -235,7 +239,33 @@ _contained_in: Object
 _key_exists: String
 _key_exists_any: [String]
 _key_exists_all: [String]
-_text_search: SingleValue
+_text_search: String
+_text_search_config: String
+}
+input computed_field_input {
+args: Object
+_in: [SingleValue!]
+_nin: [SingleValue!]
+_lt: SingleValue
+_lte: SingleValue
+_gt: SingleValue
+_gte: SingleValue
+_is: SingleValue
+_is_not: SingleValue
+_like: String
+_ilike: String
+_eq: SingleValue
+_neq: SingleValue
+_any: [SingleValue!]
+_nany: SingleValue
+_all: [SingleValue!]
+_contains: Object
+_contained_in: Object
+_key_exists: String
+_key_exists_any: [String]
+_key_exists_all: [String]
+_text_search: String
+_text_search_config: String
 }`
 }
 
@@ -266,10 +296,45 @@ func BuildModelOrderByExp(model *Model) (string, error) {
 	for key := range model.ColumnsMap {
 		fields = append(fields, fmt.Sprintf("%s: order_by_direction_enum", key))
 	}
+	fields = append(fields, fmt.Sprintf("%s: order_by_direction_enum", TEXT_SEARCH_RANK_KEY))
+	for key := range model.ComputedFields {
+		fields = append(fields, fmt.Sprintf("%s: order_by_direction_enum", key))
+	}
+
+	for key, value := range model.Relations {
+		info, ok := model.RelationsInfoMap[key]
+		if !ok {
+			continue
+		}
+		if info.RelationType == ARRAY {
+			fields = append(fields, fmt.Sprintf("%s_aggregate: %s_%s_aggregate_order_by_exp", key, value.Database, value.Table))
+		} else {
+			fields = append(fields, fmt.Sprintf("%s: %s_%s_order_by_exp", key, value.Database, value.Table))
+		}
+	}
 
 	return fmt.Sprintf("%s {\n%s\n}", typeName, strings.Join(fields, "\n")), nil
 }
 
+func BuildModelAggregateOrderByExp(model *Model) []string {
+	typeName := fmt.Sprintf("%s_%s", model.Database, model.Table)
+
+	columnFields := []string{}
+	for key := range model.ColumnsMap {
+		columnFields = append(columnFields, fmt.Sprintf("%s: order_by_direction_enum", key))
+	}
+
+	fields := []string{"_count: order_by_direction_enum"}
+	for _, aggregationKey := range []string{"_min", "_max", "_sum", "_avg"} {
+		fields = append(fields, fmt.Sprintf("%s: %s_aggregate_column_order_by_exp", aggregationKey, typeName))
+	}
+
+	return []string{
+		fmt.Sprintf("input %s_aggregate_column_order_by_exp {\n%s\n}", typeName, strings.Join(columnFields, "\n")),
+		fmt.Sprintf("input %s_aggregate_order_by_exp {\n%s\n}", typeName, strings.Join(fields, "\n")),
+	}
+}
+
 func BuildModelBoolExp(model *Model) (string, error) {
 	typeName := fmt.Sprintf("input %s_%s_bool_exp", model.Database, model.Table)
 
@@ -277,6 +342,9 @@ func BuildModelBoolExp(model *Model) (string, error) {
 	for key := range model.ColumnsMap {
 		fields = append(fields, fmt.Sprintf("%s: column_input", key))
 	}
+	for key := range model.ComputedFields {
+		fields = append(fields, fmt.Sprintf("%s: computed_field_input", key))
+	}
 
 	for key, value := range model.Relations {
 		fields = append(fields, fmt.Sprintf("%s: %s_%s_bool_exp", key, value.Database, value.Table))
@@ -303,6 +371,18 @@ func BuildQueryTypeFields(model *Model) ([]string, error) {
 
 }
 
+func BuildModelComputedFields(model *Model) ([]string, error) {
+	fields := make([]string, 0)
+	for _, computedField := range GetSortedComputedFields(model) {
+		fieldType, err := GetGraphqlQueryFieldTypeByColumn(computedField.Column())
+		if err != nil {
+			return fields, err
+		}
+		fields = append(fields, fmt.Sprintf("%s(%s: Object): %s", computedField.Name, COMPUTED_FIELD_ARGS_KEY, fieldType))
+	}
+	return fields, nil
+}
+
 func BuildSelectAggregateTypeArgs(model *Model) string {
 	_where := fmt.Sprintf("_where: %s_%s_bool_exp", model.Database, model.Table)
 	_groupBy := fmt.Sprintf("_groupBy: [%s_%s_enum]", model.Database, model.Table)
@@ -325,6 +405,8 @@ func BuildSelectTypeArgs(model *Model) string {
 	_distinct := fmt.Sprintf("_distinct: [%s_%s_enum]", model.Database, model.Table)
 	_limit := "_limit: Int"
 	_offset := "_offset: Int"
+	_after := "_after: String"
+	_before := "_before: String"
 	arr := []string{
 		_where,
 		_groupBy,
@@ -332,6 +414,8 @@ func BuildSelectTypeArgs(model *Model) string {
 		_distinct,
 		_limit,
 		_offset,
+		_after,
+		_before,
 	}
 	return fmt.Sprintf("(%s)", strings.Join(arr, ", "))
 }
@@ -347,6 +431,19 @@ func BuildModelRelationalFields(model *Model) ([]string, error) {
 	return fields, nil
 }
 
+func BuildModelRelationalPaginatedFields(model *Model) ([]string, error) {
+	fields := []string{}
+	for key, value := range model.Relations {
+		if info, ok := model.RelationsInfoMap[key]; !ok || info.RelationType != ARRAY {
+			continue
+		}
+		field := fmt.Sprintf(`%s%s%s: %s_%s%s`, key, PAGINATED_SUFFIX, BuildSelectTypeArgs(value), value.Database, value.Table, PAGINATED_SUFFIX)
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
 func BuildModelRelationalAggregateColumns(model *Model) ([]string, error) {
 	fields := []string{}
 	for key, value := range model.Relations {
@@ -413,6 +510,9 @@ func BuildModelInsertInputObjects(model *Model) (string, error) {
 	fields, _ := BuildQueryTypeFields(model)
 	formattedFields := RemoveRequiredSuffixFromGQLType(fields)
 	for key, value := range model.Relations {
+		if (*Model)(value).IsReadOnly() {
+			continue
+		}
 		formattedFields = append(formattedFields, fmt.Sprintf("%s: %s_%s_insert_input", key, value.Database, value.Table))
 	}
 	return fmt.Sprintf("%s {\n%s\n}", typeName, strings.Join(formattedFields, "\n")), nil
@@ -439,18 +539,32 @@ func BuildQueryType(model *Model) (string, error) {
 		return typeName, err
 	}
 
+	modelColumnFields = append(modelColumnFields, fmt.Sprintf("%s: String", CURSOR_KEY))
+	modelColumnFields = append(modelColumnFields, fmt.Sprintf("%s: Float", TEXT_SEARCH_RANK_KEY))
+
+	computedFields, _ := BuildModelComputedFields(model)
+	modelColumnFields = append(modelColumnFields, computedFields...)
+
 	relationalColumns, _ := BuildModelRelationalFields(model)
 	modelColumnFields = append(modelColumnFields, relationalColumns...)
 
 	relationalAggregateColumns, _ := BuildModelRelationalAggregateColumns(model)
 	modelColumnFields = append(modelColumnFields, relationalAggregateColumns...)
 
+	relationalPaginatedFields, _ := BuildModelRelationalPaginatedFields(model)
+	modelColumnFields = append(modelColumnFields, relationalPaginatedFields...)
+
 	modelColumnFieldsString := strings.Join(modelColumnFields, "\n")
 
 	return fmt.Sprintf("%s {\n%s\n}", typeName, modelColumnFieldsString), nil
 
 }
 
+func BuildQueryPaginatedType(model *Model) string {
+	typeName := fmt.Sprintf("%s_%s", model.Database, model.Table)
+	return fmt.Sprintf("type %s%s {\n%s: [%s!]!\n%s: Int!\n}", typeName, PAGINATED_SUFFIX, PAGINATED_ROWS_KEY, typeName, PAGINATED_TOTAL_KEY)
+}
+
 func BuildQueryAggregateType(model *Model) (string, error) {
 	typeName := fmt.Sprintf("type %s_%s_aggregate", model.Database, model.Table)
 	arr := []string{
@@ -494,6 +608,17 @@ func (e *Engine) BuildQueryTypes() ([]string, error) {
 	return queryTypes, nil
 }
 
+func (e *Engine) BuildQueryPaginatedTypes() []string {
+	queryTypes := make([]string, 0)
+	for _, model := range e.Models {
+		if model.Database == e.InternalSchemaName {
+			continue
+		}
+		queryTypes = append(queryTypes, BuildQueryPaginatedType(model))
+	}
+	return queryTypes
+}
+
 func (e *Engine) BuildQueryAggregateTypes() ([]string, error) {
 	queryTypes := make([]string, 0)
 	for _, model := range e.Models {
@@ -549,6 +674,7 @@ func (e *Engine) BuildSelectInputTypes() ([]string, error) {
 		}
 
 		queryTypes = append(queryTypes, queryType)
+		queryTypes = append(queryTypes, BuildModelAggregateOrderByExp(model)...)
 	}
 	return queryTypes, nil
 }
@@ -556,7 +682,7 @@ func (e *Engine) BuildSelectInputTypes() ([]string, error) {
 func (e *Engine) BuildUpdateInputTypes() ([]string, error) {
 	queryTypes := make([]string, 0)
 	for _, model := range e.Models {
-		if model.Database == e.InternalSchemaName {
+		if model.Database == e.InternalSchemaName || model.IsReadOnly() {
 			continue
 		}
 		queryType, err := BuildModelUpdateInput(model)
@@ -572,7 +698,7 @@ func (e *Engine) BuildUpdateInputTypes() ([]string, error) {
 func (e *Engine) BuildInsertInputTypes() ([]string, error) {
 	queryTypes := make([]string, 0)
 	for _, model := range e.Models {
-		if model.Database == e.InternalSchemaName {
+		if model.Database == e.InternalSchemaName || model.IsReadOnly() {
 			continue
 		}
 		queryType, err := BuildModelInsertInput(model)
@@ -606,8 +732,12 @@ func (e *Engine) BuildRootQueryType() ([]string, error) {
 
 		fields = append(fields, fmt.Sprintf("%s_%s%s: [%s_%s!]", model.Database, model.Table, BuildSelectTypeArgs(model), model.Database, model.Table))
 		fields = append(fields, fmt.Sprintf("%s_%s_aggregate%s: %s_%s_aggregate", model.Database, model.Table, BuildSelectAggregateTypeArgs(model), model.Database, model.Table))
+		fields = append(fields, fmt.Sprintf("%s_%s_connection%s: %s_%s_connection!", model.Database, model.Table, BuildSelectTypeArgs(model), model.Database, model.Table))
+		fields = append(fields, fmt.Sprintf("%s_%s%s%s: %s_%s%s!", model.Database, model.Table, PAGINATED_SUFFIX, BuildSelectTypeArgs(model), model.Database, model.Table, PAGINATED_SUFFIX))
 
 	}
+	fields = append(fields, e.BuildRestHandlerGraphqlFields(true)...)
+	fields = append(fields, e.BuildFunctionGraphqlFields(true)...)
 
 	str := fmt.Sprintf("%s{\n%s\n}", typeName, strings.Join(fields, "\n"))
 
@@ -618,7 +748,7 @@ func (e *Engine) BuildRootMutationType() ([]string, error) {
 	typeName := "type Mutation"
 	fields := []string{}
 	for _, model := range e.Models {
-		if model.Database == e.InternalSchemaName {
+		if model.Database == e.InternalSchemaName || model.IsReadOnly() {
 			continue
 		}
 
@@ -626,6 +756,25 @@ func (e *Engine) BuildRootMutationType() ([]string, error) {
 		fields = append(fields, fmt.Sprintf("%s_%s_update(set:%s_%s_update_input!,_where:%s_%s_bool_exp): Object", model.Database, model.Table, model.Database, model.Table, model.Database, model.Table))
 		fields = append(fields, fmt.Sprintf("%s_%s_delete(_where:%s_%s_bool_exp): Object", model.Database, model.Table, model.Database, model.Table))
 	}
+	fields = append(fields, e.BuildRestHandlerGraphqlFields(false)...)
+	fields = append(fields, e.BuildFunctionGraphqlFields(false)...)
+
+	str := fmt.Sprintf("%s{\n%s\n}", typeName, strings.Join(fields, "\n"))
+
+	return []string{str}, nil
+}
+
+func (e *Engine) BuildRootSubscriptionType() ([]string, error) {
+	typeName := "type Subscription"
+	fields := []string{}
+	for _, model := range e.Models {
+		if model.Database == e.InternalSchemaName {
+			continue
+		}
+
+		fields = append(fields, fmt.Sprintf("%s_%s%s: [%s_%s!]", model.Database, model.Table, BuildSelectTypeArgs(model), model.Database, model.Table))
+		fields = append(fields, fmt.Sprintf("%s_%s_aggregate%s: %s_%s_aggregate", model.Database, model.Table, BuildSelectAggregateTypeArgs(model), model.Database, model.Table))
+	}
 
 	str := fmt.Sprintf("%s{\n%s\n}", typeName, strings.Join(fields, "\n"))
 
@@ -646,6 +795,19 @@ func (e *Engine) BuildEngineGraphqlConfig() map[string]*EngineGraphQlDatabaseTab
 			Table:      model.Table,
 			ActionType: "SELECT",
 		}
+		config[fmt.Sprintf("%s%s", resolverBaseName, PAGINATED_SUFFIX)] = &EngineGraphQlDatabaseTableConfig{
+			Database:   model.Database,
+			Table:      model.Table,
+			ActionType: "SELECT",
+		}
+		config[fmt.Sprintf("%s_connection", resolverBaseName)] = &EngineGraphQlDatabaseTableConfig{
+			Database:   model.Database,
+			Table:      model.Table,
+			ActionType: GRAPHQL_CONNECTION,
+		}
+		if model.IsReadOnly() {
+			continue
+		}
 		config[fmt.Sprintf("%s_insert", resolverBaseName)] = &EngineGraphQlDatabaseTableConfig{
 			Database:   model.Database,
 			Table:      model.Table,
@@ -662,6 +824,23 @@ func (e *Engine) BuildEngineGraphqlConfig() map[string]*EngineGraphQlDatabaseTab
 			ActionType: "DELETE",
 		}
 	}
+	for _, handler := range e.GetGraphqlRestHandlers() {
+		action := handler
+		config[GetRestHandlerGraphqlName(handler)] = &EngineGraphQlDatabaseTableConfig{
+			Database:   handler.Database,
+			ActionType: GRAPHQL_ACTION,
+			Action:     &action,
+		}
+	}
+	for _, function := range e.GetGraphqlFunctions() {
+		databaseFunction := function
+		config[GetFunctionGraphqlName(function)] = &EngineGraphQlDatabaseTableConfig{
+			Database:   function.Database,
+			Table:      function.ReturnTable,
+			ActionType: GRAPHQL_FUNCTION,
+			Function:   &databaseFunction,
+		}
+	}
 
 	return config
 }
@@ -669,27 +848,39 @@ func (e *Engine) BuildEngineGraphqlConfig() map[string]*EngineGraphQlDatabaseTab
 func (e *Engine) LoadGraphql() {
 
 	orderBy := []string{GetOrderByEnum()}
+	pageInfo := []string{GetPageInfoType()}
 	scalarsAndDefaultInputs := []string{GetScalarsAndInputs()}
 	queryTypes, _ := e.BuildQueryTypes()
 	queryAggregateTypes, _ := e.BuildQueryAggregateTypes()
+	queryConnectionTypes := e.BuildQueryConnectionTypes()
+	queryPaginatedTypes := e.BuildQueryPaginatedTypes()
 	enumTypes, _ := e.BuildEnumTypes()
 	selectInputTypes, _ := e.BuildSelectInputTypes()
 	updateInputTypes, _ := e.BuildUpdateInputTypes()
 	insertInputTypes, _ := e.BuildInsertInputTypes()
 	rootQuery, _ := e.BuildRootQueryType()
 	rootMutation, _ := e.BuildRootMutationType()
+	rootSubscription, _ := e.BuildRootSubscriptionType()
+	actionTypes := e.BuildRestHandlerGraphqlTypes()
+	functionTypes := e.BuildFunctionGraphqlTypes()
 
 	parts := make([]string, 0)
 	parts = append(parts, scalarsAndDefaultInputs...)
 	parts = append(parts, orderBy...)
+	parts = append(parts, pageInfo...)
 	parts = append(parts, queryTypes...)
 	parts = append(parts, queryAggregateTypes...)
+	parts = append(parts, queryConnectionTypes...)
+	parts = append(parts, queryPaginatedTypes...)
 	parts = append(parts, enumTypes...)
 	parts = append(parts, selectInputTypes...)
 	parts = append(parts, insertInputTypes...)
 	parts = append(parts, updateInputTypes...)
+	parts = append(parts, actionTypes...)
+	parts = append(parts, functionTypes...)
 	parts = append(parts, rootQuery...)
 	parts = append(parts, rootMutation...)
+	parts = append(parts, rootSubscription...)
 
 	schemaStr := strings.Join(parts, "\n")
 	WriteGraphQLSchemaToFile(schemaStr)
@@ -709,6 +900,10 @@ func ShouldAuthenticate() bool {
 }
 
 func CanAccess(config EngineGraphQlDatabaseTableConfig, auth jwt.MapClaims) error {
+	return CanAccessDatabase(config.Database, auth)
+}
+
+func CanAccessDatabase(database string, auth jwt.MapClaims) error {
 	if !ShouldAuthenticate() {
 		return nil
 	}
@@ -720,17 +915,17 @@ func CanAccess(config EngineGraphQlDatabaseTableConfig, auth jwt.MapClaims) erro
 		return fmt.Errorf("Unauthorized")
 	}
 
-	database, ok := auth["database"]
+	databaseClaim, ok := auth["database"]
 
 	if !ok {
 		return fmt.Errorf("Unauthorized")
 	}
 
-	dbName, ok := database.(string)
+	dbName, ok := databaseClaim.(string)
 	if !ok {
 		return fmt.Errorf("Unauthorized")
 	}
-	if config.Database != dbName {
+	if database != dbName {
 		return fmt.Errorf("Unauthorized")
 	}
 
@@ -764,12 +959,43 @@ func (e *Engine) GraphqlQueryResolve(inputData any, auth jwt.MapClaims, db *sql.
 	}
 
 	configByDatabase := make(map[string]map[string]any)
+	resolvedResults := make(map[string]any)
 
 	for key, value := range parsedSelectBody.GetMap() {
 		config, ok := e.GraphQL.EngineResolverNameToDatabaseTableConfigMap[key]
 		if !ok {
 			return nil, fmt.Errorf("no such relation")
 		}
+		if config.ActionType == GRAPHQL_ACTION {
+			if !IsRestHandlerGraphqlQuery(*config.Action) {
+				continue
+			}
+			result, err := e.ResolveGraphqlAction(*config, value, auth, db)
+			if err != nil {
+				return nil, err
+			}
+			resolvedResults[key] = result
+			continue
+		}
+		if config.ActionType == GRAPHQL_FUNCTION {
+			if config.Function.IsMutation() {
+				continue
+			}
+			result, err := e.ResolveGraphqlFunction(*config, value, auth, db)
+			if err != nil {
+				return nil, err
+			}
+			resolvedResults[key] = result
+			continue
+		}
+		if config.ActionType == GRAPHQL_CONNECTION {
+			result, err := e.ResolveGraphqlConnection(*config, value, auth, db)
+			if err != nil {
+				return nil, err
+			}
+			resolvedResults[key] = result
+			continue
+		}
 		if config.ActionType != "SELECT" {
 			continue
 		}
@@ -783,24 +1009,52 @@ func (e *Engine) GraphqlQueryResolve(inputData any, auth jwt.MapClaims, db *sql.
 		isAggregate := strings.HasSuffix(key, "_aggregate")
 		if isAggregate {
 			configByDatabase[config.Database][fmt.Sprintf("%s_aggregate", config.Table)] = value
+		} else if IsPaginated(key) {
+			configByDatabase[config.Database][fmt.Sprintf("%s%s", config.Table, PAGINATED_SUFFIX)] = value
 		} else {
 			configByDatabase[config.Database][config.Table] = value
 		}
 	}
 
-	results := []byte{}
+	if len(resolvedResults) == 0 && len(configByDatabase) <= 1 {
+		results := []byte{}
+		for dbName, payload := range configByDatabase {
+			result, err := e.SelectExec(auth, db, dbName, payload, true)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result...)
+		}
+		return results, nil
+	}
+
+	results := make(map[string]json.RawMessage)
 	for dbName, payload := range configByDatabase {
 		result, err := e.SelectExec(auth, db, dbName, payload, true)
 		if err != nil {
 			return nil, err
 		}
-		results = append(results, result...)
+		partial := make(map[string]json.RawMessage)
+		err = json.Unmarshal(result, &partial)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range partial {
+			results[key] = value
+		}
+	}
+	for key, value := range resolvedResults {
+		result, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		results[key] = result
 	}
 
-	return results, nil
+	return json.Marshal(results)
 }
 
-func (e *Engine) GraphqlMutationResolve(inputData any, auth jwt.MapClaims, db *sql.DB) (any, error) {
+func (e *Engine) GraphqlMutationResolve(inputData any, auth jwt.MapClaims, requestId string, db *sql.DB) (any, error) {
 
 	parsedActionBody, err := IsOrderedMap(inputData)
 	if err != nil {
@@ -808,6 +1062,7 @@ func (e *Engine) GraphqlMutationResolve(inputData any, auth jwt.MapClaims, db *s
 	}
 
 	configByDatabase := make(map[string][]any)
+	actionResults := make(map[string]any)
 	var iterErr error
 	parsedActionBody.Iter(func(key string, value any) {
 		if iterErr != nil {
@@ -816,10 +1071,35 @@ func (e *Engine) GraphqlMutationResolve(inputData any, auth jwt.MapClaims, db *s
 		config, ok := e.GraphQL.EngineResolverNameToDatabaseTableConfigMap[key]
 		if !ok {
 			iterErr = fmt.Errorf("no such relation")
+			return
 		}
 		if config.ActionType == "SELECT" {
 			return
 		}
+		if config.ActionType == GRAPHQL_ACTION {
+			if IsRestHandlerGraphqlQuery(*config.Action) {
+				return
+			}
+			result, err := e.ResolveGraphqlAction(*config, value, auth, db)
+			if err != nil {
+				iterErr = err
+				return
+			}
+			actionResults[key] = result
+			return
+		}
+		if config.ActionType == GRAPHQL_FUNCTION {
+			if !config.Function.IsMutation() {
+				return
+			}
+			result, err := e.ResolveGraphqlFunction(*config, value, auth, db)
+			if err != nil {
+				iterErr = err
+				return
+			}
+			actionResults[key] = result
+			return
+		}
 		iterErr = CanAccess(*config, auth)
 		if iterErr != nil {
 			return
@@ -871,12 +1151,15 @@ func (e *Engine) GraphqlMutationResolve(inputData any, auth jwt.MapClaims, db *s
 		body := map[string]any{
 			"transactions": value,
 		}
-		result, err := e.Process("", db, dbName, body)
+		result, err := e.Process(auth, requestId, GetClaimsRole(auth), GraphQLDataTrigger, db, dbName, body)
 		if err != nil {
 			return nil, err
 		}
 		results[dbName] = result
 	}
+	for key, value := range actionResults {
+		results[key] = value
+	}
 
 	return results, nil
 }
@@ -905,6 +1188,25 @@ func (e *Engine) GetIntrospectionQueryResponse() ([]byte, error) {
 	return resp, nil
 }
 
+func astToOrderedValue(node ast.Node, variables map[string]interface{}, isAggregate bool) interface{} {
+	switch node := node.(type) {
+	case *ast.ObjectValue:
+		obj := NewOrderedMap()
+		for _, field := range node.Fields {
+			obj.Set(field.Name.Value, astToOrderedValue(field.Value, variables, isAggregate))
+		}
+		return obj
+	case *ast.ListValue:
+		list := make([]interface{}, len(node.Values))
+		for i, value := range node.Values {
+			list[i] = astToOrderedValue(value, variables, isAggregate)
+		}
+		return list
+	default:
+		return astToMap(node, variables, isAggregate)
+	}
+}
+
 func astToMap(node ast.Node, variables map[string]interface{}, isAggregate bool) interface{} {
 	switch node := node.(type) {
 	case *ast.Document:
@@ -936,7 +1238,12 @@ func astToMap(node ast.Node, variables map[string]interface{}, isAggregate bool)
 			arguments := make(map[string]interface{})
 			for _, arg := range node.Arguments {
 				argName := arg.Name.Value
-				argValue := astToMap(arg.Value, variables, isAggregation)
+				var argValue interface{}
+				if argName == "_orderBy" {
+					argValue = astToOrderedValue(arg.Value, variables, isAggregation)
+				} else {
+					argValue = astToMap(arg.Value, variables, isAggregation)
+				}
 				arguments[argName] = argValue
 			}
 			for key, value := range arguments {
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var GRAPHQL_CONNECTION string = "CONNECTION"
+
+func GetPageInfoType() string {
+	return "type page_info {\nhasNextPage: Boolean!\nhasPreviousPage: Boolean!\nstartCursor: String\nendCursor: String\n}"
+}
+
+func BuildQueryConnectionTypes(model *Model) []string {
+	typeName := fmt.Sprintf("%s_%s", model.Database, model.Table)
+	return []string{
+		fmt.Sprintf("type %s_edge {\ncursor: String!\nnode: %s!\n}", typeName, typeName),
+		fmt.Sprintf("type %s_connection {\nedges: [%s_edge!]!\npageInfo: page_info!\n}", typeName, typeName),
+	}
+}
+
+func (e *Engine) BuildQueryConnectionTypes() []string {
+	connectionTypes := make([]string, 0)
+	for _, model := range e.Models {
+		if model.Database == e.InternalSchemaName {
+			continue
+		}
+		connectionTypes = append(connectionTypes, BuildQueryConnectionTypes(model)...)
+	}
+	return connectionTypes
+}
+
+func GetGraphqlConnectionLimit(value any) (int, bool) {
+	switch limit := value.(type) {
+	case int:
+		return limit, true
+	case float64:
+		return int(limit), true
+	case json.Number:
+		parsedLimit, err := strconv.Atoi(limit.String())
+		return parsedLimit, err == nil
+	case string:
+		parsedLimit, err := strconv.Atoi(limit)
+		return parsedLimit, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func BuildGraphqlConnectionSelectBody(args map[string]any) map[string]any {
+	body := make(map[string]any)
+	for key, value := range args {
+		if _, ok := SELECT_BODY_KEYS[key]; ok {
+			body[key] = value
+		}
+	}
+
+	edges, _ := IsMapToInterface(args["edges"])
+	node, err := IsMapToInterface(edges["node"])
+	if err != nil {
+		node = make(map[string]any)
+	}
+	_select := make(map[string]any)
+	for key, value := range node {
+		if key == "_select" {
+			if nodeSelect, err := IsMapToInterface(value); err == nil {
+				for column, selected := range nodeSelect {
+					_select[column] = selected
+				}
+			}
+			continue
+		}
+		body[key] = value
+	}
+	_select[CURSOR_KEY] = true
+	body["_select"] = _select
+	return body
+}
+
+func (e *Engine) ResolveGraphqlConnection(config EngineGraphQlDatabaseTableConfig, value any, auth jwt.MapClaims, db *sql.DB) (map[string]any, error) {
+	err := CanAccess(config, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := IsMapToInterface(value)
+	if err != nil {
+		args = make(map[string]any)
+	}
+	body := BuildGraphqlConnectionSelectBody(args)
+
+	limit, hasLimit := GetGraphqlConnectionLimit(body["_limit"])
+	if hasLimit {
+		body["_limit"] = limit + 1
+	}
+
+	result, err := e.SelectExec(auth, db, config.Database, map[string]any{config.Table: body}, true)
+	if err != nil {
+		return nil, err
+	}
+	parsedResult := make(map[string][]map[string]json.RawMessage)
+	err = json.Unmarshal(result, &parsedResult)
+	if err != nil {
+		return nil, err
+	}
+	rows := parsedResult[config.Table]
+
+	after := GetCursorValue(body, CURSOR_AFTER_KEY)
+	before := GetCursorValue(body, CURSOR_BEFORE_KEY)
+	reverse := len(before) > 0 && len(after) == 0
+	hasMore := hasLimit && len(rows) > limit
+	if hasMore && reverse {
+		rows = rows[len(rows)-limit:]
+	} else if hasMore {
+		rows = rows[:limit]
+	}
+	hasNextPage := len(before) > 0 || (hasMore && !reverse)
+	hasPreviousPage := len(after) > 0 || (hasMore && reverse)
+
+	edgesArgs, _ := IsMapToInterface(args["edges"])
+	edgesSelect, _ := IsMapToInterface(edgesArgs["_select"])
+	nodeArgs, _ := IsMapToInterface(edgesArgs["node"])
+	nodeSelect, _ := IsMapToInterface(nodeArgs["_select"])
+	_, hasNode := edgesArgs["node"]
+	_, keepNodeCursor := nodeSelect[CURSOR_KEY]
+
+	edges := make([]map[string]any, 0, len(rows))
+	cursors := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var cursor string
+		err := json.Unmarshal(row[CURSOR_KEY], &cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursors = append(cursors, cursor)
+		if !keepNodeCursor {
+			delete(row, CURSOR_KEY)
+		}
+		edge := make(map[string]any)
+		if _, ok := edgesSelect["cursor"]; ok {
+			edge["cursor"] = cursor
+		}
+		if hasNode {
+			edge["node"] = row
+		}
+		edges = append(edges, edge)
+	}
+
+	var startCursor, endCursor any
+	if len(cursors) > 0 {
+		startCursor = cursors[0]
+		endCursor = cursors[len(cursors)-1]
+	}
+	pageInfoArgs, _ := IsMapToInterface(args["pageInfo"])
+	pageInfoSelect, _ := IsMapToInterface(pageInfoArgs["_select"])
+	pageInfoValues := map[string]any{
+		"hasNextPage":     hasNextPage,
+		"hasPreviousPage": hasPreviousPage,
+		"startCursor":     startCursor,
+		"endCursor":       endCursor,
+	}
+	pageInfo := make(map[string]any)
+	for key := range pageInfoSelect {
+		if value, ok := pageInfoValues[key]; ok {
+			pageInfo[key] = value
+		}
+	}
+
+	connection := make(map[string]any)
+	if _, ok := args["edges"]; ok {
+		connection["edges"] = edges
+	}
+	if _, ok := args["pageInfo"]; ok {
+		connection["pageInfo"] = pageInfo
+	}
+	return connection, nil
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/graphql-go/graphql/language/ast"
+	gqlParser "github.com/graphql-go/graphql/language/parser"
+)
+
+var GRAPHQL_QUERY_OPERATION string = "query"
+var GRAPHQL_MUTATION_OPERATION string = "mutation"
+var GRAPHQL_SUBSCRIPTION_OPERATION string = "subscription"
+
+type GraphqlSubscription struct {
+	Id        string
+	Operation string
+	Payload   any
+	Tables    map[string]map[string]bool
+}
+
+func GetGraphqlOperationType(input string, operationName string) (string, error) {
+	document, err := gqlParser.Parse(gqlParser.ParseParams{
+		Source: input,
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if len(operationName) > 0 && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+		return operation.Operation, nil
+	}
+	return "", fmt.Errorf("no operation was found")
+}
+
+func (e *Engine) CollectGraphqlSubscriptionTables(model *Model, selection any, tables map[string]map[string]bool) {
+	if _, ok := tables[model.Database]; !ok {
+		tables[model.Database] = make(map[string]bool)
+	}
+	if tables[model.Database][model.Table] {
+		return
+	}
+	tables[model.Database][model.Table] = true
+
+	parsedSelection, err := IsMapToInterface(selection)
+	if err != nil {
+		return
+	}
+	for key, value := range parsedSelection {
+		relation, ok := model.RelationsInfoMap[key]
+		if !ok {
+			continue
+		}
+		table := relation.ToTable
+		if table == model.Table {
+			table = relation.FromTable
+		}
+		relationModel, err := e.GetModelByKey(model.Database, table)
+		if err != nil {
+			continue
+		}
+		e.CollectGraphqlSubscriptionTables(relationModel, value, tables)
+	}
+}
+
+func (e *Engine) NewGraphqlSubscription(id string, input GraphQLRequestInput, auth jwt.MapClaims) (GraphqlSubscription, error) {
+	subscription := GraphqlSubscription{Id: id, Tables: make(map[string]map[string]bool)}
+	if len(id) == 0 {
+		return subscription, fmt.Errorf("subscription id was not provided")
+	}
+
+	operation, err := GetGraphqlOperationType(input.Query, input.OperationName)
+	if err != nil {
+		return subscription, err
+	}
+	subscription.Operation = operation
+
+	queryErrors := e.GraphQL.Handler.Schema.ValidateWithVariables(input.Query, input.Variables)
+	if len(queryErrors) > 0 {
+		return subscription, queryErrors[0]
+	}
+
+	payload, err := e.GraphQL.GraphqlParser(input.Query, input.Variables)
+	if err != nil {
+		return subscription, err
+	}
+	subscription.Payload = payload
+
+	if operation != GRAPHQL_SUBSCRIPTION_OPERATION {
+		return subscription, nil
+	}
+
+	parsedPayload, err := IsOrderedMap(payload)
+	if err != nil {
+		return subscription, err
+	}
+	for key, value := range parsedPayload.GetMap() {
+		config, ok := e.GraphQL.EngineResolverNameToDatabaseTableConfigMap[key]
+		if !ok || config.ActionType != "SELECT" {
+			return subscription, fmt.Errorf("no such relation")
+		}
+		err := CanAccess(*config, auth)
+		if err != nil {
+			return subscription, err
+		}
+		model, err := e.GetModelByKey(config.Database, config.Table)
+		if err != nil {
+			return subscription, err
+		}
+		_, err = model.ApplySelectPermission(auth, value, true)
+		if err != nil {
+			return subscription, err
+		}
+		e.CollectGraphqlSubscriptionTables(model, value, subscription.Tables)
+	}
+
+	return subscription, nil
+}
+
+func (subscription GraphqlSubscription) IsAffectedBy(input DataTriggerInput) bool {
+	return subscription.Tables[input.Database][input.Table]
+}
+
+func (e *Engine) ResolveGraphqlSubscription(subscription GraphqlSubscription, auth jwt.MapClaims, requestId string, db *sql.DB) (map[string]any, error) {
+	response := make(map[string]any)
+	if subscription.Operation == GRAPHQL_MUTATION_OPERATION {
+		results, err := e.GraphqlMutationResolve(subscription.Payload, auth, requestId, db)
+		if err != nil {
+			return nil, err
+		}
+		response["data"] = results
+		return response, nil
+	}
+
+	results, err := e.GraphqlQueryResolve(subscription.Payload, auth, db)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]any)
+	if len(results) > 0 {
+		err = json.Unmarshal(results, &data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response["data"] = data
+	return response, nil
+}
@@ -102,11 +102,7 @@ func IsEligibleOrderByOperation(arg interface{}) bool {
 	if !ok {
 		return false
 	}
-	parsedOrderBy, errorY := IsMapToInterface(fields)
-	if errorY != nil {
-		return false
-	}
-	return len(parsedOrderBy) > 0
+	return len(GetOrderByEntries(fields)) > 0
 }
 
 func GetMapValueFromStringKey(arg map[string]interface{}, key string) (interface{}, error) {
@@ -213,7 +209,7 @@ func (e *Engine) GetModelByKey(database, key string) (*Model, error) {
 	if !ok {
 		return nil, fmt.Errorf("no such model %s for database %s", key, database)
 	}
-	tableName := ClearAliasForAggregate(key)
+	tableName := ClearAliasForPaginated(ClearAliasForAggregate(key))
 	model, ok := tablesMap[tableName]
 	if !ok {
 		return nil, fmt.Errorf("no such model %s", key)
@@ -228,6 +224,43 @@ func ClearAliasForAggregate(alias string) string {
 	return strings.Split(alias, "_aggregate")[0]
 }
 
+func IsTotalRequested(body interface{}) bool {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return false
+	}
+	withTotal, _ := parsedBody[TOTAL_KEY].(bool)
+	return withTotal
+}
+
+func IsPaginated(alias string) bool {
+	return strings.HasSuffix(alias, PAGINATED_SUFFIX)
+}
+
+func ClearAliasForPaginated(alias string) string {
+	return strings.TrimSuffix(alias, PAGINATED_SUFFIX)
+}
+
+func NormalizePaginatedBody(body interface{}) interface{} {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return body
+	}
+	normalizedBody := make(map[string]interface{})
+	rows, rowsErr := IsMapToInterface(parsedBody[PAGINATED_ROWS_KEY])
+	for key, value := range parsedBody {
+		if key == PAGINATED_ROWS_KEY || (key == "_select" && rowsErr == nil) {
+			continue
+		}
+		normalizedBody[key] = value
+	}
+	for key, value := range rows {
+		normalizedBody[key] = value
+	}
+	normalizedBody[TOTAL_KEY] = true
+	return normalizedBody
+}
+
 func ProcessEntryIsSelect(entry map[string]interface{}) bool {
 	_, ok := entry["select"]
 	return ok
@@ -135,6 +135,17 @@ func CheckSQLStringValidity(db *sql.DB, query string) error {
 
 }
 
+func ExpectAffectedRows(result sql.Result, message string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s", message)
+	}
+	return nil
+}
+
 func (e *Engine) EngineModelsToNotCycledValue() []Model {
 	models := []Model{}
 	for _, model := range e.Models {
@@ -8,7 +8,7 @@ import (
 	"sync"
 )
 
-var mutex sync.Mutex
+var mutex sync.RWMutex
 
 type Engine struct {
 	Databases                 []string                     `json:"databases"`
@@ -26,13 +26,17 @@ type Engine struct {
 	DataTriggers              map[string]map[string]DataTrigger
 	RestHandlers              []CustomRestHandlerInput
 	RestHandlersMap           map[string]map[string]CustomRestHandlerInput
+	Functions                 map[string]map[string]DatabaseFunction
 	SuperUser                 string
 	AuthDisabled              bool
 	DataTriggerProtocol       string
+	WebhookDispatcher         *WebhookDispatcher
+	EventBus                  EventBus
+	OpenAPI                   OpenAPIDocument
 }
 
 func (e *Engine) CreateSuperUser(db *sql.DB) error {
-	engineRole := EngineRole{RoleName: "admin"}
+	engineRole := EngineRole{RoleName: ADMIN_ROLE}
 	e.CreateEngineRole(db, engineRole)
 	engineUser := EngineUserInput{
 		Email:    "admin@admin.com",
@@ -71,7 +75,9 @@ func Init(db *sql.DB) *Engine {
 		SuperUser:           environment.GetEnvValueToStringWithDefault("SUPER_USER", "engine_administrator"),
 		DataTriggerProtocol: environment.GetEnvValue("DATA_TRIGGER_PROTOCOL"),
 		AuthDisabled:        environment.GetEnvValue("DISABLE_AUTH") == "ON",
+		WebhookDispatcher:   NewWebhookDispatcher(),
 	}
+	engine.EventBus = NewEventBus(db, engine.WebSocketEvent)
 	engine.CreateSuperUser(db)
 	engine.LoadRLS(db)
 	relations, _ := GetEngineRelations(db)
@@ -100,8 +106,21 @@ func Init(db *sql.DB) *Engine {
 	engine.LoadGlobalAuth(db)
 	engine.LoadWebhooks(db)
 	engine.LoadDataTriggers(db)
+	err = engine.SyncDataChangeTriggers(db)
+	if err != nil {
+		fmt.Println(err)
+	}
 	engine.LoadRestHandlers(db)
+	engine.LoadFunctions(db)
 	engine.LoadGraphql()
+	engine.LoadOpenAPI()
+	engine.StartWebhookDispatcher(db)
+	engine.StartMaterializedViewRefresher(db)
+	engine.StartDataChangeListener(db)
+	err = engine.EventBus.Start()
+	if err != nil {
+		fmt.Println(err)
+	}
 
 	return engine
 }
@@ -141,12 +160,20 @@ func (engine *Engine) Reload(db *sql.DB) {
 	engine.LoadGlobalAuth(db)
 	engine.LoadWebhooks(db)
 	engine.LoadDataTriggers(db)
+	err = engine.SyncDataChangeTriggers(db)
+	if err != nil {
+		fmt.Println(err)
+	}
 	engine.LoadRestHandlers(db)
+	engine.LoadFunctions(db)
 	engine.LoadGraphql()
+	engine.LoadOpenAPI()
 }
 
 func InitializeModels(db *sql.DB) ([]*Model, error) {
 	relations, _ := GetEngineRelations(db)
+	computedFields, _ := GetEngineComputedFields(db)
+	roles, _ := GetEngineRoles(db)
 	var models []*Model = make([]*Model, 0)
 	databases, err := GetDatabases(db)
 	if err != nil {
@@ -157,9 +184,16 @@ func InitializeModels(db *sql.DB) ([]*Model, error) {
 		if err != nil {
 			return models, err
 		}
+		kinds, err := GetTableKinds(db, database)
+		if err != nil {
+			return models, err
+		}
 
 		for _, tableName := range tables {
 			columns, err := GetTableColumns(db, database, tableName)
+			if kinds[tableName] == MATERIALIZED_VIEW_KIND {
+				columns, err = GetMaterializedViewColumns(db, database, tableName)
+			}
 			if err != nil {
 				return models, err
 			}
@@ -169,13 +203,22 @@ func InitializeModels(db *sql.DB) ([]*Model, error) {
 			}
 
 			model := NewModel(database, tableName)
+			if kind, ok := kinds[tableName]; ok {
+				model.Kind = kind
+			}
 			model.Columns = columns
 			model.Indexes = indexes
-			// model.RLS["ADMIN"] = ColumnsMap{"id": "int8"}
 			for _, column := range columns {
 				model.ColumnsMap[column.Name] = column.Type
 			}
 
+			for _, computedField := range computedFields {
+				if computedField.Database == database && computedField.Table == tableName {
+					model.ComputedFields[computedField.Name] = computedField
+				}
+			}
+			model.LoadModelPermissions(roles)
+
 			models = append(models, model)
 		}
 		for _, relation := range relations {
@@ -204,12 +247,17 @@ func InitializeEngineDatabase(db *sql.DB) {
 	}
 	CreateEngineLogsTable(db)
 	CreateEngineWebhooksTable(db)
+	CreateEngineWebhookDeliveriesTable(db)
+	CreateEngineEventPayloadsTable(db)
 	CreateEngineAuthProviderTable(db)
 	CreateEngineDataTriggersTable(db)
 	CreateEngineRelationsTable(db)
 	CreateEngineApiKeysTable(db)
 	CreateEngineCustomEndopointsTable(db)
 	CreateEngineRowLevelSecurityTable(db)
+	CreateEngineComputedFieldsTable(db)
+	CreateEngineExposedFunctionsTable(db)
+	CreateEngineMaterializedViewRefreshesTable(db)
 }
 
 func CreateEngineLogsTable(db *sql.DB) {
@@ -332,6 +380,17 @@ func CreateEngineWebhooksTable(db *sql.DB) {
 		MaxLength:    255,
 		DefaultValue: "'POST_EXEC'",
 	})
+	columns = append(columns, ColumnInput{
+		Name:      "secret",
+		Type:      "varchar",
+		Nullable:  true,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "headers",
+		Type:     "jsonb",
+		Nullable: true,
+	})
 	columns = append(columns, ColumnInput{
 		Name:         "created_at",
 		Type:         "timestamp",
@@ -406,6 +465,182 @@ func CreateEngineWebhooksTable(db *sql.DB) {
 	}
 	CreateTable(db, table)
 	CreateIndexes(db, table)
+	db.Exec(ALTER_ENGINE_WEBHOOKS_ADD_SIGNING_COLUMNS)
+
+}
+
+func CreateEngineEventPayloadsTable(db *sql.DB) {
+	columns := []ColumnInput{}
+	columns = append(columns, ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "payload",
+		Type:     "text",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "created_at",
+		Type:     "timestamp",
+		Nullable: false,
+	})
+
+	primaryIndexColumn := ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	}
+
+	primaryIndex := IndexInput{
+		Columns: []ColumnInput{
+			primaryIndexColumn,
+		},
+		Type: PRIMARY,
+	}
+
+	indexes := []IndexInput{}
+
+	indexes = append(indexes, primaryIndex)
+
+	table := TableInput{
+		Database: environment.GetEnvValue("INTERNAL_SCHEMA_NAME"),
+		Name:     "engine_event_payloads",
+		Columns:  columns,
+		Indexes:  indexes,
+	}
+
+	CreateTable(db, table)
+	CreateIndexes(db, table)
+
+}
+
+func CreateEngineWebhookDeliveriesTable(db *sql.DB) {
+	columns := []ColumnInput{}
+	columns = append(columns, ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "webhook_id",
+		Type:     "int",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "endpoint",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "db_table",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "operation",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "payload",
+		Type:     "json",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "auth",
+		Type:     "text",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "status",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "attempts",
+		Type:         "int",
+		Nullable:     false,
+		DefaultValue: "0",
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "max_attempts",
+		Type:     "int",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "last_error",
+		Type:     "text",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "last_status_code",
+		Type:     "int",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "next_attempt_at",
+		Type:     "timestamp",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "created_at",
+		Type:     "timestamp",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "updated_at",
+		Type:     "timestamp",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "delivered_at",
+		Type:     "timestamp",
+		Nullable: true,
+	})
+
+	primaryIndexColumn := ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	}
+
+	primaryIndex := IndexInput{
+		Columns: []ColumnInput{
+			primaryIndexColumn,
+		},
+		Type: PRIMARY,
+	}
+
+	indexes := []IndexInput{}
+
+	indexes = append(indexes, primaryIndex)
+
+	table := TableInput{
+		Database: environment.GetEnvValue("INTERNAL_SCHEMA_NAME"),
+		Name:     "engine_webhook_deliveries",
+		Columns:  columns,
+		Indexes:  indexes,
+	}
+
+	CreateTable(db, table)
+	CreateIndexes(db, table)
+	db.Exec(CREATE_WEBHOOK_DELIVERIES_PENDING_INDEX)
 
 }
 
@@ -753,6 +988,22 @@ func CreateEngineCustomEndopointsTable(db *sql.DB) {
 		Nullable:     false,
 		DefaultValue: "CURRENT_TIMESTAMP",
 	})
+	columns = append(columns, ColumnInput{
+		Name:     "params",
+		Type:     "jsonb",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "graphql",
+		Type:     "boolean",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "graphql_name",
+		Type:      "varchar",
+		Nullable:  true,
+		MaxLength: 255,
+	})
 
 	indexes := []IndexInput{}
 
@@ -805,6 +1056,8 @@ func CreateEngineCustomEndopointsTable(db *sql.DB) {
 	CreateTable(db, table)
 
 	CreateIndexes(db, table)
+	db.Exec(ALTER_ENGINE_REST_ACTIONS_ADD_PARAMS_COLUMN)
+	db.Exec(ALTER_ENGINE_REST_ACTIONS_ADD_GRAPHQL_COLUMNS)
 }
 
 func CreateEngineRowLevelSecurityTable(db *sql.DB) {
@@ -897,3 +1150,302 @@ func CreateEngineRowLevelSecurityTable(db *sql.DB) {
 
 	CreateIndexes(db, table)
 }
+
+func CreateEngineComputedFieldsTable(db *sql.DB) {
+	columns := []ColumnInput{}
+	columns = append(columns, ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "db_table",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "function_schema",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "function_name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "return_type",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "created_at",
+		Type:         "timestamp",
+		Nullable:     false,
+		DefaultValue: "CURRENT_TIMESTAMP",
+	})
+
+	indexes := []IndexInput{}
+
+	primaryIndexColumn := ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	}
+
+	primaryIndex := IndexInput{
+		Columns: []ColumnInput{
+			primaryIndexColumn,
+		},
+		Type: PRIMARY,
+	}
+
+	uniqueColumns := []ColumnInput{}
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "db_table",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+
+	uniqueIndex := IndexInput{
+		Columns: uniqueColumns,
+		Type:    UNIQUE,
+	}
+
+	indexes = append(indexes, primaryIndex)
+	indexes = append(indexes, uniqueIndex)
+
+	table := TableInput{
+		Database: environment.GetEnvValue("INTERNAL_SCHEMA_NAME"),
+		Name:     "engine_computed_fields",
+		Columns:  columns,
+		Indexes:  indexes,
+	}
+
+	CreateTable(db, table)
+
+	CreateIndexes(db, table)
+}
+
+func CreateEngineExposedFunctionsTable(db *sql.DB) {
+	columns := []ColumnInput{}
+	columns = append(columns, ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "function_name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "roles",
+		Type:     "jsonb",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "created_at",
+		Type:         "timestamp",
+		Nullable:     false,
+		DefaultValue: "CURRENT_TIMESTAMP",
+	})
+
+	indexes := []IndexInput{}
+
+	primaryIndexColumn := ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	}
+
+	primaryIndex := IndexInput{
+		Columns: []ColumnInput{
+			primaryIndexColumn,
+		},
+		Type: PRIMARY,
+	}
+
+	uniqueColumns := []ColumnInput{}
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "function_name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+
+	uniqueIndex := IndexInput{
+		Columns: uniqueColumns,
+		Type:    UNIQUE,
+	}
+
+	indexes = append(indexes, primaryIndex)
+	indexes = append(indexes, uniqueIndex)
+
+	table := TableInput{
+		Database: environment.GetEnvValue("INTERNAL_SCHEMA_NAME"),
+		Name:     "engine_exposed_functions",
+		Columns:  columns,
+		Indexes:  indexes,
+	}
+
+	CreateTable(db, table)
+
+	CreateIndexes(db, table)
+}
+
+func CreateEngineMaterializedViewRefreshesTable(db *sql.DB) {
+	columns := []ColumnInput{}
+	columns = append(columns, ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:      "view_name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "concurrently",
+		Type:         "boolean",
+		Nullable:     false,
+		DefaultValue: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "interval_seconds",
+		Type:     "int",
+		Nullable: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "enabled",
+		Type:         "boolean",
+		Nullable:     false,
+		DefaultValue: false,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "next_refresh_at",
+		Type:         "timestamp",
+		Nullable:     false,
+		DefaultValue: "CURRENT_TIMESTAMP",
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "last_refreshed_at",
+		Type:     "timestamp",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:     "last_error",
+		Type:     "text",
+		Nullable: true,
+	})
+	columns = append(columns, ColumnInput{
+		Name:         "created_at",
+		Type:         "timestamp",
+		Nullable:     false,
+		DefaultValue: "CURRENT_TIMESTAMP",
+	})
+
+	indexes := []IndexInput{}
+
+	primaryIndexColumn := ColumnInput{
+		Name:          "id",
+		Type:          "bigint",
+		Nullable:      false,
+		AutoIncrement: true,
+	}
+
+	primaryIndex := IndexInput{
+		Columns: []ColumnInput{
+			primaryIndexColumn,
+		},
+		Type: PRIMARY,
+	}
+
+	uniqueColumns := []ColumnInput{}
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "db",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+	uniqueColumns = append(uniqueColumns, ColumnInput{
+		Name:      "view_name",
+		Type:      "varchar",
+		Nullable:  false,
+		MaxLength: 255,
+	})
+
+	uniqueIndex := IndexInput{
+		Columns: uniqueColumns,
+		Type:    UNIQUE,
+	}
+
+	indexes = append(indexes, primaryIndex)
+	indexes = append(indexes, uniqueIndex)
+
+	table := TableInput{
+		Database: environment.GetEnvValue("INTERNAL_SCHEMA_NAME"),
+		Name:     "engine_materialized_view_refreshes",
+		Columns:  columns,
+		Indexes:  indexes,
+	}
+
+	CreateTable(db, table)
+
+	CreateIndexes(db, table)
+}
@@ -29,6 +29,19 @@ func (e *Engine) SetRLSPolicyInput(conn *sql.Conn, ctx context.Context, auth jwt
 	return err
 }
 
+func (e *Engine) SetTransactionRLSPolicyInput(ctx context.Context, tx *sql.Tx, auth jwt.MapClaims) error {
+	claimsJson := []byte("{}")
+	if _, ok := auth["bypass_auth"]; !e.AuthDisabled && len(auth) > 0 && !ok {
+		var err error
+		claimsJson, err = json.Marshal(auth)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := tx.ExecContext(ctx, SET_TRANSACTION_JWT_USER, string(claimsJson))
+	return err
+}
+
 func (e *Engine) SelectExec(auth jwt.MapClaims, db *sql.DB, database string, body interface{}, isGraphQL bool) ([]byte, error) {
 
 	ctx := context.Background()
@@ -77,6 +90,9 @@ func (e *Engine) SelectExec(auth jwt.MapClaims, db *sql.DB, database string, bod
 			query = queryString
 			args = append(args, newArgs...)
 		} else {
+			if IsPaginated(key) {
+				modelBody = NormalizePaginatedBody(modelBody)
+			}
 			queryString, newArgs, err := (*model).Select(auth, modelBody, 0, &idx, nil, fmt.Sprintf("_0_%s", key), isGraphQL)
 			if err != nil {
 				return nil, err
@@ -103,7 +119,7 @@ func (e *Engine) SelectExec(auth jwt.MapClaims, db *sql.DB, database string, bod
 	return response, nil
 }
 
-func (e *Engine) InsertExec(role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
+func (e *Engine) InsertExec(auth jwt.MapClaims, requestId string, role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
 
 	databaseExists := e.DatabaseExists(database)
 	if !databaseExists {
@@ -114,6 +130,12 @@ func (e *Engine) InsertExec(role string, db *sql.DB, database string, body inter
 	if err != nil {
 		return nil, err
 	}
+
+	args, err = e.ExecutePreExecWebhooks(auth, role, database, INSERT_OPERATION, RestDataTrigger, args)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, ctx, err := TransactionQueryStart(db)
 	shouldRollback := new(bool)
 	*shouldRollback = true
@@ -129,11 +151,24 @@ func (e *Engine) InsertExec(role string, db *sql.DB, database string, body inter
 		return nil, nil
 	}
 
+	err = SetTransactionEngineOrigin(ctx, tx, requestId)
+	if err != nil {
+		return nil, err
+	}
+	ctx = WithClaims(ctx, auth)
+
 	results, err := e.InsertGo(role, database, ctx, tx, args)
 
 	if err != nil {
 		return nil, err
 	}
+
+	webhookInputs := BuildWebhookExecInputs(auth, requestId, role, database, INSERT_OPERATION, RestDataTrigger, results, nil)
+	err = e.EnqueueWebhooksFromInputs(ctx, tx, webhookInputs)
+	if err != nil {
+		return nil, err
+	}
+
 	err = TransactionQueryCommit(tx)
 
 	if err != nil {
@@ -141,35 +176,15 @@ func (e *Engine) InsertExec(role string, db *sql.DB, database string, body inter
 	}
 
 	*shouldRollback = false
+	e.WebhookDispatcher.Notify()
 
-	parsedResults, err := IsMapToArray(results)
-	if err == nil {
-		for key, payload := range parsedResults {
-			webhookInput := WebhookExecInput{
-				Database:  database,
-				Table:     key,
-				Operation: INSERT_OPERATION,
-				Type:      POST_EXEC,
-				Payload:   payload,
-				Auth:      role,
-			}
-			go e.ExecuteWebhooks(webhookInput)
-
-			dataTriggerInput := DataTriggerInput{
-				Database:  database,
-				Table:     key,
-				Operation: INSERT_OPERATION,
-				Payload:   payload,
-				Auth:      role,
-				Type:      RestDataTrigger,
-			}
-			go e.ExecuteDataTrigger(dataTriggerInput)
-		}
+	for _, webhookInput := range webhookInputs {
+		go e.ExecuteDataTrigger(webhookInput.ToDataTriggerInput())
 	}
-	return results, nil
+	return e.ProjectMutationResults(role, database, results), nil
 }
 
-func (e *Engine) UpdateExec(role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
+func (e *Engine) UpdateExec(auth jwt.MapClaims, requestId string, role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
 	databaseExists := e.DatabaseExists(database)
 
 	if !databaseExists {
@@ -180,6 +195,12 @@ func (e *Engine) UpdateExec(role string, db *sql.DB, database string, body inter
 	if err != nil {
 		return nil, err
 	}
+
+	args, err = e.ExecutePreExecWebhooks(auth, role, database, UPDATE_OPERATION, RestDataTrigger, args)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, ctx, err := TransactionQueryStart(db)
 	shouldRollback := new(bool)
 	*shouldRollback = true
@@ -195,8 +216,20 @@ func (e *Engine) UpdateExec(role string, db *sql.DB, database string, body inter
 		return nil, nil
 	}
 
-	results, err := e.UpdateGo(role, database, ctx, tx, args)
+	err = SetTransactionEngineOrigin(ctx, tx, requestId)
+	if err != nil {
+		return nil, err
+	}
+	ctx = WithClaims(ctx, auth)
+
+	results, previousResults, err := e.UpdateGo(role, database, ctx, tx, args)
+
+	if err != nil {
+		return nil, err
+	}
 
+	webhookInputs := BuildWebhookExecInputs(auth, requestId, role, database, UPDATE_OPERATION, RestDataTrigger, results, previousResults)
+	err = e.EnqueueWebhooksFromInputs(ctx, tx, webhookInputs)
 	if err != nil {
 		return nil, err
 	}
@@ -208,36 +241,16 @@ func (e *Engine) UpdateExec(role string, db *sql.DB, database string, body inter
 	}
 
 	*shouldRollback = false
+	e.WebhookDispatcher.Notify()
 
-	parsedResults, err := IsMapToArray(results)
-	if err == nil {
-		for key, payload := range parsedResults {
-			webhookInput := WebhookExecInput{
-				Database:  database,
-				Table:     key,
-				Operation: UPDATE_OPERATION,
-				Type:      POST_EXEC,
-				Payload:   payload,
-				Auth:      role,
-			}
-			go e.ExecuteWebhooks(webhookInput)
-
-			dataTriggerInput := DataTriggerInput{
-				Database:  database,
-				Table:     key,
-				Operation: UPDATE_OPERATION,
-				Payload:   payload,
-				Auth:      role,
-				Type:      RestDataTrigger,
-			}
-			go e.ExecuteDataTrigger(dataTriggerInput)
-		}
+	for _, webhookInput := range webhookInputs {
+		go e.ExecuteDataTrigger(webhookInput.ToDataTriggerInput())
 	}
 
-	return results, nil
+	return e.ProjectMutationResults(role, database, results), nil
 }
 
-func (e *Engine) DeleteExec(role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
+func (e *Engine) DeleteExec(auth jwt.MapClaims, requestId string, role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
 	databaseExists := e.DatabaseExists(database)
 
 	if !databaseExists {
@@ -247,6 +260,12 @@ func (e *Engine) DeleteExec(role string, db *sql.DB, database string, body inter
 	if err != nil {
 		return nil, err
 	}
+
+	args, err = e.ExecutePreExecWebhooks(auth, role, database, DELETE_OPERATION, RestDataTrigger, args)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, ctx, err := TransactionQueryStart(db)
 	shouldRollback := new(bool)
 	*shouldRollback = true
@@ -261,10 +280,22 @@ func (e *Engine) DeleteExec(role string, db *sql.DB, database string, body inter
 	if err != nil {
 		return nil, nil
 	}
+
+	err = SetTransactionEngineOrigin(ctx, tx, requestId)
+	if err != nil {
+		return nil, err
+	}
+	ctx = WithClaims(ctx, auth)
 	results, err := e.DeleteGo(role, database, ctx, tx, args)
 	if err != nil {
 		return nil, err
 	}
+
+	webhookInputs := BuildWebhookExecInputs(auth, requestId, role, database, DELETE_OPERATION, RestDataTrigger, results, nil)
+	err = e.EnqueueWebhooksFromInputs(ctx, tx, webhookInputs)
+	if err != nil {
+		return nil, err
+	}
 	err = TransactionQueryCommit(tx)
 
 	if err != nil {
@@ -272,36 +303,16 @@ func (e *Engine) DeleteExec(role string, db *sql.DB, database string, body inter
 	}
 
 	*shouldRollback = false
+	e.WebhookDispatcher.Notify()
 
-	parsedResults, err := IsMapToArray(results)
-	if err == nil {
-		for key, payload := range parsedResults {
-			webhookInput := WebhookExecInput{
-				Database:  database,
-				Table:     key,
-				Operation: DELETE_OPERATION,
-				Type:      POST_EXEC,
-				Payload:   payload,
-				Auth:      role,
-			}
-			go e.ExecuteWebhooks(webhookInput)
-
-			dataTriggerInput := DataTriggerInput{
-				Database:  database,
-				Table:     key,
-				Operation: DELETE_OPERATION,
-				Payload:   payload,
-				Auth:      role,
-				Type:      RestDataTrigger,
-			}
-			go e.ExecuteDataTrigger(dataTriggerInput)
-		}
+	for _, webhookInput := range webhookInputs {
+		go e.ExecuteDataTrigger(webhookInput.ToDataTriggerInput())
 	}
 
-	return results, nil
+	return e.ProjectMutationResults(role, database, results), nil
 }
 
-func (e *Engine) Process(role string, db *sql.DB, database string, body interface{}) (interface{}, error) {
+func (e *Engine) Process(auth jwt.MapClaims, requestId string, role string, channel string, db *sql.DB, database string, body interface{}) (interface{}, error) {
 	databaseExists := e.DatabaseExists(database)
 
 	if !databaseExists {
@@ -309,6 +320,28 @@ func (e *Engine) Process(role string, db *sql.DB, database string, body interfac
 	}
 	results := make(map[string][]interface{})
 
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input")
+	}
+
+	transactionsPayload, ok := parsedBody["transactions"]
+
+	if !ok {
+		return nil, fmt.Errorf("transactions key is missing")
+	}
+
+	parsedTransactions, err := IsArray(transactionsPayload)
+
+	if err != nil {
+		return nil, fmt.Errorf("process many transactions payload should be an array")
+	}
+
+	err = e.ExecuteProcessPreExecWebhooks(auth, role, database, channel, parsedTransactions)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, ctx, err := TransactionQueryStart(db)
 	shouldRollback := new(bool)
 	*shouldRollback = true
@@ -324,23 +357,13 @@ func (e *Engine) Process(role string, db *sql.DB, database string, body interfac
 		return nil, nil
 	}
 
-	parsedBody, err := IsMapToInterface(body)
+	err = SetTransactionEngineOrigin(ctx, tx, requestId)
 	if err != nil {
-		return nil, fmt.Errorf("invalid input")
-	}
-
-	transactionsPayload, ok := parsedBody["transactions"]
-
-	if !ok {
-		return nil, fmt.Errorf("transactions key is missing")
-	}
-
-	parsedTransactions, err := IsArray(transactionsPayload)
-
-	if err != nil {
-		return nil, fmt.Errorf("process many transactions payload should be an array")
+		return nil, err
 	}
+	ctx = WithClaims(ctx, auth)
 
+	webhookInputs := make([]WebhookExecInput, 0)
 	for i, entry := range parsedTransactions {
 		parsedEntry, err := IsMapToInterface(entry)
 		if err != nil {
@@ -360,8 +383,9 @@ func (e *Engine) Process(role string, db *sql.DB, database string, body interfac
 			if err != nil {
 				return nil, fmt.Errorf("insert operation [%d] failed", i)
 			}
+			webhookInputs = append(webhookInputs, BuildWebhookExecInputs(auth, requestId, role, database, INSERT_OPERATION, channel, result, nil)...)
 
-			results["insert"] = append(results["insert"], result)
+			results["insert"] = append(results["insert"], e.ProjectMutationResults(role, database, result))
 			continue
 		}
 
@@ -374,12 +398,13 @@ func (e *Engine) Process(role string, db *sql.DB, database string, body interfac
 			if err != nil {
 				return nil, fmt.Errorf("invalid input")
 			}
-			result, err := e.UpdateGo(role, database, ctx, tx, parsedPayload)
+			result, previousResult, err := e.UpdateGo(role, database, ctx, tx, parsedPayload)
 			if err != nil {
 				return nil, fmt.Errorf("update operation [%d] failed", i)
 			}
+			webhookInputs = append(webhookInputs, BuildWebhookExecInputs(auth, requestId, role, database, UPDATE_OPERATION, channel, result, previousResult)...)
 
-			results["update"] = append(results["update"], result)
+			results["update"] = append(results["update"], e.ProjectMutationResults(role, database, result))
 			continue
 		}
 
@@ -396,14 +421,20 @@ func (e *Engine) Process(role string, db *sql.DB, database string, body interfac
 			if err != nil {
 				return nil, fmt.Errorf("delete operation [%d] failed", i)
 			}
+			webhookInputs = append(webhookInputs, BuildWebhookExecInputs(auth, requestId, role, database, DELETE_OPERATION, channel, result, nil)...)
 
-			results["delete"] = append(results["delete"], result)
+			results["delete"] = append(results["delete"], e.ProjectMutationResults(role, database, result))
 			continue
 		}
 
 		return nil, fmt.Errorf("invalid operation")
 	}
 
+	err = e.EnqueueWebhooksFromInputs(ctx, tx, webhookInputs)
+	if err != nil {
+		return nil, err
+	}
+
 	err = TransactionQueryCommit(tx)
 
 	if err != nil {
@@ -411,15 +442,46 @@ func (e *Engine) Process(role string, db *sql.DB, database string, body interfac
 	}
 
 	*shouldRollback = false
+	e.WebhookDispatcher.Notify()
+
+	for _, webhookInput := range webhookInputs {
+		go e.ExecuteDataTrigger(webhookInput.ToDataTriggerInput())
+	}
 
 	return results, nil
 }
 
+func (e *Engine) ExecuteProcessPreExecWebhooks(auth jwt.MapClaims, role string, database string, channel string, transactions []interface{}) error {
+	for _, entry := range transactions {
+		parsedEntry, err := IsMapToInterface(entry)
+		if err != nil {
+			return fmt.Errorf("invalid operation")
+		}
+
+		for key, operation := range map[string]string{"insert": INSERT_OPERATION, "update": UPDATE_OPERATION, "delete": DELETE_OPERATION} {
+			payload, ok := parsedEntry[key]
+			if !ok {
+				continue
+			}
+			parsedPayload, err := IsMapToInterface(payload)
+			if err != nil {
+				return fmt.Errorf("invalid input")
+			}
+			parsedPayload, err = e.ExecutePreExecWebhooks(auth, role, database, operation, channel, parsedPayload)
+			if err != nil {
+				return err
+			}
+			parsedEntry[key] = parsedPayload
+		}
+	}
+	return nil
+}
+
 func (e *Engine) InsertGo(role string, database string, ctx context.Context, tx *sql.Tx, args map[string]interface{}) (interface{}, error) {
 	results := make(map[string][]interface{})
 
 	for key, input := range args {
-		model, err := e.GetModelByKey(database, key)
+		model, err := e.GetWritableModelByKey(database, key)
 		if err != nil {
 			return nil, err
 		}
@@ -457,7 +519,7 @@ func (e *Engine) InsertGo(role string, database string, ctx context.Context, tx
 func (e *Engine) DeleteGo(role string, database string, ctx context.Context, tx *sql.Tx, args map[string]interface{}) (interface{}, error) {
 	results := make(map[string][]interface{})
 	for key, input := range args {
-		model, err := e.GetModelByKey(database, key)
+		model, err := e.GetWritableModelByKey(database, key)
 		if err != nil {
 			return nil, err
 		}
@@ -476,24 +538,26 @@ func (e *Engine) DeleteGo(role string, database string, ctx context.Context, tx
 	return results, nil
 }
 
-func (e *Engine) UpdateGo(role string, database string, ctx context.Context, tx *sql.Tx, args map[string]interface{}) (interface{}, error) {
+func (e *Engine) UpdateGo(role string, database string, ctx context.Context, tx *sql.Tx, args map[string]interface{}) (interface{}, map[string][]interface{}, error) {
 	results := make(map[string][]interface{})
+	previousResults := make(map[string][]interface{})
 	for key, input := range args {
-		model, err := e.GetModelByKey(database, key)
+		model, err := e.GetWritableModelByKey(database, key)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		results[key] = make([]interface{}, 0)
-		result, err := model.Update(role, ctx, tx, input)
+		result, previousResult, err := model.Update(role, ctx, tx, input)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		results[key] = result
+		previousResults[key] = previousResult
 	}
-	return results, nil
+	return results, previousResults, nil
 }
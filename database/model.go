@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -27,7 +28,6 @@ type ModelRelation *Model
 type RelationMap map[string]ModelRelation
 type RelationInfoMap map[string]DatabaseRelationSchema
 type ColumnsMap map[string]string
-type RLSMap map[string]ColumnsMap
 
 type InsertionResult struct {
 	LastInsertId any
@@ -36,15 +36,23 @@ type InsertionResult struct {
 }
 
 type Model struct {
-	Database         string          `json:"database"`
-	Table            string          `json:"table"`
-	Columns          []Column        `json:"columns"`
-	Relations        RelationMap     `json:"relations"`
-	RelationsInfoMap RelationInfoMap `json:"relationInfoMap"`
-	Indexes          []Index         `json:"indexes"`
-	ColumnsMap       ColumnsMap      `json:"columnsMap"`
-	ModelRLS         []RLS           `json:"rls"`
-	RLS              RLSMap
+	Database         string              `json:"database"`
+	Table            string              `json:"table"`
+	Kind             string              `json:"kind"`
+	Columns          []Column            `json:"columns"`
+	Relations        RelationMap         `json:"relations"`
+	RelationsInfoMap RelationInfoMap     `json:"relationInfoMap"`
+	Indexes          []Index             `json:"indexes"`
+	ColumnsMap       ColumnsMap          `json:"columnsMap"`
+	ModelRLS         []RLS               `json:"rls"`
+	ComputedFields   ComputedFieldsMap   `json:"computedFields"`
+	Source           string              `json:"-"`
+	Permissions      ModelPermissionsMap `json:"-"`
+}
+
+type OrderByPart struct {
+	Expression string
+	Direction  string
 }
 
 type RelationCoalesceBuilder struct {
@@ -58,12 +66,14 @@ func NewModel(database string, table string) *Model {
 	return &Model{
 		Database:         database,
 		Table:            table,
+		Kind:             TABLE_KIND,
 		Columns:          make([]Column, 0),
 		Relations:        make(RelationMap),
 		Indexes:          make([]Index, 0),
 		RelationsInfoMap: make(RelationInfoMap),
 		ColumnsMap:       make(ColumnsMap),
-		RLS:              make(RLSMap),
+		ComputedFields:   make(ComputedFieldsMap),
+		Permissions:      make(ModelPermissionsMap),
 	}
 }
 
@@ -76,7 +86,7 @@ func (model *Model) LoadModelRLS(rls []RLS) {
 }
 
 func (model *Model) GetModelRelationInfo(alias string) (*DatabaseRelationSchema, error) {
-	x := ClearAliasForAggregate(alias)
+	x := ClearAliasForPaginated(ClearAliasForAggregate(alias))
 	if info, ok := model.RelationsInfoMap[x]; ok {
 		return &info, nil
 	}
@@ -85,7 +95,7 @@ func (model *Model) GetModelRelationInfo(alias string) (*DatabaseRelationSchema,
 }
 
 func (model *Model) GetModelRelation(alias string) (*Model, error) {
-	x := ClearAliasForAggregate(alias)
+	x := ClearAliasForPaginated(ClearAliasForAggregate(alias))
 	if info, ok := model.Relations[x]; ok {
 		return info, nil
 	}
@@ -98,17 +108,22 @@ func (model *Model) Select(auth jwt.MapClaims, body interface{}, depth int, idx
 	if !IsEligibleModelRequestBody(body) {
 		return query, args, fmt.Errorf("not eligible select input")
 	}
+	body, err := model.ApplySelectPermission(auth, body, true)
+	if err != nil {
+		return query, args, err
+	}
+	role := GetClaimsRole(auth)
 	builder := GetRelationalCoalesceSymbols(model, relationInfo, depth, parentAlias)
-	query = fmt.Sprintf(`SELECT coalesce(json_agg(_%d_%s)%s,'%s') as %s FROM (`,
+	selectExpression := fmt.Sprintf(`coalesce(json_agg(_%d_%s)%s,'%s')`,
 		depth,
 		model.Table,
 		builder.RelationExtractSymbol,
-		builder.RelationCoalesceSymbol,
-		builder.RelationAlias)
+		builder.RelationCoalesceSymbol)
+	withTotal := IsTotalRequested(body) && (relationInfo == nil || relationInfo.RelationType != OBJECT)
 	makeQuery := func(model *Model, bodyEntities interface{}, aliasPart string) error {
 		parsedBody, err := IsMapToInterface(bodyEntities)
 		currentAlias := fmt.Sprintf("_%d_%s", depth, aliasPart)
-		modelColumnsString := model.GetModelColumnsWithAlias("", body, currentAlias, isGraphQL)
+		modelColumnsString := model.GetModelColumnsWithAlias(role, body, currentAlias, isGraphQL)
 
 		if _where, ok := parsedBody["_where"]; ok {
 			initialQuery := " WHERE "
@@ -122,12 +137,58 @@ func (model *Model) Select(auth jwt.MapClaims, body interface{}, depth int, idx
 			builder.RelationWhereJoin = whereQuery
 		}
 
+		// CURSOR
+		cursorPagination, cursorErr := model.BuildCursorPagination(body, currentAlias, idx)
+		if cursorErr != nil {
+			return cursorErr
+		}
+		args = append(args, cursorPagination.Args...)
+		whereJoin := AppendWhereCondition(builder.RelationWhereJoin, cursorPagination.Condition)
+		if cursorPagination.Enabled {
+			if len(modelColumnsString) > 0 {
+				modelColumnsString += ","
+			}
+			modelColumnsString += cursorPagination.Column
+		}
+
+		// TEXT SEARCH RANK
+		rankQuery, rankArgs := model.BuildTextSearchRank(body, currentAlias, idx)
+		args = append(args, rankArgs...)
+		rankColumn := ""
+		if len(rankQuery) > 0 {
+			rankColumn = fmt.Sprintf(", %s AS %s", rankQuery, TEXT_SEARCH_RANK_KEY)
+			if IsTextSearchRankSelected(body) {
+				if len(modelColumnsString) > 0 {
+					modelColumnsString += ","
+				}
+				modelColumnsString += fmt.Sprintf("%s.%s", currentAlias, TEXT_SEARCH_RANK_KEY)
+			}
+		}
+
+		// COMPUTED FIELDS
+		computedColumns, computedArgs := model.BuildComputedFieldColumns(body, currentAlias, idx)
+		args = append(args, computedArgs...)
+
 		//DISTINCT ON
 		distinctOnQuery, _ := model.BuildDistinctOn(body, currentAlias)
 
 		//GROUP BY
 		groupByQuery, _ := model.BuildGroupBy(body, currentAlias)
 
+		// TOTAL
+		if withTotal {
+			selectExpression = fmt.Sprintf(`json_build_object('%s',%s,'%s',(SELECT count(*) FROM ( SELECT %s * FROM %s %s %s %s ) %s))`,
+				PAGINATED_ROWS_KEY,
+				selectExpression,
+				PAGINATED_TOTAL_KEY,
+				distinctOnQuery,
+				model.From(),
+				currentAlias,
+				builder.RelationWhereJoin,
+				groupByQuery,
+				currentAlias)
+		}
+
 		// LIMIT AND OFFSET
 		paginationQuery, paginationArgs := model.GetPagination(body, idx)
 		args = append(args, paginationArgs...)
@@ -135,21 +196,34 @@ func (model *Model) Select(auth jwt.MapClaims, body interface{}, depth int, idx
 		// ORDER BY
 		orderByQuery, orderByArgs := model.BuildOrderBy(body, currentAlias, idx)
 		args = append(args, orderByArgs...)
+		if direction, ok := GetTextSearchRankDirection(body); ok && len(rankQuery) > 0 {
+			orderByQuery = PrependOrderBy(orderByQuery, fmt.Sprintf("%s %s", TEXT_SEARCH_RANK_KEY, direction))
+		}
+		if cursorPagination.Enabled {
+			orderByQuery = cursorPagination.OrderBy
+		}
+
+		sourceQuery := fmt.Sprintf(`SELECT %s *%s%s FROM %s %s %s %s %s %s`,
+			distinctOnQuery,
+			rankColumn,
+			computedColumns,
+			model.From(),
+			currentAlias,
+			whereJoin,
+			groupByQuery,
+			orderByQuery,
+			paginationQuery)
+		if cursorPagination.Reverse {
+			sourceQuery = fmt.Sprintf(`SELECT * FROM ( %s ) %s %s`, sourceQuery, currentAlias, BuildCursorOrderBy(cursorPagination.Columns, false))
+		}
 
-		query += fmt.Sprintf(`SELECT row_to_json((SELECT  %s FROM (SELECT %s%s ) %s )) %s FROM ( SELECT %s * FROM %s.%s %s %s %s %s %s) %s`,
+		query += fmt.Sprintf(`SELECT row_to_json((SELECT  %s FROM (SELECT %s%s ) %s )) %s FROM ( %s ) %s`,
 			currentAlias,
 			modelColumnsString,
 			"%s",
 			currentAlias,
 			currentAlias,
-			distinctOnQuery,
-			model.Database,
-			model.Table,
-			currentAlias,
-			builder.RelationWhereJoin,
-			groupByQuery,
-			orderByQuery,
-			paginationQuery,
+			sourceQuery,
 			currentAlias)
 
 		if err == nil {
@@ -177,7 +251,13 @@ func (model *Model) Select(auth jwt.MapClaims, body interface{}, depth int, idx
 						args = append(args, queryArgs...)
 					} else {
 						depth = depth + 1
-						queryStr, queryArgs, err := relatedModel.Select(auth, bodyRelation[key], depth, idx, relatedModelInfo, currentAlias, isGraphQL)
+						relationBody := bodyRelation[key]
+						relationColumnAlias := ""
+						if IsPaginated(key) {
+							relationBody = NormalizePaginatedBody(relationBody)
+							relationColumnAlias = fmt.Sprintf(" AS %s", key)
+						}
+						queryStr, queryArgs, err := relatedModel.Select(auth, relationBody, depth, idx, relatedModelInfo, currentAlias, isGraphQL)
 						if err != nil {
 							return err
 						}
@@ -186,7 +266,7 @@ func (model *Model) Select(auth jwt.MapClaims, body interface{}, depth int, idx
 							commaForParentCols = ""
 						}
 						relationQueryAlias := fmt.Sprintf("_%d_%s", depth, relatedModel.Table)
-						query = fmt.Sprintf(query, fmt.Sprintf("%s%s.%s%s", commaForParentCols, relationQueryAlias, relatedModelInfo.Alias, "%s"))
+						query = fmt.Sprintf(query, fmt.Sprintf("%s%s.%s%s%s", commaForParentCols, relationQueryAlias, relatedModelInfo.Alias, relationColumnAlias, "%s"))
 						query += fmt.Sprintf(` LEFT OUTER JOIN LATERAL (%s) AS %s on true `, queryStr, relationQueryAlias)
 						args = append(args, queryArgs...)
 					}
@@ -197,12 +277,13 @@ func (model *Model) Select(auth jwt.MapClaims, body interface{}, depth int, idx
 		return nil
 	}
 
-	err := makeQuery(model, body, model.Table)
+	err = makeQuery(model, body, model.Table)
 	if err != nil {
 		return query, args, err
 	}
 	query += fmt.Sprintf(") _%d_%s", depth, model.Table)
 	query = fmt.Sprintf(query, "")
+	query = fmt.Sprintf(`SELECT %s as %s FROM (`, selectExpression, builder.RelationAlias) + query
 
 	return query, args, err
 }
@@ -213,6 +294,10 @@ func (model *Model) SelectAggregate(auth jwt.MapClaims, body interface{}, depth
 	if !IsEligibleModelRequestBody(body) {
 		return query, args, fmt.Errorf("not eligible aggregate input")
 	}
+	body, err := model.ApplySelectPermission(auth, body, false)
+	if err != nil {
+		return query, args, err
+	}
 	builder := GetRelationalCoalesceSymbols(model, relationInfo, depth, parentAlias)
 	makeQuery := func(model *Model, bodyEntities interface{}, aliasPart string) error {
 		parsedBody, err := IsMapToInterface(bodyEntities)
@@ -266,12 +351,16 @@ func (model *Model) SelectAggregate(auth jwt.MapClaims, body interface{}, depth
 		return nil
 	}
 
-	err := makeQuery(model, body, model.Table)
+	err = makeQuery(model, body, model.Table)
 	return query, args, err
 }
 
 func (model *Model) Insert(role string, ctx context.Context, tx *sql.Tx, body interface{}, onConflict interface{}) (interface{}, error) {
-	query, args, err := model.InsertOneQueryBuilder(role, body, onConflict)
+	body, err := model.ApplyInsertPermission(role, body, GetContextClaims(ctx))
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := model.InsertOneQueryBuilder(role, body, onConflict, GetContextClaims(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -295,6 +384,9 @@ func (model *Model) Insert(role string, ctx context.Context, tx *sql.Tx, body in
 			if err != nil {
 				return nil, err
 			}
+			if relatedModel.IsReadOnly() {
+				return nil, fmt.Errorf("%s %s is read-only", relatedModel.Database, relatedModel.Table)
+			}
 			relationalInfo, err := model.GetModelRelationInfo(key)
 			if err != nil {
 				return nil, err
@@ -358,106 +450,136 @@ func (model *Model) Insert(role string, ctx context.Context, tx *sql.Tx, body in
 	return row, nil
 }
 
-func (model *Model) BuildOnConflict(onConflict interface{}) (string, error) {
+func (model *Model) BuildOnConflict(role string, onConflict interface{}, claims jwt.MapClaims, idx *int) (string, []interface{}, error) {
+	args := make([]interface{}, 0)
 	if onConflict == nil {
-		return "", nil
+		return "", args, nil
 	}
 	parsedOnConflict, err := IsMapToInterface(onConflict)
 	if err != nil {
-		return "", err
+		return "", args, err
 	}
 
 	constraints, ok := parsedOnConflict["constraints"]
 	if !ok {
-		return "", fmt.Errorf("constraints should be an array of strings")
+		return "", args, fmt.Errorf("constraints should be an array of strings")
 	}
 
 	arr, err := IsArray(constraints)
 	if err != nil {
-		return "", fmt.Errorf("constraints should be an array of strings")
+		return "", args, fmt.Errorf("constraints should be an array of strings")
 	}
 
 	if len(arr) > len(model.Columns) {
-		return "", fmt.Errorf("too many constraints")
+		return "", args, fmt.Errorf("too many constraints")
 	}
 
 	constraintsParsed, err := isArrayOfStrings(constraints)
 
 	if err != nil {
-		return "", err
+		return "", args, err
 	}
 	constraintParts := make([]string, 0)
 	for _, column := range constraintsParsed {
 		if _, ok := model.ColumnsMap[column]; !ok {
-			return "", fmt.Errorf("model for database: %s and table: %s has no column: %s", model.Database, model.Table, column)
+			return "", args, fmt.Errorf("model for database: %s and table: %s has no column: %s", model.Database, model.Table, column)
 		}
 		constraintParts = append(constraintParts, column)
 	}
 
 	if len(constraintParts) == 0 {
-		return "", fmt.Errorf("constraints should be an array of strings ")
+		return "", args, fmt.Errorf("constraints should be an array of strings ")
 	}
 
 	update, ok := parsedOnConflict["update"]
 	if ok {
+		permission, err := model.GetPermission(role, PERMISSION_UPDATE)
+		if err != nil {
+			return "", args, err
+		}
+		allowedColumns := model.ColumnsMap
+		if permission != nil {
+			allowedColumns = permission.AllowedColumns(model)
+		}
+
+		updateColumns := make([]string, 0)
 		if update == "*" {
-			columnParts := make([]string, 0)
-			for key := range model.ColumnsMap {
-				columnParts = append(columnParts, fmt.Sprintf("%s = EXCLUDED.%s", key, key))
+			for key := range allowedColumns {
+				updateColumns = append(updateColumns, key)
 			}
+			sort.Strings(updateColumns)
+			if len(updateColumns) == 0 {
+				return "", args, NewForbiddenError("role %s can't update any column of %s %s", role, model.Database, model.Table)
+			}
+		} else {
+			arr, err := IsArray(update)
 
-			return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(constraintParts, ","), strings.Join(columnParts, ",")), nil
-		}
-
-		arr, err := IsArray(update)
+			if err != nil {
+				return "", args, err
+			}
 
-		if err != nil {
-			return "", err
-		}
+			if len(arr) > len(model.Columns) {
+				return "", args, fmt.Errorf("too many update entries")
+			}
 
-		if len(arr) > len(model.Columns) {
-			return "", fmt.Errorf("too many update entries")
+			updateColumns, err = isArrayOfStrings(update)
+			if err != nil {
+				return "", args, err
+			}
+			for _, column := range updateColumns {
+				if _, ok := model.ColumnsMap[column]; !ok {
+					return "", args, fmt.Errorf("model for database: %s and table: %s has no column: %s", model.Database, model.Table, column)
+				}
+				if _, ok := allowedColumns[column]; !ok {
+					return "", args, NewForbiddenError("role %s can't update column %s of %s %s", role, column, model.Database, model.Table)
+				}
+			}
 		}
 
-		updateArr, err := isArrayOfStrings(update)
-		if err != nil {
-			return "", err
-		}
 		columnParts := make([]string, 0)
-		for _, column := range updateArr {
-			if _, ok := model.ColumnsMap[column]; !ok {
-				return "", fmt.Errorf("model for database: %s and table: %s has no column: %s", model.Database, model.Table, column)
-			}
+		for _, column := range updateColumns {
 			columnParts = append(columnParts, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
 		}
 
 		if len(columnParts) == 0 {
-			return "", fmt.Errorf("update should be an array of strings or a string that equals *")
+			return "", args, fmt.Errorf("update should be an array of strings or a string that equals *")
 		}
-		return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(constraintParts, ","), strings.Join(columnParts, ",")), nil
+		query := fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(constraintParts, ","), strings.Join(columnParts, ","))
+		if permission != nil {
+			filter, err := permission.MergeFilter(nil, claims)
+			if err != nil {
+				return "", args, err
+			}
+			whereClause, whereArgs := model.BuildWhereClause(filter, model.Table, idx, "", "")
+			if len(whereClause) > 0 {
+				query += fmt.Sprintf(" WHERE %s", whereClause)
+				args = append(args, whereArgs...)
+			}
+		}
+		return query, args, nil
 	}
 
 	ignore, ok := parsedOnConflict["ignore"]
 
 	if !ok {
-		return "", fmt.Errorf("no action provided for  conflict")
+		return "", args, fmt.Errorf("no action provided for  conflict")
 	}
 
 	ignoreParsed, ok := ignore.(bool)
 
 	if !ok {
-		return "", fmt.Errorf("ignore should be of type boolean")
+		return "", args, fmt.Errorf("ignore should be of type boolean")
 	}
 
 	if !ignoreParsed {
-		return "", nil
+		return "", args, nil
 	}
 
-	return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(constraintParts, ",")), nil
+	return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(constraintParts, ",")), args, nil
 
 }
 
-func (model *Model) InsertOneQueryBuilder(role string, body interface{}, onConflict interface{}) (string, []interface{}, error) {
+func (model *Model) InsertOneQueryBuilder(role string, body interface{}, onConflict interface{}, claims jwt.MapClaims) (string, []interface{}, error) {
 	query := "INSERT INTO %s.%s(%s) VALUES(%s) %s RETURNING *"
 	args := make([]interface{}, 0)
 	parsedBody, err := isEligibleInsertModelRequestBody(body)
@@ -465,7 +587,7 @@ func (model *Model) InsertOneQueryBuilder(role string, body interface{}, onConfl
 		return query, args, fmt.Errorf("invalid body provided")
 	}
 
-	allowedColumns, err := model.GetAllowedColumnsMapByRole(role)
+	allowedColumns, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_INSERT)
 	if err != nil {
 		return query, args, err
 	}
@@ -492,20 +614,34 @@ func (model *Model) InsertOneQueryBuilder(role string, body interface{}, onConfl
 		return query, args, fmt.Errorf("nothing to insert here")
 	}
 
-	onConflictStr, err := model.BuildOnConflict(onConflict)
+	onConflictStr, onConflictArgs, err := model.BuildOnConflict(role, onConflict, claims, &idx)
 
 	if err != nil {
 		return query, args, err
 	}
+	args = append(args, onConflictArgs...)
 
 	query = fmt.Sprintf(query, model.Database, model.Table, strings.Join(columnsParts, ","), strings.Join(valuesParts, ","), onConflictStr)
 	return query, args, nil
 }
 
-func (model *Model) Update(role string, ctx context.Context, tx *sql.Tx, body interface{}) ([]interface{}, error) {
+func (model *Model) Update(role string, ctx context.Context, tx *sql.Tx, body interface{}) ([]interface{}, []interface{}, error) {
+	query, args, withPrevious, err := model.UpdateQueryBuilder(role, body, GetContextClaims(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	cb := QueryContext(ctx, tx, query, args...)
+	if !withPrevious {
+		results, err := model.ScanManyFromReturningResult(cb)
+		return results, nil, err
+	}
+	return model.ScanManyWithPreviousFromReturningResult(cb)
+}
+
+func (model *Model) UpdateQueryBuilder(role string, body interface{}, claims jwt.MapClaims) (string, []interface{}, bool, error) {
 	parsedBody, err := IsMapToInterface(body)
 	if err != nil {
-		return nil, err
+		return "", nil, false, err
 	}
 
 	_set, ok := parsedBody["set"]
@@ -514,19 +650,26 @@ func (model *Model) Update(role string, ctx context.Context, tx *sql.Tx, body in
 	}
 	parsedSet, err := IsMapToInterface(_set)
 	if err != nil {
-		return nil, err
+		return "", nil, false, err
 	}
-	query := fmt.Sprintf(`UPDATE %s.%s SET `, model.Database, model.Table)
+	allowedColumns, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_UPDATE)
+	if err != nil {
+		return "", nil, false, err
+	}
+	query := fmt.Sprintf(`UPDATE %s.%s AS %s SET `, model.Database, model.Table, model.Table)
 	columnParts := make([]string, 0)
 	args := make([]interface{}, 0)
 	idx := 1
 	for key, value := range parsedSet {
 		if _, ok := model.ColumnsMap[key]; ok {
+			if _, ok := allowedColumns[key]; !ok {
+				return "", nil, false, NewForbiddenError("role %s can't update column %s of %s %s", role, key, model.Database, model.Table)
+			}
 			columnParts = append(columnParts, fmt.Sprintf("%s = $%d", key, idx))
 			idx += 1
 			transformedValue, err := model.GetArgumentValueByColumnType(value, key)
 			if err != nil {
-				return nil, fmt.Errorf("invalid value provided")
+				return "", nil, false, fmt.Errorf("invalid value provided")
 			}
 			args = append(args, transformedValue)
 		}
@@ -539,15 +682,18 @@ func (model *Model) Update(role string, ctx context.Context, tx *sql.Tx, body in
 		}
 		parsedPayload, err := IsMapToInterface(payload)
 		if err != nil {
-			return nil, err
+			return "", nil, false, err
 		}
 		for key, value := range parsedPayload {
 			if _, ok := model.ColumnsMap[key]; ok {
+				if _, ok := allowedColumns[key]; !ok {
+					return "", nil, false, NewForbiddenError("role %s can't update column %s of %s %s", role, key, model.Database, model.Table)
+				}
 				columnParts = append(columnParts, fmt.Sprintf("%s = %s %s $%d", key, key, symbol, idx))
 				idx += 1
 				transformedValue, err := model.GetArgumentValueByColumnType(value, key)
 				if err != nil {
-					return nil, fmt.Errorf("invalid value provided")
+					return "", nil, false, fmt.Errorf("invalid value provided")
 				}
 				args = append(args, transformedValue)
 			}
@@ -556,7 +702,7 @@ func (model *Model) Update(role string, ctx context.Context, tx *sql.Tx, body in
 	}
 
 	if len(columnParts) == 0 {
-		return nil, fmt.Errorf("invalid update input")
+		return "", nil, false, fmt.Errorf("invalid update input")
 	}
 
 	query += fmt.Sprintf(" %s ", strings.Join(columnParts, ", "))
@@ -568,15 +714,38 @@ func (model *Model) Update(role string, ctx context.Context, tx *sql.Tx, body in
 			_where = where
 		}
 	}
+	_where, err = model.ApplyPermissionFilter(role, PERMISSION_UPDATE, _where, claims)
+	if err != nil {
+		return "", nil, false, err
+	}
 	whereClause, whereArgs := model.BuildWhereClause(_where, model.Table, &idx, "", "")
+	args = append(args, whereArgs...)
+
+	primaryKeys := model.GetPrimaryKeyColumns()
+	if len(primaryKeys) == 0 {
+		if len(whereClause) > 0 {
+			query += fmt.Sprintf(" WHERE %s ", whereClause)
+		}
+		query += " RETURNING * "
+		return query, args, false, nil
+	}
+
+	keyParts := make([]string, 0, len(primaryKeys))
+	joinParts := make([]string, 0, len(primaryKeys))
+	for i, key := range primaryKeys {
+		keyParts = append(keyParts, fmt.Sprintf("%s.%s AS _engine_key_%d", model.Table, key, i))
+		joinParts = append(joinParts, fmt.Sprintf("%s.%s = _engine_previous._engine_key_%d", model.Table, key, i))
+	}
+	previousQuery := fmt.Sprintf(`SELECT %s, to_jsonb(%s) AS _engine_previous FROM %s.%s AS %s`, strings.Join(keyParts, ", "), model.Table, model.Database, model.Table, model.Table)
 	if len(whereClause) > 0 {
-		args = append(args, whereArgs...)
-		query += fmt.Sprintf(" WHERE %s ", whereClause)
+		previousQuery += fmt.Sprintf(" WHERE %s ", whereClause)
 	}
+	previousQuery += " FOR UPDATE "
 
-	query += " RETURNING * "
-	cb := QueryContext(ctx, tx, query, args...)
-	return model.ScanManyFromReturningResult(cb)
+	query = fmt.Sprintf("WITH _engine_previous AS (%s) %s", previousQuery, query)
+	query += fmt.Sprintf(" FROM _engine_previous WHERE %s ", strings.Join(joinParts, " AND "))
+	query += fmt.Sprintf(" RETURNING %s.*, _engine_previous._engine_previous ", model.Table)
+	return query, args, true, nil
 }
 
 func (model *Model) Delete(role string, ctx context.Context, tx *sql.Tx, body interface{}) ([]interface{}, error) {
@@ -590,6 +759,10 @@ func (model *Model) Delete(role string, ctx context.Context, tx *sql.Tx, body in
 			_where = where
 		}
 	}
+	_where, err = model.ApplyPermissionFilter(role, PERMISSION_DELETE, _where, GetContextClaims(ctx))
+	if err != nil {
+		return nil, err
+	}
 	whereClause, args := model.BuildWhereClause(_where, model.Table, &idx, "", "")
 	if len(whereClause) > 0 {
 		query += fmt.Sprintf(" WHERE %s ", whereClause)
@@ -677,6 +850,62 @@ func (model *Model) ScanManyFromReturningResult(cb func(func(rows *sql.Rows) err
 	return results, err
 }
 
+func (model *Model) ScanManyWithPreviousFromReturningResult(cb func(func(rows *sql.Rows) error) error) ([]any, []any, error) {
+	results := make([]interface{}, 0)
+	previousResults := make([]interface{}, 0)
+	scanner := func(rows *sql.Rows) error {
+		row := make(map[string]any)
+		cols, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		err = rows.Scan(ptrs...)
+		if err != nil {
+			return err
+		}
+		var previous any
+		for i, col := range cols {
+			if col == "_engine_previous" {
+				x, ok := values[i].([]byte)
+				if ok {
+					err = json.Unmarshal(x, &previous)
+					if err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if columnType, ok := model.ColumnsMap[col]; ok {
+				if columnType == "json" || columnType == "jsonb" {
+					var val any
+					x, ok := values[i].([]byte)
+					if ok {
+						err = json.Unmarshal(x, &val)
+						if err != nil {
+							return err
+						}
+						row[col] = val
+						continue
+					}
+				}
+				row[col] = values[i]
+			}
+		}
+		results = append(results, row)
+		previousResults = append(previousResults, previous)
+		return nil
+	}
+
+	err := cb(scanner)
+
+	return results, previousResults, err
+}
+
 func (model *Model) isModelColumn(key string) bool {
 	_, ok := model.ColumnsMap[key]
 	return ok
@@ -711,14 +940,27 @@ func (model *Model) BuildWhereClause(body interface{}, alias string, idx *int, i
 		}
 	} else if operation, err := IsMapToInterface(body); err == nil {
 		for key, value := range operation {
-			if model.isModelColumn(key) {
+			if model.isModelColumn(key) || model.isComputedField(key) {
 				qBinder := binder
 				if len(queryString) > len(initialQuery) {
 					if len(qBinder) == 0 {
 						qBinder = "AND"
 					}
 				}
-				queryString += fmt.Sprintf(" %s %s.%s ", qBinder, alias, key)
+				column := fmt.Sprintf("%s.%s", alias, key)
+				if model.isComputedField(key) {
+					expression, newArgs := model.BuildComputedFieldExpression(key, value, alias, idx)
+					column = expression
+					args = append(args, newArgs...)
+					value = WithoutComputedFieldArgs(value)
+				}
+				if input, ok := GetTextSearchInput(value); ok {
+					query, newArgs := BuildTextSearchCondition(column, input, idx)
+					queryString += fmt.Sprintf(" %s %s ", qBinder, query)
+					args = append(args, newArgs...)
+					continue
+				}
+				queryString += fmt.Sprintf(" %s %s ", qBinder, column)
 				query, newArgs := model.BuildWhereClause(value, alias, idx, "", qBinder)
 				queryString += query
 				args = append(args, newArgs...)
@@ -948,40 +1190,154 @@ func (model *Model) BuildOrderBy(body interface{}, alias string, idx *int) (stri
 	if !ok {
 		return queryString, args
 	}
-	fields, err := IsMapToInterface(orderByFields)
-	if err != nil {
-		return queryString, args
-	}
 	parts := make([]string, 0)
-	for key, value := range fields {
-		switch parsedValue := value.(type) {
+	for _, part := range model.BuildOrderByParts(orderByFields, alias) {
+		parts = append(parts, part.String())
+	}
+	if len(parts) > 0 {
+		queryString = fmt.Sprintf(" ORDER BY %s ", strings.Join(parts, ","))
+	}
+
+	return queryString, args
+}
+
+func (part OrderByPart) String() string {
+	return fmt.Sprintf("%s %s", part.Expression, part.Direction)
+}
+
+func GetSortedOrderByKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type OrderByEntry struct {
+	Key   string
+	Value interface{}
+}
+
+func GetOrderByEntries(orderBy interface{}) []OrderByEntry {
+	entries := make([]OrderByEntry, 0)
+	appendEntry := func(key string, value interface{}) {
+		entries = append(entries, OrderByEntry{Key: key, Value: value})
+	}
+	switch parsedOrderBy := orderBy.(type) {
+	case []interface{}:
+		for _, entry := range parsedOrderBy {
+			entries = append(entries, GetOrderByEntries(entry)...)
+		}
+	case OrderedMap:
+		parsedOrderBy.Iter(appendEntry)
+	case *OrderedMap:
+		parsedOrderBy.Iter(appendEntry)
+	case map[string]interface{}:
+		for _, key := range GetSortedOrderByKeys(parsedOrderBy) {
+			appendEntry(key, parsedOrderBy[key])
+		}
+	}
+	return entries
+}
+
+func GetOrderByValue(orderBy interface{}, key string) (interface{}, bool) {
+	for _, entry := range GetOrderByEntries(orderBy) {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (model *Model) BuildOrderByParts(fields interface{}, alias string) []OrderByPart {
+	parts := make([]OrderByPart, 0)
+	for _, entry := range GetOrderByEntries(fields) {
+		key := entry.Key
+		switch parsedValue := entry.Value.(type) {
 		case string:
-			if model.isModelColumn(key) {
-				if direction, ok := ORDER_BY_KEYS[parsedValue]; ok {
-					parts = append(parts, fmt.Sprintf("%s %s", key, direction))
-				}
+			expression := fmt.Sprintf("%s.%s", alias, key)
+			if computedField, ok := model.ComputedFields[key]; ok {
+				expression = computedField.Call(alias)
+			} else if !model.isModelColumn(key) {
+				continue
+			}
+			if direction, ok := ORDER_BY_KEYS[parsedValue]; ok {
+				parts = append(parts, OrderByPart{Expression: expression, Direction: direction})
 			}
-			// if model.isRelationColumn(key) {
-			// 	relatedModel,err := model.GetModelRelation(key)
-			// 	if err != nil {
-			// 		return queryString,args
-			// 	}
-			// 	relatedModelInfo,err := model.GetModelRelationInfo(key)
-			// 	if err != nil {
-			// 		return queryString,args
-			// 	}
-			// 	orderByQuery,newArgs := relatedModel.BuildOrderBy(value,relatedModel.Table,idx)
-			// 		args = append(args, newArgs...)
-			// 	query := fmt.Sprintf("( SELECT )")
-			// }
+		default:
+			parts = append(parts, model.BuildRelationOrderByParts(key, parsedValue, alias)...)
 		}
+	}
+	return parts
+}
 
+func (model *Model) BuildRelationOrderByParts(key string, fields interface{}, alias string) []OrderByPart {
+	parts := make([]OrderByPart, 0)
+	relatedModelInfo, err := model.GetModelRelationInfo(key)
+	if err != nil {
+		return parts
 	}
-	if len(parts) > 0 {
-		queryString = fmt.Sprintf(" ORDER BY %s ", strings.Join(parts, ","))
+	relatedModel, err := model.GetModelRelation(key)
+	if err != nil {
+		return parts
+	}
+	relationAlias := fmt.Sprintf("%s_%s", alias, relatedModelInfo.Alias)
+	source := fmt.Sprintf("FROM %s.%s %s WHERE %s.%s = %s.%s",
+		relatedModel.Database,
+		relatedModel.Table,
+		relationAlias,
+		alias,
+		relatedModelInfo.FromColumn,
+		relationAlias,
+		relatedModelInfo.ToColumn)
+
+	if !IsAggregation(key) {
+		if relatedModelInfo.RelationType != OBJECT {
+			return parts
+		}
+		for _, part := range relatedModel.BuildOrderByParts(fields, relationAlias) {
+			parts = append(parts, OrderByPart{
+				Expression: fmt.Sprintf("(SELECT %s %s LIMIT 1)", part.Expression, source),
+				Direction:  part.Direction,
+			})
+		}
+		return parts
 	}
 
-	return queryString, args
+	if relatedModelInfo.RelationType != ARRAY {
+		return parts
+	}
+	for _, aggregationEntry := range GetOrderByEntries(fields) {
+		aggregationKey := aggregationEntry.Key
+		aggregation, ok := AGGREGATION_KEYS[aggregationKey]
+		if !ok {
+			continue
+		}
+		if aggregationKey == "_count" {
+			value, _ := aggregationEntry.Value.(string)
+			if direction, ok := ORDER_BY_KEYS[value]; ok {
+				parts = append(parts, OrderByPart{
+					Expression: fmt.Sprintf("(SELECT %s(*) %s)", aggregation, source),
+					Direction:  direction,
+				})
+			}
+			continue
+		}
+		for _, columnEntry := range GetOrderByEntries(aggregationEntry.Value) {
+			column := columnEntry.Key
+			value, _ := columnEntry.Value.(string)
+			direction, ok := ORDER_BY_KEYS[value]
+			if !ok || !relatedModel.isModelColumn(column) {
+				continue
+			}
+			parts = append(parts, OrderByPart{
+				Expression: fmt.Sprintf("(SELECT %s(%s.%s) %s)", aggregation, relationAlias, column, source),
+				Direction:  direction,
+			})
+		}
+	}
+	return parts
 }
 
 func (model *Model) BuildDistinctOn(body interface{}, alias string) (string, []interface{}) {
@@ -1056,11 +1412,12 @@ func (model *Model) BuildGroupBy(body interface{}, alias string) (string, []inte
 
 func (model *Model) BuildAggregate(auth jwt.MapClaims, body interface{}, alias string) (string, error) {
 	queryParts := make([]string, 0)
-	countParts := model.BuildCountAggregate("", body)
-	maxParts := model.BuildMaxAggregate("", body, alias)
-	minParts := model.BuildMinAggregate("", body, alias)
-	sumParts := model.BuildSumAggregate("", body, alias)
-	avgParts := model.BuildAVGAggregate("", body, alias)
+	role := GetClaimsRole(auth)
+	countParts := model.BuildCountAggregate(role, body)
+	maxParts := model.BuildMaxAggregate(role, body, alias)
+	minParts := model.BuildMinAggregate(role, body, alias)
+	sumParts := model.BuildSumAggregate(role, body, alias)
+	avgParts := model.BuildAVGAggregate(role, body, alias)
 
 	if len(countParts) > 0 {
 		queryParts = append(queryParts, countParts)
@@ -1158,7 +1515,7 @@ func (model *Model) BuildMinAggregate(role string, body interface{}, alias strin
 	if err != nil {
 		return ""
 	}
-	allowedColumns, err := model.GetAllowedColumnsMapByRole(role)
+	allowedColumns, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_SELECT)
 	if err != nil {
 		return ""
 	}
@@ -1192,7 +1549,7 @@ func (model *Model) BuildMaxAggregate(role string, body interface{}, alias strin
 	if err != nil {
 		return ""
 	}
-	allowedColumns, err := model.GetAllowedColumnsMapByRole(role)
+	allowedColumns, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_SELECT)
 	if err != nil {
 		return ""
 	}
@@ -1227,7 +1584,7 @@ func (model *Model) BuildSumAggregate(role string, body interface{}, alias strin
 	if err != nil {
 		return ""
 	}
-	allowedColumns, err := model.GetAllowedColumnsMapByRole(role)
+	allowedColumns, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_SELECT)
 	if err != nil {
 		return ""
 	}
@@ -1263,7 +1620,7 @@ func (model *Model) BuildAVGAggregate(role string, body interface{}, alias strin
 	if err != nil {
 		return ""
 	}
-	allowedColumns, err := model.GetAllowedColumnsMapByRole(role)
+	allowedColumns, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_SELECT)
 	if err != nil {
 		return ""
 	}
@@ -1307,7 +1664,7 @@ func (model *Model) GetModelColumnsWithAlias(role string, body interface{}, alia
 		}
 	}
 
-	allowedColumnsMap, err := model.GetAllowedColumnsMapByRole(role)
+	allowedColumnsMap, err := model.GetAllowedColumnsMapByRole(role, PERMISSION_SELECT)
 	if err != nil {
 		return ""
 	}
@@ -1321,21 +1678,24 @@ func (model *Model) GetModelColumnsWithAlias(role string, body interface{}, alia
 			columns = append(columns, fmt.Sprintf("%s%s", prefix, column))
 		}
 	}
+	for column := range model.GetSelectedComputedFields(body) {
+		columns = append(columns, fmt.Sprintf("%s%s", prefix, column))
+	}
 
 	return strings.Join(columns, ",")
 }
 
-func (model *Model) GetAllowedColumnsMapByRole(role string) (ColumnsMap, error) {
-	allowedColumnsMap, ok := model.RLS[role]
-	if !ok && len(model.RLS) > 0 {
-		return nil, fmt.Errorf("no columns are available")
+func (model *Model) GetAllowedColumnsMapByRole(role string, operation string) (ColumnsMap, error) {
+	permission, err := model.GetPermission(role, operation)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(model.RLS) == 0 {
-		allowedColumnsMap = model.ColumnsMap
+	if permission == nil {
+		return model.ColumnsMap, nil
 	}
 
-	return allowedColumnsMap, nil
+	return permission.AllowedColumns(model), nil
 }
 
 func (model *Model) GetArgumentValueByColumnType(value interface{}, key string) (interface{}, error) {
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestUpdateQueryBuilder(t *testing.T) {
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	t.Setenv("DISABLE_AUTH", "")
+	claims := jwt.MapClaims{"sub": "42"}
+	body := map[string]any{"set": map[string]any{"title": "hello"}, "_where": map[string]any{"id": map[string]any{"_eq": 1.0}}}
+	cases := []struct {
+		name         string
+		indexes      []Index
+		want         string
+		wantPrevious bool
+	}{
+		{
+			name:         "previous rows are paired by primary key",
+			indexes:      []Index{{Type: "PRIMARY KEY", Column: "id"}},
+			want:         "WITH _engine_previous AS (SELECT posts.id AS _engine_key_0, to_jsonb(posts) AS _engine_previous FROM public.posts AS posts WHERE  (  posts.id  = $2  AND posts.author_id  = $3 )  FOR UPDATE ) UPDATE public.posts AS posts SET  title = $1  FROM _engine_previous WHERE posts.id = _engine_previous._engine_key_0  RETURNING posts.*, _engine_previous._engine_previous ",
+			wantPrevious: true,
+		},
+		{
+			name:         "composite primary key",
+			indexes:      []Index{{Type: "PRIMARY KEY", Column: "id"}, {Type: "PRIMARY KEY", Column: "author_id"}},
+			want:         "WITH _engine_previous AS (SELECT posts.author_id AS _engine_key_0, posts.id AS _engine_key_1, to_jsonb(posts) AS _engine_previous FROM public.posts AS posts WHERE  (  posts.id  = $2  AND posts.author_id  = $3 )  FOR UPDATE ) UPDATE public.posts AS posts SET  title = $1  FROM _engine_previous WHERE posts.author_id = _engine_previous._engine_key_0 AND posts.id = _engine_previous._engine_key_1  RETURNING posts.*, _engine_previous._engine_previous ",
+			wantPrevious: true,
+		},
+		{
+			name: "without primary key",
+			want: "UPDATE public.posts AS posts SET  title = $1  WHERE  (  posts.id  = $2  AND posts.author_id  = $3 )  RETURNING * ",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			model := NewMutationPermissionsTestModel()
+			model.Indexes = c.indexes
+			query, args, withPrevious, err := model.UpdateQueryBuilder("editor", body, claims)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if query != c.want {
+				t.Errorf("expected %q, got %q", c.want, query)
+			}
+			if withPrevious != c.wantPrevious {
+				t.Errorf("expected previous rows %v, got %v", c.wantPrevious, withPrevious)
+			}
+			if !reflect.DeepEqual(args, []interface{}{"hello", 1.0, "42"}) {
+				t.Errorf("expected args [hello 1 42], got %v", args)
+			}
+		})
+	}
+}
+
+func NewQueryBuilderTestModels() (*Model, *Model) {
+	users := NewModel("public", "users")
+	users.Columns = []Column{{Name: "id"}, {Name: "email"}}
+	users.ColumnsMap = ColumnsMap{"id": "bigint", "email": "text"}
+	posts := NewModel("public", "posts")
+	posts.Columns = []Column{{Name: "id"}, {Name: "author_id"}, {Name: "title"}, {Name: "likes"}}
+	posts.ColumnsMap = ColumnsMap{"id": "bigint", "author_id": "bigint", "title": "text", "likes": "integer"}
+	users.Relations = RelationMap{"posts": posts}
+	users.RelationsInfoMap = RelationInfoMap{"posts": {Alias: "posts", RelationType: ARRAY, FromColumn: "id", ToColumn: "author_id"}}
+	posts.Relations = RelationMap{"author": users}
+	posts.RelationsInfoMap = RelationInfoMap{"author": {Alias: "author", RelationType: OBJECT, FromColumn: "author_id", ToColumn: "id"}}
+	return users, posts
+}
+
+func TestSelectTotal(t *testing.T) {
+	auth := jwt.MapClaims{"bypass_all": true}
+	cases := []struct {
+		name     string
+		body     map[string]any
+		total    string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "plain",
+			body:     map[string]any{"_select": map[string]any{"id": true}, "_total": true, "_limit": 10.0},
+			total:    "'total',(SELECT count(*) FROM ( SELECT  * FROM public.users _0_users   ) _0_users)",
+			wantArgs: []interface{}{10.0},
+		},
+		{
+			name:     "distinct",
+			body:     map[string]any{"_select": map[string]any{"id": true}, "_total": true, "_distinct": []any{"email"}},
+			total:    "'total',(SELECT count(*) FROM ( SELECT  DISTINCT ON (_0_users.email)  * FROM public.users _0_users   ) _0_users)",
+			wantArgs: []interface{}{},
+		},
+		{
+			name:     "group by with filter",
+			body:     map[string]any{"_select": map[string]any{"id": true}, "_total": true, "_groupBy": []any{"email"}, "_where": map[string]any{"id": map[string]any{"_gt": 1.0}}},
+			total:    "'total',(SELECT count(*) FROM ( SELECT  * FROM public.users _0_users  WHERE   _0_users.id  > $1   GROUP BY _0_users.email  ) _0_users)",
+			wantArgs: []interface{}{1.0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			users, _ := NewQueryBuilderTestModels()
+			idx := 1
+			query, args, err := users.Select(auth, c.body, 0, &idx, nil, "", false)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !strings.HasPrefix(query, "SELECT json_build_object('rows',coalesce(json_agg(_0_users),'[]'),") {
+				t.Errorf("expected rows and total object, got %q", query)
+			}
+			if !strings.Contains(query, c.total) {
+				t.Errorf("expected %q in %q", c.total, query)
+			}
+			if !reflect.DeepEqual(args, c.wantArgs) {
+				t.Errorf("expected args %v, got %v", c.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestSelectTotalIsSkippedForObjectRelations(t *testing.T) {
+	_, posts := NewQueryBuilderTestModels()
+	idx := 1
+	query, _, err := posts.Select(jwt.MapClaims{"bypass_all": true}, map[string]any{"_select": map[string]any{"id": true}, "author": map[string]any{"_select": map[string]any{"id": true}, "_total": true}}, 0, &idx, nil, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if strings.Contains(query, "'total'") {
+		t.Errorf("expected no total for an object relation, got %q", query)
+	}
+}
+
+func TestBuildOrderByRelations(t *testing.T) {
+	cases := []struct {
+		name    string
+		orderBy any
+		want    string
+	}{
+		{
+			name:    "object relation column",
+			orderBy: map[string]any{"author": map[string]any{"email": "ASC"}},
+			want:    " ORDER BY (SELECT _0_posts_author.email FROM public.users _0_posts_author WHERE _0_posts.author_id = _0_posts_author.id LIMIT 1) ASC ",
+		},
+		{
+			name:    "array relation count",
+			orderBy: map[string]any{"comments_aggregate": map[string]any{"_count": "DESC"}},
+			want:    " ORDER BY (SELECT COUNT(*) FROM public.comments _0_posts_comments WHERE _0_posts.id = _0_posts_comments.post_id) DESC ",
+		},
+		{
+			name:    "array relation aggregate column",
+			orderBy: map[string]any{"comments_aggregate": map[string]any{"_max": map[string]any{"likes": "DESC_NULLS_LAST"}}},
+			want:    " ORDER BY (SELECT MAX(_0_posts_comments.likes) FROM public.comments _0_posts_comments WHERE _0_posts.id = _0_posts_comments.post_id) DESC NULLS LAST ",
+		},
+		{
+			name:    "array form keeps priority",
+			orderBy: []any{map[string]any{"likes": "DESC"}, map[string]any{"author": map[string]any{"email": "ASC"}}, map[string]any{"id": "ASC"}},
+			want:    " ORDER BY _0_posts.likes DESC,(SELECT _0_posts_author.email FROM public.users _0_posts_author WHERE _0_posts.author_id = _0_posts_author.id LIMIT 1) ASC,_0_posts.id ASC ",
+		},
+		{
+			name:    "array relation without aggregate is ignored",
+			orderBy: map[string]any{"comments": map[string]any{"likes": "ASC"}},
+			want:    "",
+		},
+		{
+			name:    "object relation aggregate is ignored",
+			orderBy: map[string]any{"author_aggregate": map[string]any{"_count": "ASC"}},
+			want:    "",
+		},
+		{
+			name:    "unknown aggregate column is ignored",
+			orderBy: map[string]any{"comments_aggregate": map[string]any{"_sum": map[string]any{"missing": "ASC"}}},
+			want:    "",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, posts := NewQueryBuilderTestModels()
+			comments := NewModel("public", "comments")
+			comments.Columns = []Column{{Name: "id"}, {Name: "post_id"}, {Name: "likes"}}
+			comments.ColumnsMap = ColumnsMap{"id": "bigint", "post_id": "bigint", "likes": "integer"}
+			posts.Relations["comments"] = comments
+			posts.RelationsInfoMap["comments"] = DatabaseRelationSchema{Alias: "comments", RelationType: ARRAY, FromColumn: "id", ToColumn: "post_id"}
+			idx := 1
+			query, _ := posts.BuildOrderBy(map[string]any{"_orderBy": c.orderBy}, "_0_posts", &idx)
+			if query != c.want {
+				t.Errorf("expected %q, got %q", c.want, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,881 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var OPENAPI_VERSION string = "3.0.3"
+
+var OPENAPI_COMPARISON_OPERATORS []string = []string{
+	"_eq", "_neq", "_gt", "_gte", "_lt", "_lte", "_like", "_ilike", "_is", "_is_not", "_contains", "_contained_in", "_key_exists", "_text_search", "_text_search_config",
+}
+
+var OPENAPI_ARRAY_COMPARISON_OPERATORS []string = []string{
+	"_in", "_nin", "_any", "_nany", "_all", "_key_exists_any", "_key_exists_all",
+}
+
+type OpenAPIDocument map[string]any
+
+func GetOpenAPISchemaName(database string, table string, suffix string) string {
+	if len(suffix) == 0 {
+		return fmt.Sprintf("%s_%s", database, table)
+	}
+	return fmt.Sprintf("%s_%s_%s", database, table, suffix)
+}
+
+func OpenAPIRef(name string) map[string]any {
+	return map[string]any{"$ref": fmt.Sprintf("#/components/schemas/%s", name)}
+}
+
+func OpenAPIArrayOf(items map[string]any) map[string]any {
+	return map[string]any{"type": "array", "items": items}
+}
+
+func OpenAPIJsonBody(schema map[string]any, required bool) map[string]any {
+	return map[string]any{
+		"required": required,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+func OpenAPIJsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+func OpenAPIErrorResponses(responses map[string]any) map[string]any {
+	responses["default"] = OpenAPIJsonResponse("Error", OpenAPIRef("message"))
+	return responses
+}
+
+func GetOpenAPISchemaByColumnType(columnType string, maxLength int64) map[string]any {
+	formattedType := strings.ToLower(strings.TrimSpace(columnType))
+	if formattedType == "array" {
+		return OpenAPIArrayOf(map[string]any{})
+	}
+	if strings.HasSuffix(formattedType, "[]") {
+		return OpenAPIArrayOf(GetOpenAPISchemaByColumnType(strings.TrimSuffix(formattedType, "[]"), 0))
+	}
+	switch formattedType {
+	case "smallint", "int2", "integer", "int", "int4", "serial":
+		return map[string]any{"type": "integer", "format": "int32"}
+	case "bigint", "int8", "bigserial":
+		return map[string]any{"type": "integer", "format": "int64"}
+	case "real", "float4", "float":
+		return map[string]any{"type": "number", "format": "float"}
+	case "double precision", "double", "float8":
+		return map[string]any{"type": "number", "format": "double"}
+	case "numeric", "decimal", "money":
+		return map[string]any{"type": "number"}
+	case "boolean", "bool":
+		return map[string]any{"type": "boolean"}
+	case "json", "jsonb":
+		return map[string]any{}
+	case "uuid":
+		return map[string]any{"type": "string", "format": "uuid"}
+	case "date":
+		return map[string]any{"type": "string", "format": "date"}
+	case "timestamp", "timestamp without time zone", "timestamp with time zone", "timestamptz":
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	schema := map[string]any{"type": "string"}
+	if maxLength > 0 {
+		schema["maxLength"] = maxLength
+	}
+	return schema
+}
+
+func GetOpenAPISchemaByColumn(column Column) map[string]any {
+	schema := GetOpenAPISchemaByColumnType(column.Type, column.MaxLength)
+	if column.Nullable {
+		schema["nullable"] = true
+	}
+	if len(column.DefaultValue) > 0 {
+		schema["description"] = fmt.Sprintf("defaults to %s", column.DefaultValue)
+	}
+	return schema
+}
+
+func GetOpenAPISchemaByRestHandlerParam(param CustomRestHandlerParam) map[string]any {
+	var schema map[string]any
+	switch param.Type {
+	case REST_PARAM_INT:
+		schema = map[string]any{"type": "integer"}
+	case REST_PARAM_FLOAT:
+		schema = map[string]any{"type": "number"}
+	case REST_PARAM_BOOLEAN:
+		schema = map[string]any{"type": "boolean"}
+	case REST_PARAM_UUID:
+		schema = map[string]any{"type": "string", "format": "uuid"}
+	case REST_PARAM_TIMESTAMP:
+		schema = map[string]any{"type": "string", "format": "date-time"}
+	case REST_PARAM_ARRAY:
+		schema = OpenAPIArrayOf(GetOpenAPISchemaByRestHandlerParam(CustomRestHandlerParam{Type: param.Items}))
+	case REST_PARAM_JSON:
+		schema = map[string]any{}
+	default:
+		schema = map[string]any{"type": "string"}
+	}
+	if param.Default != nil {
+		schema["default"] = param.Default
+	}
+	if param.Min != nil {
+		schema["minimum"] = *param.Min
+	}
+	if param.Max != nil {
+		schema["maximum"] = *param.Max
+	}
+	if param.MinLength != nil {
+		if param.Type == REST_PARAM_ARRAY {
+			schema["minItems"] = *param.MinLength
+		} else {
+			schema["minLength"] = *param.MinLength
+		}
+	}
+	if param.MaxLength != nil {
+		if param.Type == REST_PARAM_ARRAY {
+			schema["maxItems"] = *param.MaxLength
+		} else {
+			schema["maxLength"] = *param.MaxLength
+		}
+	}
+	if len(param.Pattern) > 0 {
+		schema["pattern"] = param.Pattern
+	}
+	if len(param.Enum) > 0 {
+		schema["enum"] = param.Enum
+	}
+	return schema
+}
+
+func GetOpenAPISchemaByGraphqlType(graphqlType string) map[string]any {
+	switch graphqlType {
+	case "Int":
+		return map[string]any{"type": "integer"}
+	case "Float":
+		return map[string]any{"type": "number"}
+	case "Boolean":
+		return map[string]any{"type": "boolean"}
+	case "Object":
+		return map[string]any{}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+func GetSortedModelRelations(model *Model) []string {
+	aliases := make([]string, 0, len(model.Relations))
+	for alias := range model.Relations {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+func GetModelColumnNames(model *Model) []string {
+	names := make([]string, 0, len(model.Columns))
+	for _, column := range model.Columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
+
+func BuildOpenAPIRelationSchema(model *Model, alias string, suffix string) map[string]any {
+	relatedModel := (*Model)(model.Relations[alias])
+	ref := OpenAPIRef(GetOpenAPISchemaName(relatedModel.Database, relatedModel.Table, suffix))
+	if info, ok := model.RelationsInfoMap[alias]; ok && info.RelationType == ARRAY {
+		return OpenAPIArrayOf(ref)
+	}
+	return ref
+}
+
+func BuildOpenAPIModelSchemas(model *Model) map[string]any {
+	schemas := make(map[string]any)
+	columnNames := GetModelColumnNames(model)
+
+	rowProperties := map[string]any{
+		CURSOR_KEY:           map[string]any{"type": "string"},
+		TEXT_SEARCH_RANK_KEY: map[string]any{"type": "number"},
+	}
+	insertProperties := make(map[string]any)
+	setProperties := make(map[string]any)
+	numericProperties := make(map[string]any)
+	whereProperties := map[string]any{
+		"_and": OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "bool_exp"))),
+		"_or":  OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "bool_exp"))),
+	}
+	selectProperties := map[string]any{
+		CURSOR_KEY:           map[string]any{"type": "boolean"},
+		TEXT_SEARCH_RANK_KEY: map[string]any{"type": "boolean"},
+	}
+	orderByProperties := make(map[string]any)
+	required := make([]string, 0)
+
+	for _, column := range model.Columns {
+		schema := GetOpenAPISchemaByColumn(column)
+		rowProperties[column.Name] = schema
+		insertProperties[column.Name] = schema
+		setProperties[column.Name] = schema
+		if schemaType, ok := schema["type"]; ok && (schemaType == "integer" || schemaType == "number") {
+			numericProperties[column.Name] = schema
+		}
+		whereProperties[column.Name] = OpenAPIRef("comparison_exp")
+		selectProperties[column.Name] = map[string]any{"type": "boolean"}
+		orderByProperties[column.Name] = OpenAPIRef("order_by")
+		if !column.Nullable && len(column.DefaultValue) == 0 {
+			required = append(required, column.Name)
+		}
+	}
+
+	columnOrderByProperties := make(map[string]any)
+	for key, value := range orderByProperties {
+		columnOrderByProperties[key] = value
+	}
+	orderByProperties[TEXT_SEARCH_RANK_KEY] = OpenAPIRef("order_by")
+
+	for _, computedField := range GetSortedComputedFields(model) {
+		rowProperties[computedField.Name] = GetOpenAPISchemaByColumn(computedField.Column())
+		whereProperties[computedField.Name] = OpenAPIRef("computed_field_comparison_exp")
+		selectProperties[computedField.Name] = OpenAPIRef("computed_field_select")
+		orderByProperties[computedField.Name] = OpenAPIRef("order_by")
+	}
+
+	for _, alias := range GetSortedModelRelations(model) {
+		relatedModel := (*Model)(model.Relations[alias])
+		if info, ok := model.RelationsInfoMap[alias]; ok && info.RelationType == ARRAY {
+			orderByProperties[alias+"_aggregate"] = OpenAPIRef(GetOpenAPISchemaName(relatedModel.Database, relatedModel.Table, "aggregate_order_by"))
+		} else {
+			orderByProperties[alias] = OpenAPIRef(GetOpenAPISchemaName(relatedModel.Database, relatedModel.Table, "order_by"))
+		}
+		rowProperties[alias] = BuildOpenAPIRelationSchema(model, alias, "")
+		if !relatedModel.IsReadOnly() {
+			insertProperties[alias] = map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"objects":    OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(relatedModel.Database, relatedModel.Table, "insert_input"))),
+					"onConflict": OpenAPIRef("on_conflict"),
+				},
+				"required": []string{"objects"},
+			}
+		}
+		whereProperties[alias] = OpenAPIRef(GetOpenAPISchemaName(relatedModel.Database, relatedModel.Table, "bool_exp"))
+		selectProperties[alias] = OpenAPIRef(GetOpenAPISchemaName(relatedModel.Database, relatedModel.Table, "select"))
+	}
+
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "")] = map[string]any{
+		"type":       "object",
+		"properties": rowProperties,
+	}
+
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "bool_exp")] = map[string]any{
+		"type":       "object",
+		"properties": whereProperties,
+	}
+
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "order_by")] = map[string]any{
+		"type":       "object",
+		"properties": orderByProperties,
+	}
+
+	columnOrderBy := map[string]any{
+		"type":       "object",
+		"properties": columnOrderByProperties,
+	}
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "aggregate_order_by")] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"_count": OpenAPIRef("order_by"),
+			"_min":   columnOrderBy,
+			"_max":   columnOrderBy,
+			"_sum":   columnOrderBy,
+			"_avg":   columnOrderBy,
+		},
+	}
+
+	columnEnum := map[string]any{"type": "string", "enum": columnNames}
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "select")] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"_select": map[string]any{
+				"type":       "object",
+				"properties": selectProperties,
+			},
+			"_where": OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "bool_exp")),
+			"_orderBy": map[string]any{
+				"oneOf": []map[string]any{
+					OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "order_by")),
+					OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "order_by"))),
+				},
+				"description": "use the array form to control the ordering priority",
+			},
+			"_groupBy":  OpenAPIArrayOf(columnEnum),
+			"_distinct": OpenAPIArrayOf(columnEnum),
+			"_limit":    map[string]any{"type": "integer", "minimum": 0},
+			"_offset":   map[string]any{"type": "integer", "minimum": 0},
+			"_after":    map[string]any{"type": "string", "nullable": true},
+			"_before":   map[string]any{"type": "string", "nullable": true},
+			"_total":    map[string]any{"type": "boolean", "description": "returns { rows, total } instead of an array"},
+		},
+	}
+
+	if model.IsReadOnly() {
+		return schemas
+	}
+
+	insertInput := map[string]any{
+		"type":       "object",
+		"properties": insertProperties,
+	}
+	if len(required) > 0 {
+		insertInput["required"] = required
+	}
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "insert_input")] = insertInput
+
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "insert")] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"objects":    OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "insert_input"))),
+			"onConflict": OpenAPIRef("on_conflict"),
+		},
+		"required": []string{"objects"},
+	}
+
+	updateProperties := map[string]any{
+		"_where": OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "bool_exp")),
+		"set": map[string]any{
+			"type":       "object",
+			"properties": setProperties,
+		},
+	}
+	for operator := range UPDATE_SELF_REFERENCING_OPERATORS {
+		updateProperties[operator] = map[string]any{
+			"type":       "object",
+			"properties": numericProperties,
+		}
+	}
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "update")] = map[string]any{
+		"type":       "object",
+		"properties": updateProperties,
+	}
+
+	schemas[GetOpenAPISchemaName(model.Database, model.Table, "delete")] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"_where": OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "bool_exp")),
+		},
+	}
+
+	return schemas
+}
+
+func BuildOpenAPISharedSchemas() map[string]any {
+	comparisonProperties := make(map[string]any)
+	for _, operator := range OPENAPI_COMPARISON_OPERATORS {
+		comparisonProperties[operator] = map[string]any{}
+	}
+	for _, operator := range OPENAPI_ARRAY_COMPARISON_OPERATORS {
+		comparisonProperties[operator] = OpenAPIArrayOf(map[string]any{})
+	}
+
+	computedFieldArgs := map[string]any{"type": "object", "additionalProperties": true}
+	computedFieldComparisonProperties := map[string]any{COMPUTED_FIELD_ARGS_KEY: computedFieldArgs}
+	for key, value := range comparisonProperties {
+		computedFieldComparisonProperties[key] = value
+	}
+
+	orderByKeys := make([]string, 0, len(ORDER_BY_KEYS))
+	for key := range ORDER_BY_KEYS {
+		orderByKeys = append(orderByKeys, key)
+	}
+	sort.Strings(orderByKeys)
+
+	return map[string]any{
+		"comparison_exp": map[string]any{
+			"type":       "object",
+			"properties": comparisonProperties,
+		},
+		"computed_field_comparison_exp": map[string]any{
+			"type":       "object",
+			"properties": computedFieldComparisonProperties,
+		},
+		"computed_field_select": map[string]any{
+			"oneOf": []map[string]any{
+				{"type": "boolean"},
+				{
+					"type": "object",
+					"properties": map[string]any{
+						COMPUTED_FIELD_ARGS_KEY: computedFieldArgs,
+					},
+				},
+			},
+		},
+		"order_by": map[string]any{
+			"type": "string",
+			"enum": orderByKeys,
+		},
+		"on_conflict": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"constraints": OpenAPIArrayOf(map[string]any{"type": "string"}),
+				"update": map[string]any{
+					"oneOf": []map[string]any{
+						{"type": "string", "enum": []string{"*"}},
+						OpenAPIArrayOf(map[string]any{"type": "string"}),
+					},
+				},
+			},
+			"required": []string{"constraints"},
+		},
+		"message": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"message": map[string]any{"type": "string"},
+			},
+		},
+		"token": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"token": map[string]any{"type": "string"},
+			},
+		},
+		"auth_body": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"database": map[string]any{"type": "string"},
+				"table":    map[string]any{"type": "string"},
+			},
+			"required":             []string{"database", "table"},
+			"additionalProperties": true,
+		},
+		"engine_user": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"email":     map[string]any{"type": "string", "format": "email"},
+				"password":  map[string]any{"type": "string", "format": "password"},
+				"role_name": map[string]any{"type": "string"},
+			},
+			"required": []string{"email", "password"},
+		},
+	}
+}
+
+func BuildOpenAPIDatabaseSchemas(database string, models []*Model) map[string]any {
+	schemas := make(map[string]any)
+	for _, operation := range []string{"select", "insert", "update", "delete"} {
+		properties := make(map[string]any)
+		for _, model := range models {
+			if operation != "select" && model.IsReadOnly() {
+				continue
+			}
+			properties[model.Table] = OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, operation))
+		}
+		schemas[fmt.Sprintf("%s_%s_body", database, operation)] = map[string]any{
+			"type":       "object",
+			"properties": properties,
+		}
+	}
+
+	resultProperties := make(map[string]any)
+	for _, model := range models {
+		resultProperties[model.Table] = OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "")))
+	}
+	schemas[fmt.Sprintf("%s_result", database)] = map[string]any{
+		"type":       "object",
+		"properties": resultProperties,
+	}
+
+	schemas[fmt.Sprintf("%s_process_body", database)] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"transactions": OpenAPIArrayOf(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"insert": OpenAPIRef(fmt.Sprintf("%s_insert_body", database)),
+					"update": OpenAPIRef(fmt.Sprintf("%s_update_body", database)),
+					"delete": OpenAPIRef(fmt.Sprintf("%s_delete_body", database)),
+				},
+				"minProperties": 1,
+				"maxProperties": 1,
+			}),
+		},
+		"required": []string{"transactions"},
+	}
+	return schemas
+}
+
+func BuildOpenAPIDatabasePaths(database string) map[string]any {
+	security := []map[string][]string{{"bearerAuth": {}}}
+	result := OpenAPIRef(fmt.Sprintf("%s_result", database))
+	return map[string]any{
+		fmt.Sprintf("/%s", database): map[string]any{
+			"post": map[string]any{
+				"tags":        []string{database},
+				"summary":     fmt.Sprintf("Select rows from %s", database),
+				"operationId": fmt.Sprintf("%s_select", database),
+				"security":    security,
+				"requestBody": OpenAPIJsonBody(OpenAPIRef(fmt.Sprintf("%s_select_body", database)), true),
+				"responses": OpenAPIErrorResponses(map[string]any{
+					"200": OpenAPIJsonResponse("Selected rows by table", result),
+				}),
+			},
+		},
+		fmt.Sprintf("/%s/actions", database): map[string]any{
+			"post": map[string]any{
+				"tags":        []string{database},
+				"summary":     fmt.Sprintf("Insert rows into %s", database),
+				"operationId": fmt.Sprintf("%s_insert", database),
+				"security":    security,
+				"requestBody": OpenAPIJsonBody(OpenAPIRef(fmt.Sprintf("%s_insert_body", database)), true),
+				"responses": OpenAPIErrorResponses(map[string]any{
+					"201": OpenAPIJsonResponse("Inserted rows by table", result),
+				}),
+			},
+			"put": map[string]any{
+				"tags":        []string{database},
+				"summary":     fmt.Sprintf("Update rows in %s", database),
+				"operationId": fmt.Sprintf("%s_update", database),
+				"security":    security,
+				"requestBody": OpenAPIJsonBody(OpenAPIRef(fmt.Sprintf("%s_update_body", database)), true),
+				"responses": OpenAPIErrorResponses(map[string]any{
+					"200": OpenAPIJsonResponse("Updated rows by table", result),
+				}),
+			},
+			"delete": map[string]any{
+				"tags":        []string{database},
+				"summary":     fmt.Sprintf("Delete rows from %s", database),
+				"operationId": fmt.Sprintf("%s_delete", database),
+				"security":    security,
+				"requestBody": OpenAPIJsonBody(OpenAPIRef(fmt.Sprintf("%s_delete_body", database)), true),
+				"responses": OpenAPIErrorResponses(map[string]any{
+					"200": OpenAPIJsonResponse("Deleted rows by table", result),
+				}),
+			},
+		},
+		fmt.Sprintf("/%s/process", database): map[string]any{
+			"post": map[string]any{
+				"tags":        []string{database},
+				"summary":     fmt.Sprintf("Run multiple statements against %s in one transaction", database),
+				"operationId": fmt.Sprintf("%s_process", database),
+				"security":    security,
+				"requestBody": OpenAPIJsonBody(OpenAPIRef(fmt.Sprintf("%s_process_body", database)), true),
+				"responses": OpenAPIErrorResponses(map[string]any{
+					"200": OpenAPIJsonResponse("Statement results grouped by operation", map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"insert": OpenAPIArrayOf(result),
+							"update": OpenAPIArrayOf(result),
+							"delete": OpenAPIArrayOf(result),
+						},
+					}),
+				}),
+			},
+		},
+	}
+}
+
+func BuildOpenAPIAuthPaths() map[string]any {
+	token := OpenAPIJsonResponse("Issued token", OpenAPIRef("token"))
+	return map[string]any{
+		"/auth": map[string]any{
+			"get": map[string]any{
+				"tags":        []string{"auth"},
+				"summary":     "Refresh the current token",
+				"operationId": "auth_refresh",
+				"security":    []map[string][]string{{"bearerAuth": {}}},
+				"responses":   OpenAPIErrorResponses(map[string]any{"200": token}),
+			},
+		},
+		"/auth/login": map[string]any{
+			"post": map[string]any{
+				"tags":        []string{"auth"},
+				"summary":     "Log in against a global auth table",
+				"operationId": "auth_login",
+				"requestBody": OpenAPIJsonBody(OpenAPIRef("auth_body"), true),
+				"responses":   OpenAPIErrorResponses(map[string]any{"200": token}),
+			},
+		},
+		"/auth/register": map[string]any{
+			"post": map[string]any{
+				"tags":        []string{"auth"},
+				"summary":     "Register against a global auth table",
+				"operationId": "auth_register",
+				"requestBody": OpenAPIJsonBody(OpenAPIRef("auth_body"), true),
+				"responses":   OpenAPIErrorResponses(map[string]any{"200": token}),
+			},
+		},
+		"/engine/auth/login": map[string]any{
+			"post": map[string]any{
+				"tags":        []string{"engine auth"},
+				"summary":     "Log in as an engine user",
+				"operationId": "engine_auth_login",
+				"requestBody": OpenAPIJsonBody(OpenAPIRef("engine_user"), true),
+				"responses":   OpenAPIErrorResponses(map[string]any{"200": token}),
+			},
+		},
+		"/engine/auth/register": map[string]any{
+			"post": map[string]any{
+				"tags":        []string{"engine auth"},
+				"summary":     "Register an engine user",
+				"operationId": "engine_auth_register",
+				"security":    []map[string][]string{{"bearerAuth": {}}},
+				"requestBody": OpenAPIJsonBody(OpenAPIRef("engine_user"), true),
+				"responses": OpenAPIErrorResponses(map[string]any{
+					"200": OpenAPIJsonResponse("Created user", OpenAPIRef("message")),
+				}),
+			},
+		},
+	}
+}
+
+func GetOpenAPIRestHandlerPath(endpoint string) (string, []string) {
+	segments := strings.Split(strings.Trim(endpoint, "/"), "/")
+	pathParams := make([]string, 0)
+	for i, segment := range segments {
+		if name, ok := GetEndpointPathParameter(segment); ok {
+			segments[i] = fmt.Sprintf("{%s}", name)
+			pathParams = append(pathParams, name)
+		}
+	}
+	return "/" + strings.Join(segments, "/"), pathParams
+}
+
+func BuildOpenAPIRestHandlerOperation(handler CustomRestHandlerInput) map[string]any {
+	path, pathParams := GetOpenAPIRestHandlerPath(handler.Endpoint)
+	declaredParams := make(map[string]CustomRestHandlerParam)
+	for _, param := range handler.Params {
+		declaredParams[param.Name] = param
+	}
+	isPathParam := make(map[string]bool)
+	parameters := make([]map[string]any, 0)
+	for _, name := range pathParams {
+		isPathParam[name] = true
+		schema := map[string]any{"type": "string"}
+		if param, ok := declaredParams[name]; ok {
+			schema = GetOpenAPISchemaByRestHandlerParam(param)
+		}
+		parameters = append(parameters, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   schema,
+		})
+	}
+
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	if len(handler.Params) > 0 {
+		for _, param := range handler.Params {
+			if isPathParam[param.Name] {
+				continue
+			}
+			properties[param.Name] = GetOpenAPISchemaByRestHandlerParam(param)
+			if param.Required && param.Default == nil {
+				required = append(required, param.Name)
+			}
+		}
+	} else {
+		_, names := CompileNamedQuery(handler.Query)
+		for _, name := range names {
+			if isPathParam[name] {
+				continue
+			}
+			properties[name] = map[string]any{}
+		}
+		if len(names) == 0 {
+			properties["args"] = OpenAPIArrayOf(map[string]any{})
+		}
+	}
+
+	rowSchema := map[string]any{"type": "object"}
+	if len(handler.Columns) > 0 {
+		columns := make(map[string]any)
+		for _, column := range handler.Columns {
+			columns[column.Name] = GetOpenAPISchemaByGraphqlType(column.Type)
+		}
+		rowSchema["properties"] = columns
+	}
+
+	operation := map[string]any{
+		"tags":        []string{handler.Database},
+		"summary":     fmt.Sprintf("%s %s", handler.Method, path),
+		"operationId": GetRestHandlerGraphqlName(CustomRestHandlerInput{Database: handler.Database, Endpoint: handler.Endpoint, Method: handler.Method}),
+		"responses": OpenAPIErrorResponses(map[string]any{
+			"200": OpenAPIJsonResponse("Rows returned by the action", OpenAPIArrayOf(rowSchema)),
+		}),
+	}
+	if handler.Auth {
+		operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+	}
+
+	if handler.Method == GET {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		isRequired := make(map[string]bool)
+		for _, name := range required {
+			isRequired[name] = true
+		}
+		for _, name := range names {
+			parameters = append(parameters, map[string]any{
+				"name":     name,
+				"in":       "query",
+				"required": isRequired[name],
+				"schema":   properties[name],
+			})
+		}
+	} else if len(properties) > 0 {
+		body := map[string]any{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			body["required"] = required
+		}
+		operation["requestBody"] = OpenAPIJsonBody(body, len(required) > 0)
+	}
+
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	return operation
+}
+
+func (e *Engine) BuildOpenAPIRestHandlerPaths() map[string]any {
+	paths := make(map[string]any)
+	for _, handler := range e.RestHandlers {
+		if !handler.Enabled {
+			continue
+		}
+		path, _ := GetOpenAPIRestHandlerPath(handler.Endpoint)
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(handler.Method)] = BuildOpenAPIRestHandlerOperation(handler)
+	}
+	return paths
+}
+
+func GetOpenAPISchemaByFunctionTypeName(typeName string) map[string]any {
+	if strings.HasPrefix(typeName, "_") {
+		return OpenAPIArrayOf(GetOpenAPISchemaByGraphqlType(GetGraphqlTypeByDatabaseTypeName(strings.TrimPrefix(typeName, "_"))))
+	}
+	return GetOpenAPISchemaByGraphqlType(GetGraphqlTypeByFunctionTypeName(typeName))
+}
+
+func (e *Engine) BuildOpenAPIFunctionOperation(function DatabaseFunction) map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	for _, arg := range function.Args {
+		properties[arg.Name] = GetOpenAPISchemaByFunctionTypeName(arg.TypeName)
+		if !arg.HasDefault {
+			required = append(required, arg.Name)
+		}
+	}
+	body := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		body["required"] = required
+	}
+
+	result := GetOpenAPISchemaByFunctionTypeName(function.ReturnType)
+	if function.ReturnsRow {
+		result = map[string]any{"type": "object"}
+	}
+	if function.ReturnsSet {
+		result = OpenAPIArrayOf(result)
+	}
+	if model, err := e.GetFunctionModel(function); err == nil {
+		body = map[string]any{
+			"allOf": []map[string]any{body, OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "select"))},
+		}
+		result = OpenAPIArrayOf(OpenAPIRef(GetOpenAPISchemaName(model.Database, model.Table, "")))
+	}
+
+	return map[string]any{
+		"tags":        []string{function.Database},
+		"summary":     fmt.Sprintf("Call %s function %s.%s", strings.ToLower(function.Volatility), function.Database, function.Name),
+		"operationId": fmt.Sprintf("%s_rpc_%s", function.Database, function.Name),
+		"security":    []map[string][]string{{"bearerAuth": {}}},
+		"requestBody": OpenAPIJsonBody(body, len(required) > 0),
+		"responses": OpenAPIErrorResponses(map[string]any{
+			"200": OpenAPIJsonResponse("Function result", result),
+		}),
+	}
+}
+
+func (e *Engine) BuildOpenAPIFunctionPaths() map[string]any {
+	paths := make(map[string]any)
+	for _, function := range e.GetFunctionsList() {
+		paths[fmt.Sprintf("/%s/rpc/%s", function.Database, function.Name)] = map[string]any{
+			"post": e.BuildOpenAPIFunctionOperation(function),
+		}
+	}
+	return paths
+}
+
+func (e *Engine) BuildOpenAPIDocument() OpenAPIDocument {
+	schemas := BuildOpenAPISharedSchemas()
+	paths := BuildOpenAPIAuthPaths()
+
+	databaseToModels := make(map[string][]*Model)
+	for _, model := range e.Models {
+		databaseToModels[model.Database] = append(databaseToModels[model.Database], model)
+		for name, schema := range BuildOpenAPIModelSchemas(model) {
+			schemas[name] = schema
+		}
+	}
+
+	for database, models := range databaseToModels {
+		for name, schema := range BuildOpenAPIDatabaseSchemas(database, models) {
+			schemas[name] = schema
+		}
+		for path, item := range BuildOpenAPIDatabasePaths(database) {
+			paths[path] = item
+		}
+	}
+
+	for path, item := range e.BuildOpenAPIRestHandlerPaths() {
+		paths[path] = item
+	}
+
+	for path, item := range e.BuildOpenAPIFunctionPaths() {
+		paths[path] = item
+	}
+
+	return OpenAPIDocument{
+		"openapi": OPENAPI_VERSION,
+		"info": map[string]any{
+			"title":   "Engine API",
+			"version": e.Version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+	}
+}
+
+func (e *Engine) LoadOpenAPI() {
+	e.OpenAPI = e.BuildOpenAPIDocument()
+}
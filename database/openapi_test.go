@@ -0,0 +1,179 @@
+package database
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func NewGeneratorTestEngine() *Engine {
+	users := &Model{
+		Database: "public",
+		Table:    "users",
+		Columns: []Column{
+			{Name: "id", Type: "bigint", DefaultValue: "nextval('users_id_seq'::regclass)"},
+			{Name: "email", Type: "character varying", MaxLength: 255},
+			{Name: "profile", Type: "jsonb", Nullable: true},
+		},
+		ColumnsMap: ColumnsMap{"id": "bigint", "email": "character varying", "profile": "jsonb"},
+	}
+	posts := &Model{
+		Database: "public",
+		Table:    "posts",
+		Columns: []Column{
+			{Name: "id", Type: "integer", DefaultValue: "nextval('posts_id_seq'::regclass)"},
+			{Name: "author_id", Type: "bigint"},
+			{Name: "published-at", Type: "timestamp with time zone", Nullable: true},
+			{Name: "tags", Type: "text[]", Nullable: true},
+		},
+		ColumnsMap: ColumnsMap{"id": "integer", "author_id": "bigint", "published-at": "timestamp with time zone", "tags": "text[]"},
+	}
+	users.Relations = RelationMap{"posts": posts}
+	users.RelationsInfoMap = RelationInfoMap{"posts": {Alias: "posts", RelationType: ARRAY}}
+	posts.Relations = RelationMap{"author": users}
+	posts.RelationsInfoMap = RelationInfoMap{"author": {Alias: "author", RelationType: OBJECT}}
+
+	return &Engine{
+		Version: "test",
+		Models:  []*Model{users, posts},
+		RestHandlers: []CustomRestHandlerInput{
+			{
+				Database: "public",
+				Enabled:  true,
+				Auth:     true,
+				Method:   GET,
+				Endpoint: "/users/<str:id>/posts",
+				Query:    "SELECT * FROM public.posts WHERE author_id = :id LIMIT :limit",
+				Params: []CustomRestHandlerParam{
+					{Name: "id", Type: REST_PARAM_INT},
+					{Name: "limit", Type: REST_PARAM_INT, Required: true},
+				},
+				Columns: []CustomRestHandlerColumn{{Name: "id", Type: "Int"}},
+			},
+			{
+				Database: "public",
+				Enabled:  false,
+				Method:   POST,
+				Endpoint: "/disabled",
+				Query:    "SELECT 1",
+			},
+		},
+	}
+}
+
+func CollectOpenAPIRefs(value any, refs map[string]bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, entry := range v {
+			if ref, ok := entry.(string); ok && key == "$ref" {
+				refs[ref] = true
+				continue
+			}
+			CollectOpenAPIRefs(entry, refs)
+		}
+	case []any:
+		for _, entry := range v {
+			CollectOpenAPIRefs(entry, refs)
+		}
+	}
+}
+
+func TestGetOpenAPISchemaByColumnType(t *testing.T) {
+	cases := []struct {
+		columnType string
+		maxLength  int64
+		want       map[string]any
+	}{
+		{columnType: "integer", want: map[string]any{"type": "integer", "format": "int32"}},
+		{columnType: "BIGINT", want: map[string]any{"type": "integer", "format": "int64"}},
+		{columnType: "numeric", want: map[string]any{"type": "number"}},
+		{columnType: "uuid", want: map[string]any{"type": "string", "format": "uuid"}},
+		{columnType: "timestamptz", want: map[string]any{"type": "string", "format": "date-time"}},
+		{columnType: "jsonb", want: map[string]any{}},
+		{columnType: "varchar", maxLength: 10, want: map[string]any{"type": "string", "maxLength": int64(10)}},
+		{columnType: "int4[]", want: map[string]any{"type": "array", "items": map[string]any{"type": "integer", "format": "int32"}}},
+	}
+	for _, c := range cases {
+		t.Run(c.columnType, func(t *testing.T) {
+			schema := GetOpenAPISchemaByColumnType(c.columnType, c.maxLength)
+			if !reflect.DeepEqual(schema, c.want) {
+				t.Errorf("expected %v, got %v", c.want, schema)
+			}
+		})
+	}
+}
+
+func TestBuildOpenAPIRestHandlerOperation(t *testing.T) {
+	handlers := NewGeneratorTestEngine().RestHandlers
+	operation := BuildOpenAPIRestHandlerOperation(handlers[0])
+
+	parameters, ok := operation["parameters"].([]map[string]any)
+	if !ok || len(parameters) != 2 {
+		t.Fatalf("expected 2 parameters, got %v", operation["parameters"])
+	}
+	if parameters[0]["name"] != "id" || parameters[0]["in"] != "path" || parameters[0]["required"] != true {
+		t.Errorf("expected a required id path parameter, got %v", parameters[0])
+	}
+	if parameters[1]["name"] != "limit" || parameters[1]["in"] != "query" || parameters[1]["required"] != true {
+		t.Errorf("expected a required limit query parameter, got %v", parameters[1])
+	}
+	if _, ok := operation["security"]; !ok {
+		t.Errorf("expected an authenticated handler to require bearer auth")
+	}
+	if _, ok := operation["requestBody"]; ok {
+		t.Errorf("expected a GET handler to have no request body")
+	}
+
+	post := BuildOpenAPIRestHandlerOperation(CustomRestHandlerInput{
+		Database: "public",
+		Method:   POST,
+		Endpoint: "/users",
+		Query:    "INSERT INTO public.users (email) VALUES (:email)",
+		Params:   []CustomRestHandlerParam{{Name: "email", Type: REST_PARAM_TEXT, Required: true}},
+	})
+	want := OpenAPIJsonBody(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"email": map[string]any{"type": "string"}},
+		"required":   []string{"email"},
+	}, true)
+	if !reflect.DeepEqual(post["requestBody"], want) {
+		t.Errorf("expected request body %v, got %v", want, post["requestBody"])
+	}
+}
+
+func TestBuildOpenAPIDocument(t *testing.T) {
+	document := NewGeneratorTestEngine().BuildOpenAPIDocument()
+	raw, err := json.Marshal(document)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var parsed map[string]any
+	err = json.Unmarshal(raw, &parsed)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	schemas := parsed["components"].(map[string]any)["schemas"].(map[string]any)
+	for _, name := range []string{"public_users", "public_posts_bool_exp", "public_users_insert_input"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("expected schema %s", name)
+		}
+	}
+	refs := make(map[string]bool)
+	CollectOpenAPIRefs(parsed, refs)
+	for ref := range refs {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("unresolved reference %s", ref)
+		}
+	}
+
+	paths := parsed["paths"].(map[string]any)
+	if _, ok := paths["/users/{id}/posts"].(map[string]any)["get"]; !ok {
+		t.Errorf("expected the rest handler path, got %v", paths["/users/{id}/posts"])
+	}
+	if _, ok := paths["/disabled"]; ok {
+		t.Errorf("expected disabled rest handlers to be skipped")
+	}
+}
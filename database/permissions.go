@@ -0,0 +1,628 @@
+package database
+
+import (
+	"application/environment"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var PERMISSION_SELECT string = "select"
+var PERMISSION_INSERT string = "insert"
+var PERMISSION_UPDATE string = "update"
+var PERMISSION_DELETE string = "delete"
+
+var PERMISSION_OPERATIONS = []string{PERMISSION_SELECT, PERMISSION_INSERT, PERMISSION_UPDATE, PERMISSION_DELETE}
+var PERMISSION_ALL_COLUMNS string = "*"
+var PERMISSION_CLAIMS_PREFIX string = "x-claims-"
+var ADMIN_ROLE string = "admin"
+
+type TablePermission struct {
+	Columns []string       `json:"columns"`
+	Filter  map[string]any `json:"filter,omitempty"`
+	Set     map[string]any `json:"set,omitempty"`
+	Limit   int            `json:"limit,omitempty"`
+}
+
+type TablePermissions map[string]TablePermission
+type RolePermissions map[string]map[string]TablePermissions
+type ModelPermissionsMap map[string]TablePermissions
+
+func GetRoleClaimKey() string {
+	return environment.GetEnvValueToStringWithDefault("JWT_ROLE_CLAIM", "role")
+}
+
+func GetClaimsRole(auth jwt.MapClaims) string {
+	if !ShouldEvaluateRLS(auth) {
+		return ADMIN_ROLE
+	}
+	role, ok := auth[GetRoleClaimKey()].(string)
+	if !ok {
+		return ""
+	}
+	return role
+}
+
+func WithClaims(ctx context.Context, auth jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, RequestContextKey("claims"), auth)
+}
+
+func GetContextClaims(ctx context.Context) jwt.MapClaims {
+	claims, ok := ctx.Value(RequestContextKey("claims")).(jwt.MapClaims)
+	if !ok {
+		return jwt.MapClaims{}
+	}
+	return claims
+}
+
+func ParseRolePermissions(raw json.RawMessage) (RolePermissions, error) {
+	permissions := make(RolePermissions)
+	if len(raw) == 0 || string(raw) == "null" {
+		return permissions, nil
+	}
+	err := json.Unmarshal(raw, &permissions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid permissions: %s", err.Error())
+	}
+	return permissions, nil
+}
+
+func GetEngineRoles(db *sql.DB) ([]EngineRole, error) {
+	roles := make([]EngineRole, 0)
+	scanner := Query(db, GET_ENGINE_ROLES)
+	cb := func(rows *sql.Rows) error {
+		var role EngineRole
+		err := rows.Scan(&role.Id, &role.RoleName, &role.Permissions, &role.CreatedAt)
+		if err != nil {
+			return err
+		}
+		roles = append(roles, role)
+		return nil
+	}
+	err := scanner(cb)
+	return roles, err
+}
+
+func (model *Model) LoadModelPermissions(roles []EngineRole) {
+	for _, role := range roles {
+		permissions, err := ParseRolePermissions(role.Permissions)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		tablePermissions, ok := permissions[model.Database][model.Table]
+		if !ok {
+			tablePermissions = make(TablePermissions)
+		}
+		model.Permissions[role.RoleName] = tablePermissions
+	}
+}
+
+func (model *Model) GetPermission(role string, operation string) (*TablePermission, error) {
+	if role == ADMIN_ROLE {
+		return nil, nil
+	}
+	if len(role) == 0 {
+		return nil, NewForbiddenError("no %s claim was provided", GetRoleClaimKey())
+	}
+	permissions, ok := model.Permissions[role]
+	if !ok {
+		return nil, NewForbiddenError("role %s has no permissions for %s %s", role, model.Database, model.Table)
+	}
+	permission, ok := permissions[operation]
+	if !ok {
+		return nil, NewForbiddenError("role %s has no %s permission for %s %s", role, operation, model.Database, model.Table)
+	}
+	return &permission, nil
+}
+
+func (permission TablePermission) AllowedColumns(model *Model) ColumnsMap {
+	columns := make(ColumnsMap)
+	for _, column := range permission.Columns {
+		if column == PERMISSION_ALL_COLUMNS {
+			return model.ColumnsMap
+		}
+		if columnType, ok := model.ColumnsMap[column]; ok {
+			columns[column] = columnType
+		}
+	}
+	for column := range permission.Set {
+		if columnType, ok := model.ColumnsMap[column]; ok {
+			columns[column] = columnType
+		}
+	}
+	return columns
+}
+
+func (permission TablePermission) AllowsColumn(model *Model, column string) bool {
+	if model.isComputedField(column) {
+		for _, entry := range permission.Columns {
+			if entry == PERMISSION_ALL_COLUMNS || entry == column {
+				return true
+			}
+		}
+		return false
+	}
+	_, ok := permission.AllowedColumns(model)[column]
+	return ok
+}
+
+func (model *Model) GetRelationSelectPermission(role string, key string) (*Model, *TablePermission, error) {
+	relatedModel, err := model.GetModelRelation(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	permission, err := relatedModel.GetPermission(role, PERMISSION_SELECT)
+	return relatedModel, permission, err
+}
+
+func (model *Model) ValidateRelationAggregate(role string, key string, value any) error {
+	relatedModel, permission, err := model.GetRelationSelectPermission(role, key)
+	if err != nil || permission == nil {
+		return err
+	}
+	if len(permission.Filter) > 0 {
+		return NewForbiddenError("role %s can't use %s because rows of %s %s are filtered", role, key, relatedModel.Database, relatedModel.Table)
+	}
+	for _, aggregation := range GetOrderByEntries(value) {
+		if aggregation.Key == "_count" {
+			continue
+		}
+		for _, column := range GetOrderByEntries(aggregation.Value) {
+			if !permission.AllowsColumn(relatedModel, column.Key) {
+				return NewForbiddenError("role %s can't use column %s of %s %s", role, column.Key, relatedModel.Database, relatedModel.Table)
+			}
+		}
+	}
+	return nil
+}
+
+func (model *Model) RestrictWhere(role string, permission *TablePermission, where any, claims jwt.MapClaims) (any, error) {
+	if entries, err := IsArray(where); err == nil {
+		restricted := make([]any, 0, len(entries))
+		for _, entry := range entries {
+			restrictedEntry, err := model.RestrictWhere(role, permission, entry, claims)
+			if err != nil {
+				return nil, err
+			}
+			restricted = append(restricted, restrictedEntry)
+		}
+		return restricted, nil
+	}
+	operation, err := IsMapToInterface(where)
+	if err != nil {
+		return where, nil
+	}
+	restricted := make(map[string]any)
+	for key, value := range operation {
+		if model.isModelColumn(key) || model.isComputedField(key) {
+			if !permission.AllowsColumn(model, key) {
+				return nil, NewForbiddenError("role %s can't filter by column %s of %s %s", role, key, model.Database, model.Table)
+			}
+		} else if _, ok := QUERY_BINDER_KEYS[key]; ok {
+			value, err = model.RestrictWhere(role, permission, value, claims)
+			if err != nil {
+				return nil, err
+			}
+		} else if model.isRelationColumnWithAggregation(key) {
+			err := model.ValidateRelationAggregate(role, key, value)
+			if err != nil {
+				return nil, err
+			}
+		} else if model.isRelationColumn(key) {
+			relatedModel, relatedPermission, err := model.GetRelationSelectPermission(role, key)
+			if err != nil {
+				return nil, err
+			}
+			if relatedPermission != nil {
+				value, err = relatedModel.RestrictWhere(role, relatedPermission, value, claims)
+				if err != nil {
+					return nil, err
+				}
+				value, err = relatedPermission.MergeFilter(value, claims)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		restricted[key] = value
+	}
+	return restricted, nil
+}
+
+func (model *Model) ValidateOrderByPermission(role string, permission *TablePermission, orderBy any) error {
+	for _, entry := range GetOrderByEntries(orderBy) {
+		if entry.Key == TEXT_SEARCH_RANK_KEY {
+			continue
+		}
+		if model.isModelColumn(entry.Key) || model.isComputedField(entry.Key) {
+			if !permission.AllowsColumn(model, entry.Key) {
+				return NewForbiddenError("role %s can't order by column %s of %s %s", role, entry.Key, model.Database, model.Table)
+			}
+		} else if model.isRelationColumnWithAggregation(entry.Key) {
+			err := model.ValidateRelationAggregate(role, entry.Key, entry.Value)
+			if err != nil {
+				return err
+			}
+		} else if model.isRelationColumn(entry.Key) {
+			relatedModel, relatedPermission, err := model.GetRelationSelectPermission(role, entry.Key)
+			if err != nil || relatedPermission == nil {
+				return err
+			}
+			if len(relatedPermission.Filter) > 0 {
+				return NewForbiddenError("role %s can't order by %s because rows of %s %s are filtered", role, entry.Key, relatedModel.Database, relatedModel.Table)
+			}
+			err = relatedModel.ValidateOrderByPermission(role, relatedPermission, entry.Value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (model *Model) ValidateSelectPermission(role string, permission *TablePermission, body map[string]any) error {
+	err := model.ValidateOrderByPermission(role, permission, body["_orderBy"])
+	if err != nil {
+		return err
+	}
+	for _, key := range []string{"_distinct", "_groupBy"} {
+		columns, _ := IsArray(body[key])
+		for _, column := range columns {
+			if name, ok := column.(string); ok && !permission.AllowsColumn(model, name) {
+				return NewForbiddenError("role %s can't use column %s of %s %s in %s", role, name, model.Database, model.Table, key)
+			}
+		}
+	}
+	for name := range model.GetSelectedComputedFields(body) {
+		if !permission.AllowsColumn(model, name) {
+			return NewForbiddenError("role %s can't select computed field %s of %s %s", role, name, model.Database, model.Table)
+		}
+	}
+	if IsCursorPagination(body) {
+		columns, err := model.GetCursorOrderColumns(body)
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			if !permission.AllowsColumn(model, column.Name) {
+				return NewForbiddenError("role %s can't paginate %s %s by column %s", role, model.Database, model.Table, column.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func ResolveClaimsVariables(value any, claims jwt.MapClaims) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.HasPrefix(strings.ToLower(v), PERMISSION_CLAIMS_PREFIX) {
+			return v, nil
+		}
+		key := strings.ReplaceAll(strings.ToLower(v[len(PERMISSION_CLAIMS_PREFIX):]), "-", "_")
+		claim, ok := claims[key]
+		if !ok {
+			return nil, fmt.Errorf("claim %s is required by permission", key)
+		}
+		return claim, nil
+	case map[string]any:
+		resolved := make(map[string]any)
+		for key, entry := range v {
+			resolvedEntry, err := ResolveClaimsVariables(entry, claims)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = resolvedEntry
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, 0, len(v))
+		for _, entry := range v {
+			resolvedEntry, err := ResolveClaimsVariables(entry, claims)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, resolvedEntry)
+		}
+		return resolved, nil
+	}
+	return value, nil
+}
+
+func (permission TablePermission) MergeFilter(where any, claims jwt.MapClaims) (any, error) {
+	if len(permission.Filter) == 0 {
+		return where, nil
+	}
+	filter, err := ResolveClaimsVariables(permission.Filter, claims)
+	if err != nil {
+		return nil, err
+	}
+	if parsedWhere, err := IsMapToInterface(where); where == nil || (err == nil && len(parsedWhere) == 0) {
+		return filter, nil
+	}
+	return map[string]any{"_and": []any{where, filter}}, nil
+}
+
+func GetLimitValue(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case json.Number:
+		limit, err := v.Int64()
+		return int(limit), err == nil
+	}
+	return 0, false
+}
+
+func (model *Model) ApplyPermissionFilter(role string, operation string, where any, claims jwt.MapClaims) (any, error) {
+	permission, err := model.GetPermission(role, operation)
+	if err != nil || permission == nil {
+		return where, err
+	}
+	selectPermission, err := model.GetPermission(role, PERMISSION_SELECT)
+	if err != nil {
+		selectPermission = &TablePermission{}
+	}
+	where, err = model.RestrictWhere(role, selectPermission, where, claims)
+	if err != nil {
+		return nil, err
+	}
+	return permission.MergeFilter(where, claims)
+}
+
+func (model *Model) ApplySelectPermission(auth jwt.MapClaims, body any, withLimit bool) (any, error) {
+	permission, err := model.GetPermission(GetClaimsRole(auth), PERMISSION_SELECT)
+	if err != nil || permission == nil {
+		return body, err
+	}
+	role := GetClaimsRole(auth)
+	permittedBody := make(map[string]any)
+	if parsedBody, err := IsMapToInterface(body); err == nil {
+		for key, value := range parsedBody {
+			permittedBody[key] = value
+		}
+	}
+	err = model.ValidateSelectPermission(role, permission, permittedBody)
+	if err != nil {
+		return nil, err
+	}
+	where, err := model.RestrictWhere(role, permission, permittedBody["_where"], auth)
+	if err != nil {
+		return nil, err
+	}
+	where, err = permission.MergeFilter(where, auth)
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		permittedBody["_where"] = where
+	}
+	if withLimit && permission.Limit > 0 {
+		limit, ok := GetLimitValue(permittedBody["_limit"])
+		if !ok || limit > permission.Limit {
+			permittedBody["_limit"] = permission.Limit
+		}
+	}
+	return permittedBody, nil
+}
+
+func (model *Model) ApplySubscriptionPermission(auth jwt.MapClaims, where any) (any, ColumnsMap, error) {
+	role := GetClaimsRole(auth)
+	permission, err := model.GetPermission(role, PERMISSION_SELECT)
+	if err != nil || permission == nil {
+		return where, nil, err
+	}
+	where, err = model.RestrictWhere(role, permission, where, auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	where, err = permission.MergeFilter(where, auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	return where, permission.AllowedColumns(model), nil
+}
+
+func ProjectRows(value any, columns ColumnsMap) any {
+	entries, err := IsArray(value)
+	if err != nil || columns == nil {
+		return value
+	}
+	projected := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		row, err := IsMapToInterface(entry)
+		if err != nil {
+			projected = append(projected, entry)
+			continue
+		}
+		projectedRow := make(map[string]any)
+		for key, rowValue := range row {
+			if _, ok := columns[key]; ok {
+				projectedRow[key] = rowValue
+			}
+		}
+		projected = append(projected, projectedRow)
+	}
+	return projected
+}
+
+func (model *Model) GetSelectableColumns(role string) ColumnsMap {
+	permission, err := model.GetPermission(role, PERMISSION_SELECT)
+	if err != nil {
+		return ColumnsMap{}
+	}
+	if permission == nil {
+		return nil
+	}
+	return permission.AllowedColumns(model)
+}
+
+func (model *Model) ProjectMutationRows(role string, entries []any) []any {
+	columns := model.GetSelectableColumns(role)
+	projected := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		row, err := IsMapToInterface(entry)
+		if err != nil {
+			projected = append(projected, entry)
+			continue
+		}
+		projectedRow := make(map[string]any)
+		for key, rowValue := range row {
+			if relatedModel, err := model.GetModelRelation(key); err == nil && !model.isModelColumn(key) {
+				if relatedRows, err := IsArray(rowValue); err == nil {
+					projectedRow[key] = relatedModel.ProjectMutationRows(role, relatedRows)
+				}
+				continue
+			}
+			if _, ok := columns[key]; columns == nil || ok {
+				projectedRow[key] = rowValue
+			}
+		}
+		projected = append(projected, projectedRow)
+	}
+	return projected
+}
+
+func (e *Engine) ProjectMutationResults(role string, database string, results any) any {
+	parsedResults, ok := results.(map[string][]interface{})
+	if !ok {
+		return results
+	}
+	projected := make(map[string][]interface{})
+	for key, rows := range parsedResults {
+		model, err := e.GetModelByKey(database, key)
+		if err != nil {
+			projected[key] = rows
+			continue
+		}
+		projected[key] = model.ProjectMutationRows(role, rows)
+	}
+	return projected
+}
+
+func (model *Model) ApplyInsertPermission(role string, body any, claims jwt.MapClaims) (any, error) {
+	permission, err := model.GetPermission(role, PERMISSION_INSERT)
+	if err != nil || permission == nil {
+		return body, err
+	}
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return body, nil
+	}
+	allowedColumns := permission.AllowedColumns(model)
+	permittedBody := make(map[string]any)
+	for key, value := range parsedBody {
+		if _, ok := allowedColumns[key]; model.isModelColumn(key) && !ok {
+			return nil, NewForbiddenError("role %s can't insert column %s of %s %s", role, key, model.Database, model.Table)
+		}
+		permittedBody[key] = value
+	}
+	for key, value := range permission.Set {
+		resolved, err := ResolveClaimsVariables(value, claims)
+		if err != nil {
+			return nil, err
+		}
+		permittedBody[key] = resolved
+	}
+	return permittedBody, nil
+}
+
+func (e *Engine) ValidateRolePermissions(input EngineRole) (EngineRole, error) {
+	if len(strings.TrimSpace(input.RoleName)) == 0 {
+		return input, fmt.Errorf("please provide a role name")
+	}
+	permissions, err := ParseRolePermissions(input.Permissions)
+	if err != nil {
+		return input, err
+	}
+	if input.RoleName == ADMIN_ROLE && len(permissions) > 0 {
+		return input, fmt.Errorf("role %s bypasses permissions and can't have any", ADMIN_ROLE)
+	}
+	for database, tables := range permissions {
+		for table, tablePermissions := range tables {
+			model, ok := e.DatabaseToTableToModelMap[database][table]
+			if !ok {
+				return input, fmt.Errorf("table %s doesn't exist for database %s", table, database)
+			}
+			for operation, permission := range tablePermissions {
+				if !IsPermissionOperation(operation) {
+					return input, fmt.Errorf("invalid permission operation %s, expected one of %s", operation, strings.Join(PERMISSION_OPERATIONS, ", "))
+				}
+				if operation != PERMISSION_SELECT && model.IsReadOnly() {
+					return input, fmt.Errorf("%s %s is read-only", database, table)
+				}
+				for _, column := range permission.Columns {
+					if column != PERMISSION_ALL_COLUMNS && !model.isModelColumn(column) && !model.isComputedField(column) {
+						return input, fmt.Errorf("column %s doesn't exist for table %s", column, table)
+					}
+				}
+				if len(permission.Set) > 0 && operation != PERMISSION_INSERT {
+					return input, fmt.Errorf("column presets are only supported for %s permissions", PERMISSION_INSERT)
+				}
+				for column := range permission.Set {
+					if !model.isModelColumn(column) {
+						return input, fmt.Errorf("column %s doesn't exist for table %s", column, table)
+					}
+				}
+				if len(permission.Filter) > 0 && operation == PERMISSION_INSERT {
+					return input, fmt.Errorf("row filters are not supported for %s permissions", PERMISSION_INSERT)
+				}
+				if permission.Limit < 0 || (permission.Limit > 0 && operation != PERMISSION_SELECT) {
+					return input, fmt.Errorf("limit should be a positive number for %s permissions only", PERMISSION_SELECT)
+				}
+			}
+		}
+	}
+	input.Permissions, err = json.Marshal(permissions)
+	return input, err
+}
+
+func IsPermissionOperation(operation string) bool {
+	for _, entry := range PERMISSION_OPERATIONS {
+		if entry == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) CreateRole(db *sql.DB, input EngineRole) error {
+	_, err := db.Exec(CREATE_ENGINE_ROLE_WITH_PERMISSIONS, input.RoleName, string(input.Permissions))
+	return err
+}
+
+func (e *Engine) UpdateRolePermissions(db *sql.DB, input EngineRole) error {
+	result, err := db.Exec(UPDATE_ENGINE_ROLE_PERMISSIONS, string(input.Permissions), input.RoleName)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("role %s doesn't exist", input.RoleName))
+}
+
+func (e *Engine) DeleteRole(db *sql.DB, input EngineRole) error {
+	if input.RoleName == ADMIN_ROLE {
+		return NewBadRequestError("role %s can't be deleted", ADMIN_ROLE)
+	}
+	var users int
+	err := db.QueryRow(COUNT_ENGINE_ROLE_USERS, input.RoleName).Scan(&users)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return &RequestError{StatusCode: http.StatusConflict, Message: fmt.Sprintf("role %s is still assigned to %d users", input.RoleName, users)}
+	}
+	result, err := db.Exec(DELETE_ENGINE_ROLE, input.RoleName)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("role %s doesn't exist", input.RoleName))
+}
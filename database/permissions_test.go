@@ -0,0 +1,289 @@
+package database
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestResolveClaimsVariables(t *testing.T) {
+	claims := jwt.MapClaims{"sub": "42", "org_id": 7.0}
+	value := map[string]any{
+		"author_id": map[string]any{"_eq": "X-Claims-Sub"},
+		"org_id":    map[string]any{"_in": []any{"x-claims-org-id", 1.0}},
+		"status":    map[string]any{"_eq": "published"},
+	}
+	resolved, err := ResolveClaimsVariables(value, claims)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := map[string]any{
+		"author_id": map[string]any{"_eq": "42"},
+		"org_id":    map[string]any{"_in": []any{7.0, 1.0}},
+		"status":    map[string]any{"_eq": "published"},
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Errorf("expected %v, got %v", want, resolved)
+	}
+
+	_, err = ResolveClaimsVariables(map[string]any{"team_id": map[string]any{"_eq": "x-claims-team-id"}}, claims)
+	if err == nil || err.Error() != "claim team_id is required by permission" {
+		t.Errorf("expected a missing claim error, got %v", err)
+	}
+}
+
+func TestMergeFilter(t *testing.T) {
+	claims := jwt.MapClaims{"sub": "42"}
+	permission := TablePermission{Filter: map[string]any{"author_id": map[string]any{"_eq": "x-claims-sub"}}}
+	filter := map[string]any{"author_id": map[string]any{"_eq": "42"}}
+	where := map[string]any{"title": map[string]any{"_eq": "a"}}
+	cases := []struct {
+		name       string
+		permission TablePermission
+		where      any
+		want       any
+	}{
+		{name: "no filter", permission: TablePermission{}, where: where, want: where},
+		{name: "no where", permission: permission, where: nil, want: filter},
+		{name: "empty where", permission: permission, where: map[string]any{}, want: filter},
+		{name: "combined", permission: permission, where: where, want: map[string]any{"_and": []any{where, filter}}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			merged, err := c.permission.MergeFilter(c.where, claims)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(merged, c.want) {
+				t.Errorf("expected %v, got %v", c.want, merged)
+			}
+		})
+	}
+}
+
+func NewPermissionsTestModel() *Model {
+	model := &Model{
+		Database:    "public",
+		Table:       "posts",
+		ColumnsMap:  ColumnsMap{"id": "integer", "title": "text", "author_id": "text"},
+		Permissions: make(ModelPermissionsMap),
+	}
+	model.LoadModelPermissions([]EngineRole{
+		{RoleName: "editor", Permissions: json.RawMessage(`{"public":{"posts":{"select":{"columns":["*"],"filter":{"author_id":{"_eq":"x-claims-sub"}},"limit":10}}}}`)},
+		{RoleName: "viewer", Permissions: json.RawMessage(`{"public":{"comments":{"select":{"columns":["*"]}}}}`)},
+	})
+	return model
+}
+
+func TestGetPermission(t *testing.T) {
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	model := NewPermissionsTestModel()
+	cases := []struct {
+		name      string
+		role      string
+		operation string
+		allowed   bool
+	}{
+		{name: "admin", role: ADMIN_ROLE, operation: PERMISSION_DELETE, allowed: true},
+		{name: "granted operation", role: "editor", operation: PERMISSION_SELECT, allowed: true},
+		{name: "missing operation", role: "editor", operation: PERMISSION_INSERT},
+		{name: "role without table entry", role: "viewer", operation: PERMISSION_SELECT},
+		{name: "unknown role", role: "guest", operation: PERMISSION_SELECT},
+		{name: "no role", role: "", operation: PERMISSION_SELECT},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := model.GetPermission(c.role, c.operation)
+			if c.allowed {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if GetErrorStatusCode(err, 0) != http.StatusForbidden {
+				t.Errorf("expected a forbidden error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplySelectPermission(t *testing.T) {
+	t.Setenv("DISABLE_AUTH", "")
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	model := NewPermissionsTestModel()
+	body := map[string]any{"_where": map[string]any{"title": map[string]any{"_eq": "a"}}, "_limit": 50.0}
+
+	for _, auth := range []jwt.MapClaims{{"role": "guest"}, {"role": "viewer"}, {"sub": "42"}} {
+		_, err := model.ApplySelectPermission(auth, body, true)
+		if GetErrorStatusCode(err, 0) != http.StatusForbidden {
+			t.Errorf("expected a forbidden error for %v, got %v", auth, err)
+		}
+	}
+
+	permitted, err := model.ApplySelectPermission(jwt.MapClaims{"role": "editor", "sub": "42"}, body, true)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := map[string]any{
+		"_where": map[string]any{"_and": []any{
+			map[string]any{"title": map[string]any{"_eq": "a"}},
+			map[string]any{"author_id": map[string]any{"_eq": "42"}},
+		}},
+		"_limit": 10,
+	}
+	if !reflect.DeepEqual(permitted, want) {
+		t.Errorf("expected %v, got %v", want, permitted)
+	}
+
+	permitted, err = model.ApplySelectPermission(jwt.MapClaims{"role": "guest", "bypass_auth": true}, body, true)
+	if err != nil || !reflect.DeepEqual(permitted, body) {
+		t.Errorf("expected bypassed claims to keep the body, got %v %v", permitted, err)
+	}
+}
+
+func NewMutationPermissionsTestModel() *Model {
+	model := &Model{
+		Database:    "public",
+		Table:       "posts",
+		Columns:     []Column{{Name: "id"}, {Name: "title"}, {Name: "author_id"}, {Name: "secret"}},
+		ColumnsMap:  ColumnsMap{"id": "integer", "title": "text", "author_id": "text", "secret": "text"},
+		Permissions: make(ModelPermissionsMap),
+	}
+	model.LoadModelPermissions([]EngineRole{
+		{RoleName: "editor", Permissions: json.RawMessage(`{"public":{"posts":{
+			"select":{"columns":["id","title","author_id"]},
+			"insert":{"columns":["*"]},
+			"update":{"columns":["title"],"filter":{"author_id":{"_eq":"x-claims-sub"}}},
+			"delete":{"columns":["*"],"filter":{"author_id":{"_eq":"x-claims-sub"}}}
+		}}}`)},
+		{RoleName: "writer", Permissions: json.RawMessage(`{"public":{"posts":{"insert":{"columns":["*"]}}}}`)},
+	})
+	return model
+}
+
+func TestBuildOnConflictPermission(t *testing.T) {
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	model := NewMutationPermissionsTestModel()
+	claims := jwt.MapClaims{"sub": "42"}
+	cases := []struct {
+		name       string
+		role       string
+		onConflict map[string]any
+		want       string
+		wantArgs   []interface{}
+		wantStatus int
+	}{
+		{
+			name:       "all columns are limited to updatable ones",
+			role:       "editor",
+			onConflict: map[string]any{"constraints": []any{"id"}, "update": "*"},
+			want:       "ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title WHERE   posts.author_id  = $3 ",
+			wantArgs:   []interface{}{"42"},
+		},
+		{
+			name:       "listed columns",
+			role:       "editor",
+			onConflict: map[string]any{"constraints": []any{"id"}, "update": []any{"title"}},
+			want:       "ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title WHERE   posts.author_id  = $3 ",
+			wantArgs:   []interface{}{"42"},
+		},
+		{
+			name:       "listed column without update permission",
+			role:       "editor",
+			onConflict: map[string]any{"constraints": []any{"id"}, "update": []any{"secret"}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "role without update permission",
+			role:       "writer",
+			onConflict: map[string]any{"constraints": []any{"id"}, "update": "*"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "role without update permission can ignore conflicts",
+			role:       "writer",
+			onConflict: map[string]any{"constraints": []any{"id"}, "ignore": true},
+			want:       "ON CONFLICT (id) DO NOTHING",
+			wantArgs:   []interface{}{},
+		},
+		{
+			name:       "admin",
+			role:       ADMIN_ROLE,
+			onConflict: map[string]any{"constraints": []any{"id"}, "update": []any{"secret"}},
+			want:       "ON CONFLICT (id) DO UPDATE SET secret = EXCLUDED.secret",
+			wantArgs:   []interface{}{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			idx := 3
+			query, args, err := model.BuildOnConflict(c.role, c.onConflict, claims, &idx)
+			if c.wantStatus > 0 {
+				if GetErrorStatusCode(err, 0) != c.wantStatus {
+					t.Fatalf("expected status %d, got %v", c.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if query != c.want {
+				t.Errorf("expected query %q, got %q", c.want, query)
+			}
+			if !reflect.DeepEqual(args, c.wantArgs) {
+				t.Errorf("expected args %v, got %v", c.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestApplyPermissionFilterRestrictsHiddenColumns(t *testing.T) {
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	model := NewMutationPermissionsTestModel()
+	claims := jwt.MapClaims{"sub": "42"}
+
+	for _, operation := range []string{PERMISSION_UPDATE, PERMISSION_DELETE} {
+		_, err := model.ApplyPermissionFilter("editor", operation, map[string]any{"_or": []any{map[string]any{"secret": map[string]any{"_eq": "a"}}}}, claims)
+		if GetErrorStatusCode(err, 0) != http.StatusForbidden {
+			t.Errorf("expected filtering %s by a hidden column to be forbidden, got %v", operation, err)
+		}
+	}
+
+	where, err := model.ApplyPermissionFilter("editor", PERMISSION_DELETE, map[string]any{"title": map[string]any{"_eq": "a"}}, claims)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	want := map[string]any{"_and": []any{
+		map[string]any{"title": map[string]any{"_eq": "a"}},
+		map[string]any{"author_id": map[string]any{"_eq": "42"}},
+	}}
+	if !reflect.DeepEqual(where, want) {
+		t.Errorf("expected %v, got %v", want, where)
+	}
+}
+
+func TestProjectMutationRows(t *testing.T) {
+	t.Setenv("JWT_ROLE_CLAIM", "")
+	model := NewMutationPermissionsTestModel()
+	rows := []any{map[string]any{"id": 1, "title": "a", "author_id": "42", "secret": "s"}}
+
+	cases := []struct {
+		role string
+		want []any
+	}{
+		{role: "editor", want: []any{map[string]any{"id": 1, "title": "a", "author_id": "42"}}},
+		{role: "writer", want: []any{map[string]any{}}},
+		{role: ADMIN_ROLE, want: rows},
+	}
+	for _, c := range cases {
+		t.Run(c.role, func(t *testing.T) {
+			projected := model.ProjectMutationRows(c.role, rows)
+			if !reflect.DeepEqual(projected, c.want) {
+				t.Errorf("expected %v, got %v", c.want, projected)
+			}
+		})
+	}
+}
@@ -0,0 +1,864 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var SDK_TYPESCRIPT string = "typescript"
+var SDK_GO string = "go"
+
+var SDK_DEFAULT_GO_PACKAGE string = "engineclient"
+
+var SDK_OPERATOR_VALUE string = "VALUE"
+var SDK_OPERATOR_ARRAY string = "ARRAY"
+var SDK_OPERATOR_NULL string = "NULL"
+var SDK_OPERATOR_TEXT string = "TEXT"
+var SDK_OPERATOR_TEXT_ARRAY string = "TEXT_ARRAY"
+var SDK_OPERATOR_JSON string = "JSON"
+
+var SDK_IDENTIFIER_SPLIT_PATTERN *regexp.Regexp = regexp.MustCompile("[^a-zA-Z0-9]+")
+var SDK_TYPESCRIPT_IDENTIFIER_PATTERN *regexp.Regexp = regexp.MustCompile("^[A-Za-z_$][A-Za-z0-9_$]*$")
+var SDK_GO_PACKAGE_PATTERN *regexp.Regexp = regexp.MustCompile("^[a-z][a-z0-9_]*$")
+
+type SDKModel struct {
+	Model         *Model
+	TypeName      string
+	Relations     []string
+	NumericFields []Column
+}
+
+func GetSDKOperatorKind(operator string) string {
+	switch operator {
+	case "_is", "_is_not":
+		return SDK_OPERATOR_NULL
+	case "_like", "_ilike", "_text_search", "_text_search_config", "_key_exists":
+		return SDK_OPERATOR_TEXT
+	case "_key_exists_any", "_key_exists_all":
+		return SDK_OPERATOR_TEXT_ARRAY
+	case "_contains", "_contained_in":
+		return SDK_OPERATOR_JSON
+	}
+	if _, ok := REQUIRE_ARRAY_TRANSFORMATION_KEYS[operator]; ok {
+		return SDK_OPERATOR_ARRAY
+	}
+	return SDK_OPERATOR_VALUE
+}
+
+func GetSDKComparisonOperators() []string {
+	operators := make([]string, 0, len(WHERE_CLAUSE_KEYS))
+	for operator := range WHERE_CLAUSE_KEYS {
+		if _, ok := QUERY_BINDER_KEYS[operator]; ok {
+			continue
+		}
+		operators = append(operators, operator)
+	}
+	operators = append(operators, TEXT_SEARCH_CONFIG_KEY)
+	sort.Strings(operators)
+	return operators
+}
+
+func GetSDKOrderByDirections() []string {
+	directions := make([]string, 0, len(ORDER_BY_KEYS))
+	for direction := range ORDER_BY_KEYS {
+		directions = append(directions, direction)
+	}
+	sort.Strings(directions)
+	return directions
+}
+
+func GetSDKTypeName(parts ...string) string {
+	name := ""
+	for _, part := range parts {
+		for _, chunk := range SDK_IDENTIFIER_SPLIT_PATTERN.Split(part, -1) {
+			if len(chunk) == 0 {
+				continue
+			}
+			name += strings.ToUpper(chunk[:1]) + chunk[1:]
+		}
+	}
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		name = "T" + name
+	}
+	return name
+}
+
+func GetSDKUniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func IsSDKNumericSchema(schema map[string]any) bool {
+	schemaType := schema["type"]
+	return schemaType == "integer" || schemaType == "number"
+}
+
+func (e *Engine) GetSDKModels() ([]string, map[string][]SDKModel) {
+	models := make([]*Model, len(e.Models))
+	copy(models, e.Models)
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Database != models[j].Database {
+			return models[i].Database < models[j].Database
+		}
+		return models[i].Table < models[j].Table
+	})
+
+	databases := make([]string, 0)
+	databaseToModels := make(map[string][]SDKModel)
+	for _, model := range models {
+		if _, ok := databaseToModels[model.Database]; !ok {
+			databases = append(databases, model.Database)
+		}
+		numericFields := make([]Column, 0)
+		for _, column := range model.Columns {
+			if IsSDKNumericSchema(GetOpenAPISchemaByColumnType(column.Type, column.MaxLength)) {
+				numericFields = append(numericFields, column)
+			}
+		}
+		databaseToModels[model.Database] = append(databaseToModels[model.Database], SDKModel{
+			Model:         model,
+			TypeName:      GetSDKTypeName(model.Database, model.Table),
+			Relations:     GetSortedModelRelations(model),
+			NumericFields: numericFields,
+		})
+	}
+	return databases, databaseToModels
+}
+
+func GetSDKRelatedModel(model *Model, alias string) (*Model, bool) {
+	relatedModel := (*Model)(model.Relations[alias])
+	info, ok := model.RelationsInfoMap[alias]
+	return relatedModel, ok && info.RelationType == ARRAY
+}
+
+func (e *Engine) GenerateSDK(language string, packageName string) (string, error) {
+	switch strings.ToLower(language) {
+	case SDK_TYPESCRIPT, "ts":
+		return e.GenerateTypescriptSDK(), nil
+	case SDK_GO:
+		if len(packageName) == 0 {
+			packageName = SDK_DEFAULT_GO_PACKAGE
+		}
+		if !SDK_GO_PACKAGE_PATTERN.MatchString(packageName) {
+			return "", fmt.Errorf("invalid go package name %s", packageName)
+		}
+		return e.GenerateGoSDK(packageName)
+	default:
+		return "", fmt.Errorf("not supported sdk language %s", language)
+	}
+}
+
+func GetTypescriptPropertyName(name string) string {
+	if SDK_TYPESCRIPT_IDENTIFIER_PATTERN.MatchString(name) {
+		return name
+	}
+	quoted, _ := json.Marshal(name)
+	return string(quoted)
+}
+
+func GetTypescriptTypeBySchema(schema map[string]any) string {
+	switch schema["type"] {
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "string":
+		return "string"
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return fmt.Sprintf("Array<%s>", GetTypescriptTypeBySchema(items))
+	default:
+		return "unknown"
+	}
+}
+
+func GetTypescriptTypeByColumn(column Column) string {
+	return GetTypescriptTypeBySchema(GetOpenAPISchemaByColumnType(column.Type, column.MaxLength))
+}
+
+func GetTypescriptOperatorType(operator string) string {
+	switch GetSDKOperatorKind(operator) {
+	case SDK_OPERATOR_NULL:
+		return "null | boolean"
+	case SDK_OPERATOR_TEXT:
+		return "string"
+	case SDK_OPERATOR_TEXT_ARRAY:
+		return "string[]"
+	case SDK_OPERATOR_JSON:
+		return "unknown"
+	case SDK_OPERATOR_ARRAY:
+		return "T[]"
+	default:
+		return "T"
+	}
+}
+
+func WriteTypescriptModel(builder *strings.Builder, entry SDKModel) {
+	model := entry.Model
+	name := entry.TypeName
+
+	columnNames := make([]string, 0)
+	for _, column := range model.Columns {
+		quoted, _ := json.Marshal(column.Name)
+		columnNames = append(columnNames, string(quoted))
+	}
+	columnType := "never"
+	if len(columnNames) > 0 {
+		columnType = strings.Join(columnNames, " | ")
+	}
+	fmt.Fprintf(builder, "export type %sColumn = %s;\n\n", name, columnType)
+
+	fmt.Fprintf(builder, "export interface %s {\n", name)
+	for _, column := range model.Columns {
+		fieldType := GetTypescriptTypeByColumn(column)
+		if column.Nullable {
+			fieldType += " | null"
+		}
+		fmt.Fprintf(builder, "  %s: %s;\n", GetTypescriptPropertyName(column.Name), fieldType)
+	}
+	fmt.Fprintf(builder, "  %s?: string;\n", CURSOR_KEY)
+	fmt.Fprintf(builder, "  %s?: number;\n", TEXT_SEARCH_RANK_KEY)
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "  %s?: %s | null;\n", GetTypescriptPropertyName(computedField.Name), GetTypescriptTypeByColumn(computedField.Column()))
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, isArray := GetSDKRelatedModel(model, alias)
+		relatedName := GetSDKTypeName(relatedModel.Database, relatedModel.Table)
+		if isArray {
+			fmt.Fprintf(builder, "  %s?: %s[];\n", GetTypescriptPropertyName(alias), relatedName)
+		} else {
+			fmt.Fprintf(builder, "  %s?: %s | null;\n", GetTypescriptPropertyName(alias), relatedName)
+		}
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sBoolExp {\n", name)
+	fmt.Fprintf(builder, "  _and?: %sBoolExp[];\n  _or?: %sBoolExp[];\n", name, name)
+	for _, column := range model.Columns {
+		fmt.Fprintf(builder, "  %s?: ComparisonExp<%s>;\n", GetTypescriptPropertyName(column.Name), GetTypescriptTypeByColumn(column))
+	}
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "  %s?: ComparisonExp<%s> & ComputedFieldArgs;\n", GetTypescriptPropertyName(computedField.Name), GetTypescriptTypeByColumn(computedField.Column()))
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, _ := GetSDKRelatedModel(model, alias)
+		fmt.Fprintf(builder, "  %s?: %sBoolExp;\n", GetTypescriptPropertyName(alias), GetSDKTypeName(relatedModel.Database, relatedModel.Table))
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export type %sColumnOrderBy = { [column in %sColumn]?: OrderBy };\n\n", name, name)
+
+	fmt.Fprintf(builder, "export interface %sAggregateOrderBy {\n  _count?: OrderBy;\n", name)
+	for _, aggregationKey := range []string{"_min", "_max", "_sum", "_avg"} {
+		fmt.Fprintf(builder, "  %s?: %sColumnOrderBy;\n", aggregationKey, name)
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sOrderBy extends %sColumnOrderBy {\n", name, name)
+	fmt.Fprintf(builder, "  %s?: OrderBy;\n", TEXT_SEARCH_RANK_KEY)
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "  %s?: OrderBy;\n", GetTypescriptPropertyName(computedField.Name))
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, isArray := GetSDKRelatedModel(model, alias)
+		relatedName := GetSDKTypeName(relatedModel.Database, relatedModel.Table)
+		if isArray {
+			fmt.Fprintf(builder, "  %s?: %sAggregateOrderBy;\n", GetTypescriptPropertyName(alias+"_aggregate"), relatedName)
+		} else {
+			fmt.Fprintf(builder, "  %s?: %sOrderBy;\n", GetTypescriptPropertyName(alias), relatedName)
+		}
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sSelect {\n", name)
+	for _, column := range model.Columns {
+		fmt.Fprintf(builder, "  %s?: boolean;\n", GetTypescriptPropertyName(column.Name))
+	}
+	fmt.Fprintf(builder, "  %s?: boolean;\n", CURSOR_KEY)
+	fmt.Fprintf(builder, "  %s?: boolean;\n", TEXT_SEARCH_RANK_KEY)
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "  %s?: boolean | ComputedFieldArgs;\n", GetTypescriptPropertyName(computedField.Name))
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, _ := GetSDKRelatedModel(model, alias)
+		fmt.Fprintf(builder, "  %s?: %sQuery;\n", GetTypescriptPropertyName(alias), GetSDKTypeName(relatedModel.Database, relatedModel.Table))
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sQuery {\n", name)
+	fmt.Fprintf(builder, "  _select?: %sSelect;\n", name)
+	fmt.Fprintf(builder, "  _where?: %sBoolExp;\n", name)
+	fmt.Fprintf(builder, "  _orderBy?: %sOrderBy | %sOrderBy[];\n", name, name)
+	fmt.Fprintf(builder, "  _groupBy?: %sColumn[];\n", name)
+	fmt.Fprintf(builder, "  _distinct?: %sColumn[];\n", name)
+	builder.WriteString("  _limit?: number;\n  _offset?: number;\n  _after?: string | null;\n  _before?: string | null;\n  _total?: boolean;\n}\n\n")
+
+	if model.IsReadOnly() {
+		return
+	}
+
+	fmt.Fprintf(builder, "export interface %sInsertInput {\n", name)
+	for _, column := range model.Columns {
+		fieldType := GetTypescriptTypeByColumn(column)
+		optional := "?"
+		if column.Nullable {
+			fieldType += " | null"
+		} else if len(column.DefaultValue) == 0 {
+			optional = ""
+		}
+		fmt.Fprintf(builder, "  %s%s: %s;\n", GetTypescriptPropertyName(column.Name), optional, fieldType)
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, _ := GetSDKRelatedModel(model, alias)
+		if relatedModel.IsReadOnly() {
+			continue
+		}
+		fmt.Fprintf(builder, "  %s?: %sInsert;\n", GetTypescriptPropertyName(alias), GetSDKTypeName(relatedModel.Database, relatedModel.Table))
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sInsert {\n", name)
+	fmt.Fprintf(builder, "  objects: %sInsertInput[];\n", name)
+	fmt.Fprintf(builder, "  onConflict?: OnConflict<%sColumn>;\n}\n\n", name)
+
+	fmt.Fprintf(builder, "export interface %sSetInput {\n", name)
+	for _, column := range model.Columns {
+		fieldType := GetTypescriptTypeByColumn(column)
+		if column.Nullable {
+			fieldType += " | null"
+		}
+		fmt.Fprintf(builder, "  %s?: %s;\n", GetTypescriptPropertyName(column.Name), fieldType)
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sUpdate {\n", name)
+	fmt.Fprintf(builder, "  _where?: %sBoolExp;\n", name)
+	fmt.Fprintf(builder, "  set?: %sSetInput;\n", name)
+	if len(entry.NumericFields) > 0 {
+		numericNames := make([]string, 0)
+		for _, column := range entry.NumericFields {
+			quoted, _ := json.Marshal(column.Name)
+			numericNames = append(numericNames, string(quoted))
+		}
+		for _, operator := range GetSDKUpdateOperators() {
+			fmt.Fprintf(builder, "  %s?: { [column in %s]?: number };\n", operator, strings.Join(numericNames, " | "))
+		}
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface %sDelete {\n", name)
+	fmt.Fprintf(builder, "  _where?: %sBoolExp;\n}\n\n", name)
+}
+
+func GetSDKUpdateOperators() []string {
+	operators := make([]string, 0, len(UPDATE_SELF_REFERENCING_OPERATORS))
+	for operator := range UPDATE_SELF_REFERENCING_OPERATORS {
+		operators = append(operators, operator)
+	}
+	sort.Strings(operators)
+	return operators
+}
+
+func WriteTypescriptDatabase(builder *strings.Builder, database string, models []SDKModel) {
+	name := GetSDKTypeName(database)
+	for _, operation := range []string{"SelectBody", "Result", "InsertBody", "UpdateBody", "DeleteBody"} {
+		fmt.Fprintf(builder, "export interface %s%s {\n", name, operation)
+		for _, entry := range models {
+			tableName := GetTypescriptPropertyName(entry.Model.Table)
+			if operation != "SelectBody" && operation != "Result" && entry.Model.IsReadOnly() {
+				continue
+			}
+			switch operation {
+			case "SelectBody":
+				fmt.Fprintf(builder, "  %s?: %sQuery;\n", tableName, entry.TypeName)
+			case "Result":
+				fmt.Fprintf(builder, "  %s?: %s[];\n", tableName, entry.TypeName)
+			case "InsertBody":
+				fmt.Fprintf(builder, "  %s?: %sInsert;\n", tableName, entry.TypeName)
+			case "UpdateBody":
+				fmt.Fprintf(builder, "  %s?: %sUpdate;\n", tableName, entry.TypeName)
+			case "DeleteBody":
+				fmt.Fprintf(builder, "  %s?: %sDelete;\n", tableName, entry.TypeName)
+			}
+		}
+		builder.WriteString("}\n\n")
+	}
+	fmt.Fprintf(builder, "export type %sProcessTransaction =\n  | { insert: %sInsertBody }\n  | { update: %sUpdateBody }\n  | { delete: %sDeleteBody };\n\n", name, name, name, name)
+	fmt.Fprintf(builder, "export interface %sProcessResult {\n  insert?: %sResult[];\n  update?: %sResult[];\n  delete?: %sResult[];\n}\n\n", name, name, name, name)
+}
+
+func (e *Engine) GenerateTypescriptSDK() string {
+	databases, databaseToModels := e.GetSDKModels()
+	builder := &strings.Builder{}
+	builder.WriteString("// Code generated by engine. DO NOT EDIT.\n\n")
+
+	directions := make([]string, 0)
+	for _, direction := range GetSDKOrderByDirections() {
+		directions = append(directions, fmt.Sprintf("%q", direction))
+	}
+	fmt.Fprintf(builder, "export type OrderBy = %s;\n\n", strings.Join(directions, " | "))
+
+	builder.WriteString("export interface ComparisonExp<T> {\n")
+	for _, operator := range GetSDKComparisonOperators() {
+		fmt.Fprintf(builder, "  %s?: %s;\n", operator, GetTypescriptOperatorType(operator))
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "export interface ComputedFieldArgs {\n  %s?: Record<string, unknown>;\n}\n\n", COMPUTED_FIELD_ARGS_KEY)
+
+	builder.WriteString("export interface OnConflict<C extends string> {\n  constraints: C[];\n  update?: \"*\" | C[];\n}\n\n")
+
+	for _, database := range databases {
+		for _, entry := range databaseToModels[database] {
+			WriteTypescriptModel(builder, entry)
+		}
+		WriteTypescriptDatabase(builder, database, databaseToModels[database])
+	}
+
+	builder.WriteString(`export class EngineError extends Error {
+  constructor(public status: number, message: string) {
+    super(message);
+  }
+}
+
+export class EngineClient {
+  constructor(
+    private baseUrl: string,
+    private token?: string,
+    private fetcher: typeof fetch = (input, init) => fetch(input, init),
+  ) {}
+
+  setToken(token?: string): void {
+    this.token = token;
+  }
+
+  async request<T>(method: string, path: string, body?: unknown): Promise<T> {
+    const headers: Record<string, string> = { "Content-Type": "application/json" };
+    if (this.token) {
+      headers["Authorization"] = ` + "`Bearer ${this.token}`" + `;
+    }
+    const response = await this.fetcher(this.baseUrl.replace(/\/+$/, "") + path, {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const payload = await response.json().catch(() => undefined);
+    if (!response.ok) {
+      throw new EngineError(response.status, payload?.message ?? response.statusText);
+    }
+    return payload as T;
+  }
+`)
+	for _, database := range databases {
+		name := GetSDKTypeName(database)
+		path := fmt.Sprintf("%q", "/"+database)
+		processPath := fmt.Sprintf("%q", "/"+database+"/process")
+		actionsPath := fmt.Sprintf("%q", "/"+database+"/actions")
+		fmt.Fprintf(builder, "\n  readonly %s = {\n", GetTypescriptPropertyName(database))
+		fmt.Fprintf(builder, "    select: (body: %sSelectBody) => this.request<%sResult>(\"POST\", %s, body),\n", name, name, path)
+		fmt.Fprintf(builder, "    insert: (body: %sInsertBody) => this.request<%sResult>(\"POST\", %s, body),\n", name, name, actionsPath)
+		fmt.Fprintf(builder, "    update: (body: %sUpdateBody) => this.request<%sResult>(\"PUT\", %s, body),\n", name, name, actionsPath)
+		fmt.Fprintf(builder, "    delete: (body: %sDeleteBody) => this.request<%sResult>(\"DELETE\", %s, body),\n", name, name, actionsPath)
+		fmt.Fprintf(builder, "    process: (transactions: %sProcessTransaction[]) => this.request<%sProcessResult>(\"POST\", %s, { transactions }),\n", name, name, processPath)
+		fmt.Fprintf(builder, "    rpc: <T = unknown>(name: string, args: Record<string, unknown> = {}) => this.request<T>(\"POST\", %s + encodeURIComponent(name), args),\n", fmt.Sprintf("%q", "/"+database+"/rpc/"))
+		builder.WriteString("  };\n")
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func GetGoTypeBySchema(schema map[string]any) string {
+	switch schema["type"] {
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return "[]" + GetGoTypeBySchema(items)
+	default:
+		return "any"
+	}
+}
+
+func GetGoTypeByColumn(column Column) string {
+	return GetGoTypeBySchema(GetOpenAPISchemaByColumnType(column.Type, column.MaxLength))
+}
+
+func GetGoOptionalType(goType string) string {
+	if strings.HasPrefix(goType, "[]") || goType == "any" {
+		return goType
+	}
+	return "*" + goType
+}
+
+func GetGoOperatorType(operator string) string {
+	switch GetSDKOperatorKind(operator) {
+	case SDK_OPERATOR_NULL:
+		return "*any"
+	case SDK_OPERATOR_TEXT:
+		return "*string"
+	case SDK_OPERATOR_TEXT_ARRAY:
+		return "[]string"
+	case SDK_OPERATOR_JSON:
+		return "any"
+	case SDK_OPERATOR_ARRAY:
+		return "[]T"
+	default:
+		return "*T"
+	}
+}
+
+func GetGoFieldNames(model *Model, relations []string) (map[string]string, map[string]string) {
+	used := map[string]bool{"Cursor": true, "Rank": true, "And": true, "Or": true}
+	names := make(map[string]string)
+	aggregateNames := make(map[string]string)
+	for _, column := range model.Columns {
+		names[column.Name] = GetSDKUniqueName(GetSDKTypeName(column.Name), used)
+	}
+	for _, computedField := range GetSortedComputedFields(model) {
+		names[computedField.Name] = GetSDKUniqueName(GetSDKTypeName(computedField.Name), used)
+	}
+	for _, alias := range relations {
+		names[alias] = GetSDKUniqueName(GetSDKTypeName(alias), used)
+	}
+	for _, alias := range relations {
+		if _, isArray := GetSDKRelatedModel(model, alias); isArray {
+			aggregateNames[alias] = GetSDKUniqueName(names[alias]+"Aggregate", used)
+		}
+	}
+	return names, aggregateNames
+}
+
+func WriteGoModel(builder *strings.Builder, entry SDKModel) {
+	model := entry.Model
+	name := entry.TypeName
+	fields, aggregateFields := GetGoFieldNames(model, entry.Relations)
+
+	fmt.Fprintf(builder, "type %sColumn string\n\n", name)
+	if len(model.Columns) > 0 {
+		builder.WriteString("const (\n")
+		for _, column := range model.Columns {
+			fmt.Fprintf(builder, "%sColumn%s %sColumn = %q\n", name, fields[column.Name], name, column.Name)
+		}
+		builder.WriteString(")\n\n")
+	}
+
+	fmt.Fprintf(builder, "type %s struct {\n", name)
+	for _, column := range model.Columns {
+		fieldType := GetGoTypeByColumn(column)
+		if column.Nullable {
+			fieldType = GetGoOptionalType(fieldType)
+		}
+		fmt.Fprintf(builder, "%s %s `json:%q`\n", fields[column.Name], fieldType, column.Name)
+	}
+	fmt.Fprintf(builder, "Cursor string `json:%q`\n", CURSOR_KEY+",omitempty")
+	fmt.Fprintf(builder, "Rank float64 `json:%q`\n", TEXT_SEARCH_RANK_KEY+",omitempty")
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "%s %s `json:%q`\n", fields[computedField.Name], GetGoOptionalType(GetGoTypeByColumn(computedField.Column())), computedField.Name+",omitempty")
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, isArray := GetSDKRelatedModel(model, alias)
+		relatedName := GetSDKTypeName(relatedModel.Database, relatedModel.Table)
+		if isArray {
+			fmt.Fprintf(builder, "%s []%s `json:%q`\n", fields[alias], relatedName, alias+",omitempty")
+		} else {
+			fmt.Fprintf(builder, "%s *%s `json:%q`\n", fields[alias], relatedName, alias+",omitempty")
+		}
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sBoolExp struct {\n", name)
+	fmt.Fprintf(builder, "And []%sBoolExp `json:\"_and,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Or []%sBoolExp `json:\"_or,omitempty\"`\n", name)
+	for _, column := range model.Columns {
+		fmt.Fprintf(builder, "%s *ComparisonExp[%s] `json:%q`\n", fields[column.Name], GetGoTypeByColumn(column), column.Name+",omitempty")
+	}
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "%s *ComputedFieldExp[%s] `json:%q`\n", fields[computedField.Name], GetGoTypeByColumn(computedField.Column()), computedField.Name+",omitempty")
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, _ := GetSDKRelatedModel(model, alias)
+		fmt.Fprintf(builder, "%s *%sBoolExp `json:%q`\n", fields[alias], GetSDKTypeName(relatedModel.Database, relatedModel.Table), alias+",omitempty")
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sOrderBy struct {\n", name)
+	fmt.Fprintf(builder, "Rank OrderBy `json:%q`\n", TEXT_SEARCH_RANK_KEY+",omitempty")
+	for _, column := range model.Columns {
+		fmt.Fprintf(builder, "%s OrderBy `json:%q`\n", fields[column.Name], column.Name+",omitempty")
+	}
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "%s OrderBy `json:%q`\n", fields[computedField.Name], computedField.Name+",omitempty")
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, isArray := GetSDKRelatedModel(model, alias)
+		relatedName := GetSDKTypeName(relatedModel.Database, relatedModel.Table)
+		if isArray {
+			fmt.Fprintf(builder, "%s *%sAggregateOrderBy `json:%q`\n", aggregateFields[alias], relatedName, alias+"_aggregate,omitempty")
+		} else {
+			fmt.Fprintf(builder, "%s *%sOrderBy `json:%q`\n", fields[alias], relatedName, alias+",omitempty")
+		}
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sAggregateOrderBy struct {\n", name)
+	builder.WriteString("Count OrderBy `json:\"_count,omitempty\"`\n")
+	for _, aggregationKey := range []string{"_min", "_max", "_sum", "_avg"} {
+		fmt.Fprintf(builder, "%s map[%sColumn]OrderBy `json:%q`\n", GetSDKTypeName(aggregationKey), name, aggregationKey+",omitempty")
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sSelect struct {\n", name)
+	for _, column := range model.Columns {
+		fmt.Fprintf(builder, "%s bool `json:%q`\n", fields[column.Name], column.Name+",omitempty")
+	}
+	fmt.Fprintf(builder, "Cursor bool `json:%q`\n", CURSOR_KEY+",omitempty")
+	fmt.Fprintf(builder, "Rank bool `json:%q`\n", TEXT_SEARCH_RANK_KEY+",omitempty")
+	for _, computedField := range GetSortedComputedFields(model) {
+		fmt.Fprintf(builder, "%s any `json:%q`\n", fields[computedField.Name], computedField.Name+",omitempty")
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, _ := GetSDKRelatedModel(model, alias)
+		fmt.Fprintf(builder, "%s *%sQuery `json:%q`\n", fields[alias], GetSDKTypeName(relatedModel.Database, relatedModel.Table), alias+",omitempty")
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sQuery struct {\n", name)
+	fmt.Fprintf(builder, "Select *%sSelect `json:\"_select,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Where *%sBoolExp `json:\"_where,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "OrderBy []%sOrderBy `json:\"_orderBy,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "GroupBy []%sColumn `json:\"_groupBy,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Distinct []%sColumn `json:\"_distinct,omitempty\"`\n", name)
+	builder.WriteString("Limit *int `json:\"_limit,omitempty\"`\n")
+	builder.WriteString("Offset *int `json:\"_offset,omitempty\"`\n")
+	builder.WriteString("After *string `json:\"_after,omitempty\"`\n")
+	builder.WriteString("Before *string `json:\"_before,omitempty\"`\n")
+	builder.WriteString("Total bool `json:\"_total,omitempty\"`\n")
+	builder.WriteString("}\n\n")
+
+	if model.IsReadOnly() {
+		return
+	}
+
+	fmt.Fprintf(builder, "type %sInsertInput struct {\n", name)
+	for _, column := range model.Columns {
+		fieldType := GetGoTypeByColumn(column)
+		if column.Nullable || len(column.DefaultValue) > 0 {
+			fmt.Fprintf(builder, "%s %s `json:%q`\n", fields[column.Name], GetGoOptionalType(fieldType), column.Name+",omitempty")
+			continue
+		}
+		fmt.Fprintf(builder, "%s %s `json:%q`\n", fields[column.Name], fieldType, column.Name)
+	}
+	for _, alias := range entry.Relations {
+		relatedModel, _ := GetSDKRelatedModel(model, alias)
+		if relatedModel.IsReadOnly() {
+			continue
+		}
+		fmt.Fprintf(builder, "%s *%sInsert `json:%q`\n", fields[alias], GetSDKTypeName(relatedModel.Database, relatedModel.Table), alias+",omitempty")
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sInsert struct {\n", name)
+	fmt.Fprintf(builder, "Objects []%sInsertInput `json:\"objects\"`\n", name)
+	fmt.Fprintf(builder, "OnConflict *OnConflict[%sColumn] `json:\"onConflict,omitempty\"`\n", name)
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sSetInput struct {\n", name)
+	for _, column := range model.Columns {
+		fmt.Fprintf(builder, "%s %s `json:%q`\n", fields[column.Name], GetGoOptionalType(GetGoTypeByColumn(column)), column.Name+",omitempty")
+	}
+	builder.WriteString("}\n\n")
+
+	if len(entry.NumericFields) > 0 {
+		fmt.Fprintf(builder, "type %sNumericInput struct {\n", name)
+		for _, column := range entry.NumericFields {
+			fmt.Fprintf(builder, "%s %s `json:%q`\n", fields[column.Name], GetGoOptionalType(GetGoTypeByColumn(column)), column.Name+",omitempty")
+		}
+		builder.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(builder, "type %sUpdate struct {\n", name)
+	fmt.Fprintf(builder, "Where *%sBoolExp `json:\"_where,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Set *%sSetInput `json:\"set,omitempty\"`\n", name)
+	if len(entry.NumericFields) > 0 {
+		for _, operator := range GetSDKUpdateOperators() {
+			fmt.Fprintf(builder, "%s *%sNumericInput `json:%q`\n", GetSDKTypeName(operator), name, operator+",omitempty")
+		}
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sDelete struct {\n", name)
+	fmt.Fprintf(builder, "Where *%sBoolExp `json:\"_where,omitempty\"`\n", name)
+	builder.WriteString("}\n\n")
+}
+
+func WriteGoDatabase(builder *strings.Builder, database string, models []SDKModel) {
+	name := GetSDKTypeName(database)
+	used := map[string]bool{}
+	fields := make(map[string]string)
+	for _, entry := range models {
+		fields[entry.Model.Table] = GetSDKUniqueName(GetSDKTypeName(entry.Model.Table), used)
+	}
+
+	for _, operation := range []string{"SelectBody", "Result", "InsertBody", "UpdateBody", "DeleteBody"} {
+		fmt.Fprintf(builder, "type %s%s struct {\n", name, operation)
+		for _, entry := range models {
+			table := entry.Model.Table
+			tag := table + ",omitempty"
+			if operation != "SelectBody" && operation != "Result" && entry.Model.IsReadOnly() {
+				continue
+			}
+			switch operation {
+			case "SelectBody":
+				fmt.Fprintf(builder, "%s *%sQuery `json:%q`\n", fields[table], entry.TypeName, tag)
+			case "Result":
+				fmt.Fprintf(builder, "%s []%s `json:%q`\n", fields[table], entry.TypeName, tag)
+			case "InsertBody":
+				fmt.Fprintf(builder, "%s *%sInsert `json:%q`\n", fields[table], entry.TypeName, tag)
+			case "UpdateBody":
+				fmt.Fprintf(builder, "%s *%sUpdate `json:%q`\n", fields[table], entry.TypeName, tag)
+			case "DeleteBody":
+				fmt.Fprintf(builder, "%s *%sDelete `json:%q`\n", fields[table], entry.TypeName, tag)
+			}
+		}
+		builder.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(builder, "type %sProcessTransaction struct {\n", name)
+	fmt.Fprintf(builder, "Insert *%sInsertBody `json:\"insert,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Update *%sUpdateBody `json:\"update,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Delete *%sDeleteBody `json:\"delete,omitempty\"`\n", name)
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type %sProcessResult struct {\n", name)
+	fmt.Fprintf(builder, "Insert []%sResult `json:\"insert,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Update []%sResult `json:\"update,omitempty\"`\n", name)
+	fmt.Fprintf(builder, "Delete []%sResult `json:\"delete,omitempty\"`\n", name)
+	builder.WriteString("}\n\n")
+
+	operations := []struct {
+		method string
+		verb   string
+		path   string
+		body   string
+	}{
+		{"Select", "http.MethodPost", "/" + database, "SelectBody"},
+		{"Insert", "http.MethodPost", "/" + database + "/actions", "InsertBody"},
+		{"Update", "http.MethodPut", "/" + database + "/actions", "UpdateBody"},
+		{"Delete", "http.MethodDelete", "/" + database + "/actions", "DeleteBody"},
+	}
+	for _, operation := range operations {
+		fmt.Fprintf(builder, "func (c *Client) %s%s(ctx context.Context, body %s%s) (*%sResult, error) {\n", name, operation.method, name, operation.body, name)
+		fmt.Fprintf(builder, "result := &%sResult{}\n", name)
+		fmt.Fprintf(builder, "err := c.Do(ctx, %s, %q, body, result)\n", operation.verb, operation.path)
+		builder.WriteString("if err != nil {\nreturn nil, err\n}\nreturn result, nil\n}\n\n")
+	}
+	fmt.Fprintf(builder, "func (c *Client) %sProcess(ctx context.Context, transactions []%sProcessTransaction) (*%sProcessResult, error) {\n", name, name, name)
+	fmt.Fprintf(builder, "result := &%sProcessResult{}\n", name)
+	fmt.Fprintf(builder, "err := c.Do(ctx, http.MethodPost, %q, map[string]any{\"transactions\": transactions}, result)\n", "/"+database+"/process")
+	builder.WriteString("if err != nil {\nreturn nil, err\n}\nreturn result, nil\n}\n\n")
+	fmt.Fprintf(builder, "func (c *Client) %sRpc(ctx context.Context, name string, args map[string]any, result any) error {\n", name)
+	builder.WriteString("if args == nil {\nargs = map[string]any{}\n}\n")
+	fmt.Fprintf(builder, "return c.Do(ctx, http.MethodPost, %q+name, args, result)\n}\n\n", "/"+database+"/rpc/")
+}
+
+func (e *Engine) GenerateGoSDK(packageName string) (string, error) {
+	databases, databaseToModels := e.GetSDKModels()
+	builder := &strings.Builder{}
+	builder.WriteString("// Code generated by engine. DO NOT EDIT.\n\n")
+	fmt.Fprintf(builder, "package %s\n\n", packageName)
+	builder.WriteString("import (\n\"bytes\"\n\"context\"\n\"encoding/json\"\n\"fmt\"\n\"net/http\"\n\"strings\"\n)\n\n")
+
+	builder.WriteString("type OrderBy string\n\nconst (\n")
+	for _, direction := range GetSDKOrderByDirections() {
+		fmt.Fprintf(builder, "OrderBy%s OrderBy = %q\n", GetSDKTypeName(strings.ToLower(direction)), direction)
+	}
+	builder.WriteString(")\n\n")
+
+	builder.WriteString("type ComparisonExp[T any] struct {\n")
+	for _, operator := range GetSDKComparisonOperators() {
+		fmt.Fprintf(builder, "%s %s `json:%q`\n", GetSDKTypeName(operator), GetGoOperatorType(operator), operator+",omitempty")
+	}
+	builder.WriteString("}\n\n")
+
+	fmt.Fprintf(builder, "type ComputedFieldArgs struct {\nArgs map[string]any `json:%q`\n}\n\n", COMPUTED_FIELD_ARGS_KEY+",omitempty")
+	builder.WriteString("type ComputedFieldExp[T any] struct {\nComparisonExp[T]\nComputedFieldArgs\n}\n\n")
+
+	builder.WriteString("type OnConflict[C ~string] struct {\nConstraints []C `json:\"constraints\"`\nUpdate any `json:\"update,omitempty\"`\n}\n\n")
+
+	builder.WriteString(`type Error struct {
+StatusCode int
+Message string ` + "`json:\"message\"`" + `
+}
+
+func (e *Error) Error() string {
+return fmt.Sprintf("engine responded with %d: %s", e.StatusCode, e.Message)
+}
+
+type Client struct {
+BaseUrl string
+Token string
+HttpClient *http.Client
+}
+
+func NewClient(baseUrl string, token string) *Client {
+return &Client{BaseUrl: strings.TrimRight(baseUrl, "/"), Token: token, HttpClient: http.DefaultClient}
+}
+
+func (c *Client) Do(ctx context.Context, method string, path string, body any, result any) error {
+payload, err := json.Marshal(body)
+if err != nil {
+return err
+}
+req, err := http.NewRequestWithContext(ctx, method, c.BaseUrl+path, bytes.NewReader(payload))
+if err != nil {
+return err
+}
+req.Header.Set("Content-Type", "application/json")
+if len(c.Token) > 0 {
+req.Header.Set("Authorization", "Bearer "+c.Token)
+}
+res, err := c.HttpClient.Do(req)
+if err != nil {
+return err
+}
+defer res.Body.Close()
+if res.StatusCode >= http.StatusBadRequest {
+engineError := &Error{StatusCode: res.StatusCode}
+json.NewDecoder(res.Body).Decode(engineError)
+return engineError
+}
+if result == nil {
+return nil
+}
+return json.NewDecoder(res.Body).Decode(result)
+}
+
+`)
+
+	for _, database := range databases {
+		for _, entry := range databaseToModels[database] {
+			WriteGoModel(builder, entry)
+		}
+		WriteGoDatabase(builder, database, databaseToModels[database])
+	}
+
+	source, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format go sdk: %s", err.Error())
+	}
+	return string(source), nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetSDKTypeName(t *testing.T) {
+	cases := []struct {
+		parts []string
+		want  string
+	}{
+		{parts: []string{"public", "users"}, want: "PublicUsers"},
+		{parts: []string{"my-db", "user_accounts"}, want: "MyDbUserAccounts"},
+		{parts: []string{"2fa"}, want: "T2fa"},
+		{parts: []string{"__"}, want: "T"},
+	}
+	for _, c := range cases {
+		t.Run(strings.Join(c.parts, "."), func(t *testing.T) {
+			if name := GetSDKTypeName(c.parts...); name != c.want {
+				t.Errorf("expected %s, got %s", c.want, name)
+			}
+		})
+	}
+}
+
+func TestGetGoFieldNames(t *testing.T) {
+	model := &Model{
+		Columns: []Column{{Name: "user_id"}, {Name: "user-id"}, {Name: "cursor"}, {Name: "posts"}},
+		Relations: RelationMap{
+			"posts": &Model{Database: "public", Table: "posts"},
+		},
+		RelationsInfoMap: RelationInfoMap{"posts": {RelationType: ARRAY}},
+	}
+	names, aggregateNames := GetGoFieldNames(model, []string{"posts"})
+	want := map[string]string{"user_id": "UserId", "user-id": "UserId2", "cursor": "Cursor2", "posts": "Posts2"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+	if aggregateNames["posts"] != "Posts2Aggregate" {
+		t.Errorf("expected Posts2Aggregate, got %v", aggregateNames)
+	}
+}
+
+func TestGenerateTypescriptSDK(t *testing.T) {
+	sdk, err := NewGeneratorTestEngine().GenerateSDK("ts", "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, expected := range []string{
+		`export type PublicPostsColumn = "id" | "author_id" | "published-at" | "tags";`,
+		`  "published-at": string | null;`,
+		`  tags: Array<string> | null;`,
+		`  author?: PublicUsers | null;`,
+		`  posts?: PublicPosts[];`,
+		`  posts_aggregate?: PublicPostsAggregateOrderBy;`,
+		`  _orderBy?: PublicUsersOrderBy | PublicUsersOrderBy[];`,
+	} {
+		if !strings.Contains(sdk, expected) {
+			t.Errorf("expected typescript sdk to contain %q", expected)
+		}
+	}
+}
+
+func TestGenerateGoSDK(t *testing.T) {
+	sdk, err := NewGeneratorTestEngine().GenerateSDK(SDK_GO, "client")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "client.go", sdk, 0)
+	if err != nil {
+		t.Fatalf("generated go sdk doesn't parse: %v", err)
+	}
+	if file.Name.Name != "client" {
+		t.Errorf("expected package client, got %s", file.Name.Name)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("client", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatalf("generated go sdk doesn't type check: %v", err)
+	}
+	for _, expected := range []string{"type PublicPosts struct", "type PublicUsers struct", "PublicPostsColumnPublishedAt"} {
+		if !strings.Contains(sdk, expected) {
+			t.Errorf("expected go sdk to contain %q", expected)
+		}
+	}
+}
+
+func TestGenerateSDKErrors(t *testing.T) {
+	engine := NewGeneratorTestEngine()
+	if _, err := engine.GenerateSDK(SDK_GO, "Client"); err == nil || err.Error() != "invalid go package name Client" {
+		t.Errorf("expected an invalid package error, got %v", err)
+	}
+	if _, err := engine.GenerateSDK("python", ""); err == nil || err.Error() != "not supported sdk language python" {
+		t.Errorf("expected a not supported language error, got %v", err)
+	}
+}
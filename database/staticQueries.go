@@ -29,7 +29,29 @@ const CREATE_PRIMARY_INDEX = `ALTER TABLE %s.%s ADD CONSTRAINT %s PRIMARY KEY (%
 
 var GET_DATABASES string = fmt.Sprintf(`SELECT schema_name FROM information_schema.schemata WHERE schema_name NOT IN (%s) ORDER BY schema_name;`, strings.Join(EXCLUDED_SCHEMAS, ","))
 
-const GET_DATABASE_TABLES = `SELECT table_name FROM information_schema.tables WHERE table_schema = $1 ORDER BY table_name;`
+const GET_DATABASE_TABLES = `SELECT table_name FROM information_schema.tables WHERE table_schema = $1
+UNION
+SELECT matviewname FROM pg_matviews WHERE schemaname = $1
+ORDER BY 1;`
+const GET_DATABASE_TABLE_KINDS = `SELECT table_name, CASE WHEN table_type = 'VIEW' THEN 'VIEW' ELSE 'TABLE' END FROM information_schema.tables WHERE table_schema = $1
+UNION ALL
+SELECT matviewname, 'MATERIALIZED_VIEW' FROM pg_matviews WHERE schemaname = $1;`
+const GET_MATERIALIZED_VIEW_COLUMNS = `SELECT a.attname, format_type(a.atttypid, NULL), NULL::bigint, NOT a.attnotnull, NULL::text FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'm' AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum;`
+const REFRESH_MATERIALIZED_VIEW = `REFRESH MATERIALIZED VIEW %s%s.%s;`
+const GET_MATERIALIZED_VIEW_REFRESHES = `SELECT id,db,view_name,concurrently,interval_seconds,enabled,next_refresh_at,last_refreshed_at,last_error FROM root_engine.engine_materialized_view_refreshes ORDER BY id;`
+const CREATE_MATERIALIZED_VIEW_REFRESH = `INSERT INTO root_engine.engine_materialized_view_refreshes(db,view_name,concurrently,interval_seconds,enabled,next_refresh_at) VALUES ($1,$2,$3,$4,$5,CURRENT_TIMESTAMP + make_interval(secs => $4));`
+const UPDATE_MATERIALIZED_VIEW_REFRESH_BY_ID = `UPDATE root_engine.engine_materialized_view_refreshes SET db = $1, view_name = $2, concurrently = $3, interval_seconds = $4, enabled = $5, next_refresh_at = CURRENT_TIMESTAMP + make_interval(secs => $4) WHERE id = $6`
+const DELETE_MATERIALIZED_VIEW_REFRESH_BY_ID = `DELETE FROM root_engine.engine_materialized_view_refreshes WHERE id = $1`
+const DELETE_MATERIALIZED_VIEW_REFRESHES_BY_DATABASE_NAME = `DELETE FROM root_engine.engine_materialized_view_refreshes WHERE db = $1`
+const DELETE_MATERIALIZED_VIEW_REFRESHES_BY_DATABASE_TABLE_NAME = `DELETE FROM root_engine.engine_materialized_view_refreshes WHERE db = $1 AND view_name = $2`
+const CLAIM_MATERIALIZED_VIEW_REFRESHES = `UPDATE root_engine.engine_materialized_view_refreshes SET next_refresh_at = CURRENT_TIMESTAMP + make_interval(secs => interval_seconds)
+WHERE id IN (SELECT id FROM root_engine.engine_materialized_view_refreshes WHERE enabled = true AND next_refresh_at <= CURRENT_TIMESTAMP FOR UPDATE SKIP LOCKED)
+RETURNING id,db,view_name,concurrently;`
+const COMPLETE_MATERIALIZED_VIEW_REFRESH = `UPDATE root_engine.engine_materialized_view_refreshes SET last_refreshed_at = CURRENT_TIMESTAMP, last_error = NULLIF($2,'') WHERE id = $1`
 const GET_DATABASE_TABLE_COLUMN = `SELECT column_name,data_type,character_maximum_length,
 CASE WHEN is_nullable = 'NO' THEN false ELSE true END,CASE WHEN column_default IS NULL THEN NULL ELSE column_default::text END
  FROM information_schema.columns WHERE table_schema = $1 AND table_name   = $2 ORDER BY ordinal_position;`
@@ -48,7 +70,57 @@ const GET_DATABASE_TABLE_INDEXES = `SELECT tc.constraint_name,  tc.table_name, k
 const GET_ENGINE_RELATIONS = `SELECT relations.id,relations.alias,relations.db,relations.from_table,relations.from_column,relations.to_table,
        relations.to_column,relations.relation FROM root_engine.relations;`
 const GET_GLOBAL_AUTH_CONFIG = `SELECT id,created_at,db,tbl,auth_config FROM root_engine.engine_auth_provider ORDER BY created_at ASC;`
-const ENGINE_GET_WEBHOOKS = `SELECT id,endpoint,enabled,db,db_table,operation,rest,graphql,created_at,type,forward_auth_headers FROM root_engine.engine_webhooks;`
+const ENGINE_GET_WEBHOOKS = `SELECT id,endpoint,enabled,db,db_table,operation,rest,graphql,created_at,type,forward_auth_headers,secret,headers FROM root_engine.engine_webhooks;`
+const ALTER_ENGINE_WEBHOOKS_ADD_SIGNING_COLUMNS = `ALTER TABLE root_engine.engine_webhooks ADD COLUMN IF NOT EXISTS secret varchar(255), ADD COLUMN IF NOT EXISTS headers jsonb;`
+const ALTER_ENGINE_REST_ACTIONS_ADD_PARAMS_COLUMN = `ALTER TABLE root_engine.engine_rest_actions ADD COLUMN IF NOT EXISTS params jsonb;`
+const ALTER_ENGINE_REST_ACTIONS_ADD_GRAPHQL_COLUMNS = `ALTER TABLE root_engine.engine_rest_actions ADD COLUMN IF NOT EXISTS graphql boolean, ADD COLUMN IF NOT EXISTS graphql_name varchar(255);`
+const CREATE_WEBHOOK = `INSERT INTO root_engine.engine_webhooks(endpoint,db,db_table,operation,enabled,rest,graphql,forward_auth_headers,type,secret,headers) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NULLIF($10,''),$11);`
+const UPDATE_WEBHOOK_BY_ID = `UPDATE root_engine.engine_webhooks SET endpoint = $1, db = $2, db_table = $3, operation = $4, enabled = $5, rest = $6, graphql = $7, forward_auth_headers = $8, type = $9, secret = CASE WHEN $13 THEN NULL ELSE COALESCE(NULLIF($11,''), secret) END, headers = CASE WHEN $14 THEN NULL ELSE COALESCE($12::jsonb, headers) END WHERE id = $10`
+const UPDATE_WEBHOOK_ENABLED_BY_ID = `UPDATE root_engine.engine_webhooks SET enabled = $1 WHERE id = $2`
+const DELETE_WEBHOOK_BY_ID = `DELETE FROM root_engine.engine_webhooks WHERE id = $1`
+const DELETE_WEBHOOKS_BY_DATABASE_NAME = `DELETE FROM root_engine.engine_webhooks WHERE db = $1`
+const DELETE_WEBHOOKS_BY_DATABASE_TABLE_NAME = `DELETE FROM root_engine.engine_webhooks WHERE db = $1 AND db_table = $2`
+const GET_ENGINE_COMPUTED_FIELDS = `SELECT id,name,db,db_table,function_schema,function_name,return_type FROM root_engine.engine_computed_fields ORDER BY id;`
+const CREATE_COMPUTED_FIELD = `INSERT INTO root_engine.engine_computed_fields(name,db,db_table,function_schema,function_name,return_type) VALUES ($1,$2,$3,$4,$5,$6);`
+const UPDATE_COMPUTED_FIELD_BY_ID = `UPDATE root_engine.engine_computed_fields SET name = $1, db = $2, db_table = $3, function_schema = $4, function_name = $5, return_type = $6 WHERE id = $7`
+const DELETE_COMPUTED_FIELD_BY_ID = `DELETE FROM root_engine.engine_computed_fields WHERE id = $1`
+const DELETE_COMPUTED_FIELDS_BY_DATABASE_NAME = `DELETE FROM root_engine.engine_computed_fields WHERE db = $1`
+const DELETE_COMPUTED_FIELDS_BY_DATABASE_TABLE_NAME = `DELETE FROM root_engine.engine_computed_fields WHERE db = $1 AND db_table = $2`
+const GET_COMPUTED_FIELD_FUNCTION = `SELECT format_type(p.prorettype, NULL), p.proretset, p.provolatile FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+JOIN pg_type t ON t.oid = p.proargtypes[0]
+JOIN pg_namespace tn ON tn.oid = t.typnamespace
+WHERE n.nspname = $1 AND p.proname = $2 AND tn.nspname = $3 AND t.typname = $4 AND p.pronargs - p.pronargdefaults = 1
+ORDER BY p.provolatile IN ('s', 'i') DESC
+LIMIT 1;`
+const GET_ENGINE_EXPOSED_FUNCTIONS = `SELECT id,db,function_name,roles,created_at FROM root_engine.engine_exposed_functions ORDER BY id;`
+const CREATE_EXPOSED_FUNCTION = `INSERT INTO root_engine.engine_exposed_functions(db,function_name,roles) VALUES ($1,$2,$3);`
+const UPDATE_EXPOSED_FUNCTION_BY_ID = `UPDATE root_engine.engine_exposed_functions SET db = $1, function_name = $2, roles = $3 WHERE id = $4`
+const DELETE_EXPOSED_FUNCTION_BY_ID = `DELETE FROM root_engine.engine_exposed_functions WHERE id = $1`
+const DELETE_EXPOSED_FUNCTIONS_BY_DATABASE_NAME = `DELETE FROM root_engine.engine_exposed_functions WHERE db = $1`
+const GET_DATABASE_FUNCTIONS = `SELECT p.proname, p.provolatile, p.proretset, upper(rt.typname), rt.typtype = 'c' OR rt.typname = 'record', COALESCE(rc.relname, ''), p.pronargdefaults,
+COALESCE((SELECT json_agg(json_build_object('name', COALESCE(p.proargnames[a.i], ''), 'type', format_type(a.type_oid, NULL), 'type_name', upper(argt.typname)) ORDER BY a.i)
+FROM unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[])) WITH ORDINALITY a(type_oid, i)
+JOIN pg_type argt ON argt.oid = a.type_oid
+WHERE p.proargmodes IS NULL OR p.proargmodes[a.i] IN ('i','b')), '[]')
+FROM pg_proc p
+JOIN pg_namespace n ON n.oid = p.pronamespace
+JOIN pg_type rt ON rt.oid = p.prorettype
+LEFT JOIN pg_class rc ON rc.oid = rt.typrelid AND rc.relnamespace = p.pronamespace
+WHERE n.nspname = $1 AND p.prokind = 'f' AND p.provariadic = 0 AND rt.typname NOT IN ('trigger','event_trigger')
+AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.classid = 'pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e')
+ORDER BY p.proname, p.oid;`
+const CREATE_WEBHOOK_DELIVERY = `INSERT INTO root_engine.engine_webhook_deliveries(webhook_id,endpoint,db,db_table,operation,payload,auth,status,attempts,max_attempts,next_attempt_at,created_at,updated_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,0,$9,CURRENT_TIMESTAMP,CURRENT_TIMESTAMP,CURRENT_TIMESTAMP);`
+const CLAIM_WEBHOOK_DELIVERIES = `UPDATE root_engine.engine_webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2), updated_at = CURRENT_TIMESTAMP
+WHERE id IN (SELECT id FROM root_engine.engine_webhook_deliveries WHERE status = 'PENDING' AND next_attempt_at <= CURRENT_TIMESTAMP ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED)
+RETURNING id,webhook_id,endpoint,db,db_table,operation,payload,auth,attempts,max_attempts;`
+const CREATE_WEBHOOK_DELIVERIES_PENDING_INDEX = `CREATE INDEX IF NOT EXISTS engine_webhook_deliveries_pending_idx ON root_engine.engine_webhook_deliveries (status, next_attempt_at) WHERE status = 'PENDING';`
+const DELETE_EXPIRED_WEBHOOK_DELIVERIES = `DELETE FROM root_engine.engine_webhook_deliveries WHERE status = 'DELIVERED' AND delivered_at < CURRENT_TIMESTAMP - make_interval(secs => $1)`
+const UPDATE_WEBHOOK_DELIVERY_DELIVERED = `UPDATE root_engine.engine_webhook_deliveries SET status = 'DELIVERED', attempts = $2, last_status_code = $3, last_error = NULL, delivered_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+const UPDATE_WEBHOOK_DELIVERY_FAILED = `UPDATE root_engine.engine_webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $4), last_error = $5, last_status_code = $6, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+const GET_WEBHOOK_DELIVERIES_BY_STATUS = `SELECT id,webhook_id,endpoint,db,db_table,operation,payload,status,attempts,max_attempts,last_error,last_status_code,next_attempt_at,created_at,delivered_at
+FROM root_engine.engine_webhook_deliveries WHERE status = $1 ORDER BY id DESC LIMIT $2;`
+const REPLAY_WEBHOOK_DELIVERIES = `UPDATE root_engine.engine_webhook_deliveries SET status = $2, attempts = 0, max_attempts = $4, last_error = NULL, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ANY($1) AND status = $3`
 const ENGINE_GET_DATA_TRIGGERS = `SELECT id,created_at,db,tbl,trigger_config FROM root_engine.engine_data_triggers;`
 const CREATE_DATA_TRIGGER = `INSERT INTO root_engine.engine_data_triggers(db,tbl,trigger_config) VALUES ($1,$2,$3);`
 const UPDATE_DATA_TRIGGER_BY_ID = `UPDATE root_engine.engine_data_triggers SET  trigger_config = $1 WHERE id = $2`
@@ -119,8 +191,58 @@ const CREATE_SUPER_USER = `CREATE ROLE %s WITH PASSWORD '%s' SUPERUSER;`
 
 const CREATE_ENGINE_ROLE = `INSERT INTO root_engine.engine_roles(role_name,permissions) VALUES($1,'{}'::json);`
 const GET_ENGINE_ROLE = `SELECT id,role_name,permissions,created_at FROM root_engine.engine_roles WHERE role_name = $1;`
+const GET_ENGINE_ROLES = `SELECT id,role_name,permissions,created_at FROM root_engine.engine_roles ORDER BY id;`
+const CREATE_ENGINE_ROLE_WITH_PERMISSIONS = `INSERT INTO root_engine.engine_roles(role_name,permissions) VALUES($1,$2::json);`
+const UPDATE_ENGINE_ROLE_PERMISSIONS = `UPDATE root_engine.engine_roles SET permissions = $1::json WHERE role_name = $2;`
+const DELETE_ENGINE_ROLE = `DELETE FROM root_engine.engine_roles WHERE role_name = $1;`
+const COUNT_ENGINE_ROLE_USERS = `SELECT count(*) FROM root_engine.engine_users JOIN root_engine.engine_roles ON engine_roles.id = engine_users.role_id WHERE engine_roles.role_name = $1;`
 const GET_ENGINE_USER_BY_EMAIL = `SELECT engine_users.id,engine_users.email,engine_users.password,engine_users.created_at,engine_users.role_id,engine_roles.role_name 
 FROM root_engine.engine_users 
 LEFT JOIN root_engine.engine_roles ON engine_roles.id = engine_users.role_id
 WHERE email = $1;`
 const CREATE_ENGINE_SUPER_USER = "INSERT INTO root_engine.engine_users (email,password,role_id) VALUES($1,$2,$3);"
+
+const DATA_CHANGE_CHANNEL = `engine_data_changes`
+const TRY_DATA_CHANGE_LISTENER_LOCK = `SELECT pg_try_advisory_lock(hashtext($1))`
+const RELEASE_DATA_CHANGE_LISTENER_LOCK = `SELECT pg_advisory_unlock(hashtext($1))`
+const SET_TRANSACTION_ENGINE_ORIGIN = `SELECT set_config('engine.origin', $1, true);`
+const SET_TRANSACTION_JWT_USER = `SELECT set_config('my.jwt_user', $1, true);`
+const CREATE_DATA_CHANGE_NOTIFY_FUNCTION = `CREATE OR REPLACE FUNCTION root_engine.engine_notify_data_change() RETURNS trigger AS $$
+DECLARE
+    payload jsonb;
+BEGIN
+    IF coalesce(current_setting('engine.origin', true), '') <> '' THEN
+        RETURN NULL;
+    END IF;
+    payload := jsonb_build_object(
+        'database', TG_TABLE_SCHEMA,
+        'table', TG_TABLE_NAME,
+        'operation', TG_OP,
+        'old', CASE WHEN TG_OP = 'INSERT' THEN NULL ELSE to_jsonb(OLD) END,
+        'new', CASE WHEN TG_OP = 'DELETE' THEN NULL ELSE to_jsonb(NEW) END
+    );
+    IF octet_length(payload::text) > 7900 THEN
+        payload := (payload - 'old' - 'new') || jsonb_build_object('truncated', true);
+    END IF;
+    PERFORM pg_notify('engine_data_changes', payload::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;`
+const CREATE_DATA_CHANGE_TRIGGER = `CREATE TRIGGER engine_data_change_capture AFTER INSERT OR UPDATE OR DELETE ON %s.%s FOR EACH ROW EXECUTE FUNCTION root_engine.engine_notify_data_change();`
+const DROP_DATA_CHANGE_TRIGGER = `DROP TRIGGER IF EXISTS engine_data_change_capture ON %s.%s;`
+const GET_DATA_CHANGE_TRIGGERS = `SELECT n.nspname, c.relname FROM pg_trigger t
+JOIN pg_class c ON c.oid = t.tgrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE t.tgname = 'engine_data_change_capture';`
+
+const GET_TABLE_SELECT_POLICIES = `SELECT c.relrowsecurity, p.permissive, p.qual FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_policies p ON p.schemaname = n.nspname AND p.tablename = c.relname AND p.cmd IN ('SELECT', 'ALL')
+AND EXISTS (SELECT 1 FROM unnest(p.roles) AS r(rolname) WHERE r.rolname = 'public' OR pg_has_role(current_user, r.rolname, 'MEMBER'))
+WHERE n.nspname = $1 AND c.relname = $2;`
+
+const EVENT_BUS_CHANNEL = "engine_events"
+const PUBLISH_EVENT_BUS_MESSAGE = `SELECT pg_notify($1, $2)`
+const CREATE_EVENT_BUS_PAYLOAD = `INSERT INTO root_engine.engine_event_payloads (payload, created_at) VALUES ($1, now()) RETURNING id`
+const GET_EVENT_BUS_PAYLOAD = `SELECT payload FROM root_engine.engine_event_payloads WHERE id = $1`
+const DELETE_EXPIRED_EVENT_BUS_PAYLOADS = `DELETE FROM root_engine.engine_event_payloads WHERE created_at < now() - make_interval(secs => $1)`
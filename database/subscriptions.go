@@ -0,0 +1,302 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Subscription struct {
+	Id         string      `json:"id"`
+	Database   string      `json:"database"`
+	Table      string      `json:"table"`
+	Operations []string    `json:"operations"`
+	Where      interface{} `json:"_where"`
+}
+
+func FormatSubscription(subscription Subscription) Subscription {
+	subscription.Database = FormatDBName(subscription.Database)
+	subscription.Table = strings.ToLower(strings.Trim(subscription.Table, " "))
+	if len(subscription.Operations) == 0 {
+		subscription.Operations = []string{INSERT_OPERATION, UPDATE_OPERATION, DELETE_OPERATION}
+	}
+	for i, operation := range subscription.Operations {
+		subscription.Operations[i] = strings.ToUpper(strings.Trim(operation, " "))
+	}
+	return subscription
+}
+
+func (engine *Engine) ValidateSubscription(auth jwt.MapClaims, subscription Subscription) error {
+	if len(subscription.Id) == 0 {
+		return fmt.Errorf("subscription id was not provided")
+	}
+
+	model, err := engine.GetModelByKey(subscription.Database, subscription.Table)
+	if err != nil {
+		return fmt.Errorf("table %s doesn't exist for database %s", subscription.Table, subscription.Database)
+	}
+
+	for _, operation := range subscription.Operations {
+		switch operation {
+		case INSERT_OPERATION, UPDATE_OPERATION, DELETE_OPERATION:
+		default:
+			return fmt.Errorf("not supported subscription operation %s", operation)
+		}
+	}
+
+	err = CanAccessDatabase(subscription.Database, auth)
+	if err != nil {
+		return err
+	}
+	_, _, err = model.ApplySubscriptionPermission(auth, subscription.Where)
+	return err
+}
+
+func (subscription Subscription) Matches(input DataTriggerInput) bool {
+	if subscription.Database != input.Database || subscription.Table != input.Table {
+		return false
+	}
+	for _, operation := range subscription.Operations {
+		if operation == input.Operation {
+			return true
+		}
+	}
+	return false
+}
+
+func ShouldEvaluateRLS(auth jwt.MapClaims) bool {
+	if !ShouldAuthenticate() {
+		return false
+	}
+	if _, ok := auth["bypass_all"]; ok {
+		return false
+	}
+	if _, ok := auth["bypass_auth"]; ok {
+		return false
+	}
+	return true
+}
+
+func GetTableSelectPolicyCondition(ctx context.Context, tx *sql.Tx, database string, table string) (string, error) {
+	rowSecurity := false
+	permissive := make([]string, 0)
+	restrictive := make([]string, 0)
+	scanner := QueryContext(ctx, tx, GET_TABLE_SELECT_POLICIES, database, table)
+	callback := func(rows *sql.Rows) error {
+		var policyType, qual sql.NullString
+		err := rows.Scan(&rowSecurity, &policyType, &qual)
+		if err != nil {
+			return err
+		}
+		if !policyType.Valid {
+			return nil
+		}
+		condition := "TRUE"
+		if qual.Valid {
+			condition = qual.String
+		}
+		if policyType.String == "RESTRICTIVE" {
+			restrictive = append(restrictive, fmt.Sprintf("(%s)", condition))
+		} else {
+			permissive = append(permissive, fmt.Sprintf("(%s)", condition))
+		}
+		return nil
+	}
+	err := scanner(callback)
+	if err != nil {
+		return "", err
+	}
+
+	if !rowSecurity {
+		return "TRUE", nil
+	}
+	if len(permissive) == 0 {
+		return "FALSE", nil
+	}
+
+	condition := fmt.Sprintf("(%s)", strings.Join(permissive, " OR "))
+	for _, entry := range restrictive {
+		condition += fmt.Sprintf(" AND %s", entry)
+	}
+	return condition, nil
+}
+
+func FilterArrayByIndexes(value any, indexes []int) any {
+	entries, err := IsArray(value)
+	if err != nil {
+		return value
+	}
+	filtered := make([]interface{}, 0)
+	for _, index := range indexes {
+		if index < len(entries) {
+			filtered = append(filtered, entries[index])
+		}
+	}
+	return filtered
+}
+
+type SubscriptionEventTarget struct {
+	Auth         jwt.MapClaims
+	Subscription Subscription
+}
+
+type SubscriptionEventResult struct {
+	Event DataTriggerInput
+	Ok    bool
+	Err   error
+}
+
+func (model *Model) BuildSubscriptionMatchQuery(policyCondition string, wheres []any) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	idx := 2
+	columns := make([]string, 0, len(wheres))
+	for _, where := range wheres {
+		whereClause, whereArgs := model.BuildWhereClause(where, model.Table, &idx, "", "")
+		if len(whereClause) == 0 {
+			whereClause = "TRUE"
+		}
+		columns = append(columns, fmt.Sprintf("(%s)", whereClause))
+		args = append(args, whereArgs...)
+	}
+	query := fmt.Sprintf(`SELECT %s.ordinality, %s FROM jsonb_populate_recordset(NULL::%s.%s, $1::jsonb) WITH ORDINALITY AS %s`, model.Table, strings.Join(columns, ", "), model.Database, model.Table, model.Table)
+	if len(policyCondition) > 0 {
+		query += fmt.Sprintf(" WHERE %s", policyCondition)
+	}
+	return query, args
+}
+
+func FilterSubscriptionEventRows(input DataTriggerInput, indexes []int, allowedColumns ColumnsMap) DataTriggerInput {
+	input.Payload = ProjectRows(FilterArrayByIndexes(input.Payload, indexes), allowedColumns)
+	if input.Old != nil {
+		input.Old = ProjectRows(FilterArrayByIndexes(input.Old, indexes), allowedColumns)
+	}
+	if input.New != nil {
+		input.New = ProjectRows(FilterArrayByIndexes(input.New, indexes), allowedColumns)
+	}
+	return input
+}
+
+func (engine *Engine) FilterSubscriptionEvents(db *sql.DB, targets []SubscriptionEventTarget, input DataTriggerInput) ([]SubscriptionEventResult, error) {
+	results := make([]SubscriptionEventResult, len(targets))
+	rows, err := IsArray(input.Payload)
+	if err != nil || len(rows) == 0 {
+		return results, nil
+	}
+
+	model, err := engine.GetModelByKey(input.Database, input.Table)
+	if err != nil {
+		return results, err
+	}
+
+	wheres := make([]any, len(targets))
+	allowedColumns := make([]ColumnsMap, len(targets))
+	groups := make(map[string][]int)
+	groupKeys := make([]string, 0)
+	for i, target := range targets {
+		if !target.Subscription.Matches(input) {
+			continue
+		}
+		if CanAccessDatabase(target.Subscription.Database, target.Auth) != nil {
+			continue
+		}
+		wheres[i], allowedColumns[i], err = model.ApplySubscriptionPermission(target.Auth, target.Subscription.Where)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		key := ""
+		if ShouldEvaluateRLS(target.Auth) {
+			claimsJson, err := json.Marshal(target.Auth)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			key = string(claimsJson)
+		}
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	if len(groupKeys) == 0 {
+		return results, nil
+	}
+
+	rowsJson, err := json.Marshal(rows)
+	if err != nil {
+		return results, err
+	}
+
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return results, err
+	}
+	defer tx.Rollback()
+
+	policyCondition := ""
+	for _, key := range groupKeys {
+		members := groups[key]
+		condition := ""
+		if len(key) > 0 {
+			err = engine.SetTransactionRLSPolicyInput(ctx, tx, targets[members[0]].Auth)
+			if err != nil {
+				return results, err
+			}
+			if len(policyCondition) == 0 {
+				policyCondition, err = GetTableSelectPolicyCondition(ctx, tx, model.Database, model.Table)
+				if err != nil {
+					return results, err
+				}
+			}
+			condition = policyCondition
+		}
+
+		groupWheres := make([]any, 0, len(members))
+		for _, member := range members {
+			groupWheres = append(groupWheres, wheres[member])
+		}
+		query, whereArgs := model.BuildSubscriptionMatchQuery(condition, groupWheres)
+		args := append([]interface{}{string(rowsJson)}, whereArgs...)
+
+		indexes := make([][]int, len(members))
+		scanner := QueryContext(ctx, tx, query, args...)
+		callback := func(rows *sql.Rows) error {
+			var ordinality int
+			matches := make([]bool, len(members))
+			values := []interface{}{&ordinality}
+			for i := range matches {
+				values = append(values, &matches[i])
+			}
+			err := rows.Scan(values...)
+			if err != nil {
+				return err
+			}
+			for i, match := range matches {
+				if match {
+					indexes[i] = append(indexes[i], ordinality-1)
+				}
+			}
+			return nil
+		}
+		err = scanner(callback)
+		if err != nil {
+			return results, err
+		}
+
+		for i, member := range members {
+			if len(indexes[i]) == 0 {
+				continue
+			}
+			results[member] = SubscriptionEventResult{
+				Event: FilterSubscriptionEventRows(input, indexes[i], allowedColumns[member]),
+				Ok:    true,
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildSubscriptionMatchQuery(t *testing.T) {
+	_, posts := NewQueryBuilderTestModels()
+	cases := []struct {
+		name            string
+		policyCondition string
+		wheres          []any
+		want            string
+		wantArgs        []interface{}
+	}{
+		{
+			name:     "one column per subscription",
+			wheres:   []any{nil, map[string]any{"likes": map[string]any{"_gt": 10.0}}},
+			want:     "SELECT posts.ordinality, (TRUE), (  posts.likes  > $2 ) FROM jsonb_populate_recordset(NULL::public.posts, $1::jsonb) WITH ORDINALITY AS posts",
+			wantArgs: []interface{}{10.0},
+		},
+		{
+			name:            "policy condition is shared",
+			policyCondition: "((author_id = 1))",
+			wheres:          []any{map[string]any{"title": map[string]any{"_eq": "a"}}, map[string]any{"title": map[string]any{"_eq": "b"}}},
+			want:            "SELECT posts.ordinality, (  posts.title  = $2 ), (  posts.title  = $3 ) FROM jsonb_populate_recordset(NULL::public.posts, $1::jsonb) WITH ORDINALITY AS posts WHERE ((author_id = 1))",
+			wantArgs:        []interface{}{"a", "b"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, args := posts.BuildSubscriptionMatchQuery(c.policyCondition, c.wheres)
+			if query != c.want {
+				t.Errorf("expected %q, got %q", c.want, query)
+			}
+			if !reflect.DeepEqual(args, c.wantArgs) {
+				t.Errorf("expected args %v, got %v", c.wantArgs, args)
+			}
+		})
+	}
+}
+
+func TestFilterSubscriptionEventRows(t *testing.T) {
+	input := DataTriggerInput{
+		Payload: []interface{}{map[string]interface{}{"id": 1.0, "secret": "a"}, map[string]interface{}{"id": 2.0, "secret": "b"}},
+		Old:     []interface{}{map[string]interface{}{"id": 1.0, "secret": "c"}, map[string]interface{}{"id": 2.0, "secret": "d"}},
+	}
+	filtered := FilterSubscriptionEventRows(input, []int{1}, ColumnsMap{"id": "bigint"})
+	if want := []interface{}{map[string]interface{}{"id": 2.0}}; !reflect.DeepEqual(filtered.Payload, want) {
+		t.Errorf("expected payload %v, got %v", want, filtered.Payload)
+	}
+	if want := []interface{}{map[string]interface{}{"id": 2.0}}; !reflect.DeepEqual(filtered.Old, want) {
+		t.Errorf("expected old rows %v, got %v", want, filtered.Old)
+	}
+	if filtered.New != nil {
+		t.Errorf("expected no new rows, got %v", filtered.New)
+	}
+}
+
+func TestFilterSubscriptionEventsSkipsUnmatchedTargets(t *testing.T) {
+	t.Setenv("DISABLE_AUTH", "ON")
+	_, posts := NewQueryBuilderTestModels()
+	engine := &Engine{DatabaseToTableToModelMap: map[string]map[string]*Model{"public": {"posts": posts}}}
+	input := DataTriggerInput{Database: "public", Table: "posts", Operation: INSERT_OPERATION, Payload: []interface{}{map[string]interface{}{"id": 1.0}}}
+	targets := []SubscriptionEventTarget{
+		{Subscription: Subscription{Id: "a", Database: "public", Table: "users", Operations: []string{INSERT_OPERATION}}},
+		{Subscription: Subscription{Id: "b", Database: "public", Table: "posts", Operations: []string{DELETE_OPERATION}}},
+	}
+	results, err := engine.FilterSubscriptionEvents(nil, targets, input)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(results) != 2 || results[0].Ok || results[1].Ok {
+		t.Errorf("expected no matching targets, got %v", results)
+	}
+}
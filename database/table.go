@@ -47,10 +47,31 @@ func GetTableNames(db *sql.DB, database string) ([]string, error) {
 	return tables, err
 }
 
+func GetTableKinds(db *sql.DB, database string) (map[string]string, error) {
+	kinds := make(map[string]string)
+	scanner := Query(db, GET_DATABASE_TABLE_KINDS, database)
+	cb := func(rows *sql.Rows) error {
+		var table, kind string
+		err := rows.Scan(&table, &kind)
+		kinds[table] = kind
+		return err
+	}
+	err := scanner(cb)
+
+	return kinds, err
+}
+
 func GetTableColumns(db *sql.DB, database string, table string) ([]Column, error) {
+	return ScanColumns(Query(db, GET_DATABASE_TABLE_COLUMN, database, table))
+}
+
+func GetMaterializedViewColumns(db *sql.DB, database string, view string) ([]Column, error) {
+	return ScanColumns(Query(db, GET_MATERIALIZED_VIEW_COLUMNS, database, view))
+}
+
+func ScanColumns(scanner func(func(rows *sql.Rows) error) error) ([]Column, error) {
 	columns := []Column{}
 
-	scanner := Query(db, GET_DATABASE_TABLE_COLUMN, database, table)
 	cb := func(rows *sql.Rows) error {
 		var column Column
 		var maxLength sql.NullInt64
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"application/environment"
+)
+
+var TEXT_SEARCH_KEY string = "_text_search"
+var TEXT_SEARCH_CONFIG_KEY string = "_text_search_config"
+var TEXT_SEARCH_RANK_KEY string = "_rank"
+
+var TEXT_SEARCH_CONFIG_PATTERN *regexp.Regexp = regexp.MustCompile("^[a-z_][a-z0-9_]*$")
+
+func GetTextSearchInput(value interface{}) (map[string]interface{}, bool) {
+	parsedValue, err := IsMapToInterface(value)
+	if err != nil {
+		return nil, false
+	}
+	_, ok := parsedValue[TEXT_SEARCH_KEY]
+	return parsedValue, ok
+}
+
+func GetTextSearchConfig(input map[string]interface{}) string {
+	config, _ := input[TEXT_SEARCH_CONFIG_KEY].(string)
+	if len(config) == 0 {
+		config = environment.GetEnvValue("TEXT_SEARCH_CONFIG")
+	}
+	return config
+}
+
+func BuildTextSearchExpressions(column string, input map[string]interface{}, idx *int) (string, string, []interface{}) {
+	args := make([]interface{}, 0)
+	config := GetTextSearchConfig(input)
+	configPrefix := ""
+	if TEXT_SEARCH_CONFIG_PATTERN.MatchString(config) {
+		configPrefix = fmt.Sprintf("'%s'::regconfig, ", config)
+	} else if len(config) > 0 {
+		configPrefix = fmt.Sprintf("$%d::regconfig, ", *idx)
+		args = append(args, config)
+		*idx += 1
+	}
+	vector := fmt.Sprintf("to_tsvector(%s%s)", configPrefix, column)
+	query := fmt.Sprintf("websearch_to_tsquery(%s$%d)", configPrefix, *idx)
+	args = append(args, fmt.Sprint(input[TEXT_SEARCH_KEY]))
+	*idx += 1
+	return vector, query, args
+}
+
+func BuildTextSearchCondition(column string, input map[string]interface{}, idx *int) (string, []interface{}) {
+	vector, query, args := BuildTextSearchExpressions(column, input, idx)
+	return fmt.Sprintf("%s @@ %s", vector, query), args
+}
+
+func IsTextSearchRankRequested(body interface{}) bool {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return false
+	}
+	if _, ok := GetOrderByValue(parsedBody["_orderBy"], TEXT_SEARCH_RANK_KEY); ok {
+		return true
+	}
+	return IsTextSearchRankSelected(body)
+}
+
+func IsTextSearchRankSelected(body interface{}) bool {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return false
+	}
+	_select, err := IsMapToInterface(parsedBody["_select"])
+	if err != nil {
+		return false
+	}
+	_, ok := _select[TEXT_SEARCH_RANK_KEY]
+	return ok
+}
+
+func GetTextSearchRankDirection(body interface{}) (string, bool) {
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return "", false
+	}
+	rank, _ := GetOrderByValue(parsedBody["_orderBy"], TEXT_SEARCH_RANK_KEY)
+	value, _ := rank.(string)
+	direction, ok := ORDER_BY_KEYS[value]
+	return direction, ok
+}
+
+func (model *Model) BuildTextSearchRank(body interface{}, alias string, idx *int) (string, []interface{}) {
+	args := make([]interface{}, 0)
+	if !IsTextSearchRankRequested(body) {
+		return "", args
+	}
+	parsedBody, err := IsMapToInterface(body)
+	if err != nil {
+		return "", args
+	}
+	parts, args := model.BuildTextSearchRankParts(parsedBody["_where"], alias, idx)
+	if len(parts) == 0 {
+		return "", args
+	}
+	return fmt.Sprintf("(%s)", strings.Join(parts, " + ")), args
+}
+
+func (model *Model) BuildTextSearchRankParts(where interface{}, alias string, idx *int) ([]string, []interface{}) {
+	parts := make([]string, 0)
+	args := make([]interface{}, 0)
+	if arr, err := IsArray(where); err == nil {
+		for _, value := range arr {
+			newParts, newArgs := model.BuildTextSearchRankParts(value, alias, idx)
+			parts = append(parts, newParts...)
+			args = append(args, newArgs...)
+		}
+		return parts, args
+	}
+	operation, err := IsMapToInterface(where)
+	if err != nil {
+		return parts, args
+	}
+	keys := make([]string, 0, len(operation))
+	for key := range operation {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, ok := QUERY_BINDER_KEYS[key]; ok {
+			newParts, newArgs := model.BuildTextSearchRankParts(operation[key], alias, idx)
+			parts = append(parts, newParts...)
+			args = append(args, newArgs...)
+			continue
+		}
+		if !model.isModelColumn(key) {
+			continue
+		}
+		input, ok := GetTextSearchInput(operation[key])
+		if !ok {
+			continue
+		}
+		vector, query, newArgs := BuildTextSearchExpressions(fmt.Sprintf("%s.%s", alias, key), input, idx)
+		parts = append(parts, fmt.Sprintf("ts_rank(%s, %s)", vector, query))
+		args = append(args, newArgs...)
+	}
+	return parts, args
+}
+
+func PrependOrderBy(orderByQuery string, part string) string {
+	existing := strings.TrimSpace(orderByQuery)
+	existing = strings.TrimSpace(strings.TrimPrefix(existing, "ORDER BY"))
+	if len(existing) == 0 {
+		return fmt.Sprintf(" ORDER BY %s ", part)
+	}
+	return fmt.Sprintf(" ORDER BY %s,%s ", part, existing)
+}
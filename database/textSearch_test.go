@@ -0,0 +1,110 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestBuildTextSearchCondition(t *testing.T) {
+	cases := []struct {
+		name       string
+		input      map[string]any
+		envConfig  string
+		want       string
+		wantArgs   []interface{}
+		wantNextId int
+	}{
+		{
+			name:       "without config",
+			input:      map[string]any{"_text_search": "cats"},
+			want:       "to_tsvector(posts.title) @@ websearch_to_tsquery($3)",
+			wantArgs:   []interface{}{"cats"},
+			wantNextId: 4,
+		},
+		{
+			name:       "plain config is inlined",
+			input:      map[string]any{"_text_search": "cats", "_text_search_config": "english"},
+			want:       "to_tsvector('english'::regconfig, posts.title) @@ websearch_to_tsquery('english'::regconfig, $3)",
+			wantArgs:   []interface{}{"cats"},
+			wantNextId: 4,
+		},
+		{
+			name:       "qualified config is a parameter",
+			input:      map[string]any{"_text_search": "cats", "_text_search_config": "pg_catalog.english"},
+			want:       "to_tsvector($3::regconfig, posts.title) @@ websearch_to_tsquery($3::regconfig, $4)",
+			wantArgs:   []interface{}{"pg_catalog.english", "cats"},
+			wantNextId: 5,
+		},
+		{
+			name:       "injected config is a parameter",
+			input:      map[string]any{"_text_search": "cats", "_text_search_config": "english'::regconfig, title) OR true --"},
+			want:       "to_tsvector($3::regconfig, posts.title) @@ websearch_to_tsquery($3::regconfig, $4)",
+			wantArgs:   []interface{}{"english'::regconfig, title) OR true --", "cats"},
+			wantNextId: 5,
+		},
+		{
+			name:       "environment default",
+			input:      map[string]any{"_text_search": "cats"},
+			envConfig:  "simple",
+			want:       "to_tsvector('simple'::regconfig, posts.title) @@ websearch_to_tsquery('simple'::regconfig, $3)",
+			wantArgs:   []interface{}{"cats"},
+			wantNextId: 4,
+		},
+		{
+			name:       "request config overrides environment",
+			input:      map[string]any{"_text_search": "cats", "_text_search_config": "german"},
+			envConfig:  "simple",
+			want:       "to_tsvector('german'::regconfig, posts.title) @@ websearch_to_tsquery('german'::regconfig, $3)",
+			wantArgs:   []interface{}{"cats"},
+			wantNextId: 4,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("TEXT_SEARCH_CONFIG", c.envConfig)
+			idx := 3
+			query, args := BuildTextSearchCondition("posts.title", c.input, &idx)
+			if query != c.want {
+				t.Errorf("expected %q, got %q", c.want, query)
+			}
+			if !reflect.DeepEqual(args, c.wantArgs) {
+				t.Errorf("expected args %v, got %v", c.wantArgs, args)
+			}
+			if idx != c.wantNextId {
+				t.Errorf("expected next index %d, got %d", c.wantNextId, idx)
+			}
+		})
+	}
+}
+
+func TestSelectTextSearchRank(t *testing.T) {
+	t.Setenv("TEXT_SEARCH_CONFIG", "")
+	_, posts := NewQueryBuilderTestModels()
+	body := map[string]any{
+		"_select":  map[string]any{"id": true, "_rank": true},
+		"_where":   map[string]any{"title": map[string]any{"_text_search": "cats", "_text_search_config": "pg_catalog.english"}},
+		"_orderBy": map[string]any{"_rank": "DESC", "id": "ASC"},
+	}
+	idx := 1
+	query, args, err := posts.Select(jwt.MapClaims{"bypass_all": true}, body, 0, &idx, nil, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, part := range []string{
+		"(SELECT _0_posts.id,_0_posts._rank )",
+		"(ts_rank(to_tsvector($3::regconfig, _0_posts.title), websearch_to_tsquery($3::regconfig, $4))) AS _rank",
+		"WHERE   to_tsvector($1::regconfig, _0_posts.title) @@ websearch_to_tsquery($1::regconfig, $2)",
+		"ORDER BY _rank DESC,_0_posts.id ASC",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("expected %q in %q", part, query)
+		}
+	}
+	wantArgs := []interface{}{"pg_catalog.english", "cats", "pg_catalog.english", "cats"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("expected args %v, got %v", wantArgs, args)
+	}
+}
@@ -0,0 +1,218 @@
+package database
+
+import (
+	"application/environment"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+var TABLE_KIND string = "TABLE"
+var VIEW_KIND string = "VIEW"
+var MATERIALIZED_VIEW_KIND string = "MATERIALIZED_VIEW"
+
+type MaterializedViewRefreshInput struct {
+	Database     string `json:"database"`
+	Table        string `json:"table"`
+	Concurrently bool   `json:"concurrently"`
+}
+
+type MaterializedViewRefresh struct {
+	Id              int64  `json:"id"`
+	Database        string `json:"database"`
+	Table           string `json:"table"`
+	Concurrently    bool   `json:"concurrently"`
+	IntervalSeconds int    `json:"interval_seconds"`
+	Enabled         bool   `json:"enabled"`
+	NextRefreshAt   string `json:"next_refresh_at"`
+	LastRefreshedAt string `json:"last_refreshed_at"`
+	LastError       string `json:"last_error"`
+}
+
+func (model *Model) IsReadOnly() bool {
+	return model.Kind == VIEW_KIND || model.Kind == MATERIALIZED_VIEW_KIND
+}
+
+func (model *Model) IsMaterializedView() bool {
+	return model.Kind == MATERIALIZED_VIEW_KIND
+}
+
+func (e *Engine) GetWritableModelByKey(database, key string) (*Model, error) {
+	model, err := e.GetModelByKey(database, key)
+	if err != nil {
+		return nil, err
+	}
+	if model.IsReadOnly() {
+		return nil, fmt.Errorf("%s %s is read-only", model.Database, model.Table)
+	}
+	return model, nil
+}
+
+func (e *Engine) GetMaterializedView(database string, table string) (*Model, error) {
+	model, err := e.GetModelByKey(database, table)
+	if err != nil {
+		return nil, fmt.Errorf("materialized view %s doesn't exist for database %s", table, database)
+	}
+	if !model.IsMaterializedView() {
+		return nil, fmt.Errorf("%s of database %s is not a materialized view", table, database)
+	}
+	return model, nil
+}
+
+func (e *Engine) GetMaterializedViewSnapshot(database string, table string) (*Model, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return e.GetMaterializedView(database, table)
+}
+
+func (e *Engine) RefreshMaterializedView(db *sql.DB, input MaterializedViewRefreshInput) error {
+	model, err := e.GetMaterializedView(input.Database, input.Table)
+	if err != nil {
+		return err
+	}
+	return RefreshMaterializedViewModel(db, model, input.Concurrently)
+}
+
+func RefreshMaterializedViewModel(db *sql.DB, model *Model, isConcurrent bool) error {
+	concurrently := ""
+	if isConcurrent {
+		concurrently = "CONCURRENTLY "
+	}
+	query := fmt.Sprintf(REFRESH_MATERIALIZED_VIEW, concurrently, model.Database, model.Table)
+	LogSql(query)
+	_, err := db.Exec(query)
+	return err
+}
+
+func GetMaterializedViewRefreshes(db *sql.DB) ([]MaterializedViewRefresh, error) {
+	refreshes := make([]MaterializedViewRefresh, 0)
+	scanner := Query(db, GET_MATERIALIZED_VIEW_REFRESHES)
+	cb := func(rows *sql.Rows) error {
+		var refresh MaterializedViewRefresh
+		var nextRefreshAt, lastRefreshedAt sql.NullTime
+		var lastError sql.NullString
+		err := rows.Scan(&refresh.Id, &refresh.Database, &refresh.Table, &refresh.Concurrently, &refresh.IntervalSeconds, &refresh.Enabled, &nextRefreshAt, &lastRefreshedAt, &lastError)
+		if err != nil {
+			return err
+		}
+		if nextRefreshAt.Valid {
+			refresh.NextRefreshAt = nextRefreshAt.Time.Format(time.RFC3339)
+		}
+		if lastRefreshedAt.Valid {
+			refresh.LastRefreshedAt = lastRefreshedAt.Time.Format(time.RFC3339)
+		}
+		refresh.LastError = lastError.String
+		refreshes = append(refreshes, refresh)
+		return nil
+	}
+	err := scanner(cb)
+	return refreshes, err
+}
+
+func (e *Engine) ValidateMaterializedViewRefresh(input MaterializedViewRefresh) error {
+	_, err := e.GetMaterializedView(input.Database, input.Table)
+	if err != nil {
+		return err
+	}
+	if input.IntervalSeconds <= 0 {
+		return fmt.Errorf("please provide a positive refresh interval in seconds")
+	}
+	return nil
+}
+
+func (e *Engine) CreateMaterializedViewRefresh(db *sql.DB, input MaterializedViewRefresh) error {
+	err := e.ValidateMaterializedViewRefresh(input)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(CREATE_MATERIALIZED_VIEW_REFRESH, input.Database, input.Table, input.Concurrently, input.IntervalSeconds, input.Enabled)
+	return err
+}
+
+func (e *Engine) UpdateMaterializedViewRefreshByID(db *sql.DB, input MaterializedViewRefresh) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("materialized view refresh id was not provided")
+	}
+	err := e.ValidateMaterializedViewRefresh(input)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(UPDATE_MATERIALIZED_VIEW_REFRESH_BY_ID, input.Database, input.Table, input.Concurrently, input.IntervalSeconds, input.Enabled, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("materialized view refresh %d doesn't exist", input.Id))
+}
+
+func (e *Engine) DeleteMaterializedViewRefreshByID(db *sql.DB, input MaterializedViewRefresh) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("materialized view refresh id was not provided")
+	}
+	result, err := db.Exec(DELETE_MATERIALIZED_VIEW_REFRESH_BY_ID, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("materialized view refresh %d doesn't exist", input.Id))
+}
+
+func (e *Engine) DeleteMaterializedViewRefreshesByDatabase(db *sql.DB, input MaterializedViewRefresh) error {
+	_, err := db.Exec(DELETE_MATERIALIZED_VIEW_REFRESHES_BY_DATABASE_NAME, input.Database)
+	return err
+}
+
+func (e *Engine) DeleteMaterializedViewRefreshesByDatabaseTable(db *sql.DB, input MaterializedViewRefresh) error {
+	_, err := db.Exec(DELETE_MATERIALIZED_VIEW_REFRESHES_BY_DATABASE_TABLE_NAME, input.Database, input.Table)
+	return err
+}
+
+func (e *Engine) ClaimMaterializedViewRefreshes(db *sql.DB) ([]MaterializedViewRefresh, error) {
+	refreshes := make([]MaterializedViewRefresh, 0)
+	scanner := Query(db, CLAIM_MATERIALIZED_VIEW_REFRESHES)
+	cb := func(rows *sql.Rows) error {
+		var refresh MaterializedViewRefresh
+		err := rows.Scan(&refresh.Id, &refresh.Database, &refresh.Table, &refresh.Concurrently)
+		if err != nil {
+			return err
+		}
+		refreshes = append(refreshes, refresh)
+		return nil
+	}
+	err := scanner(cb)
+	return refreshes, err
+}
+
+func (e *Engine) RunMaterializedViewRefreshes(db *sql.DB) error {
+	refreshes, err := e.ClaimMaterializedViewRefreshes(db)
+	if err != nil {
+		return err
+	}
+	for _, refresh := range refreshes {
+		message := ""
+		model, err := e.GetMaterializedViewSnapshot(refresh.Database, refresh.Table)
+		if err == nil {
+			err = RefreshMaterializedViewModel(db, model, refresh.Concurrently)
+		}
+		if err != nil {
+			message = err.Error()
+		}
+		_, err = db.Exec(COMPLETE_MATERIALIZED_VIEW_REFRESH, refresh.Id, message)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) StartMaterializedViewRefresher(db *sql.DB) {
+	interval := environment.GetEnvValueToIntWithDefault("MATERIALIZED_VIEW_REFRESH_CHECK_INTERVAL_IN_SECONDS", 30)
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	go func() {
+		defer ticker.Stop()
+		for range ticker.C {
+			err := e.RunMaterializedViewRefreshes(db)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func NewViewsTestEngine() *Engine {
+	users := NewModel("public", "users")
+	activeUsers := NewModel("public", "active_users")
+	activeUsers.Kind = VIEW_KIND
+	userStats := NewModel("public", "user_stats")
+	userStats.Kind = MATERIALIZED_VIEW_KIND
+	for _, model := range []*Model{users, activeUsers, userStats} {
+		model.Columns = []Column{{Name: "id"}}
+		model.ColumnsMap = ColumnsMap{"id": "bigint"}
+	}
+	return &Engine{
+		Models: []*Model{users, activeUsers, userStats},
+		DatabaseToTableToModelMap: map[string]map[string]*Model{
+			"public": {"users": users, "active_users": activeUsers, "user_stats": userStats},
+		},
+	}
+}
+
+func TestGetWritableModelByKey(t *testing.T) {
+	engine := NewViewsTestEngine()
+	cases := []struct {
+		key     string
+		wantErr string
+	}{
+		{key: "users"},
+		{key: "active_users", wantErr: "public active_users is read-only"},
+		{key: "user_stats", wantErr: "public user_stats is read-only"},
+		{key: "missing", wantErr: "no such model missing"},
+	}
+	for _, c := range cases {
+		t.Run(c.key, func(t *testing.T) {
+			model, err := engine.GetWritableModelByKey("public", c.key)
+			if len(c.wantErr) > 0 {
+				if err == nil || err.Error() != c.wantErr {
+					t.Fatalf("expected error %q, got %v", c.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if model.Table != c.key {
+				t.Errorf("expected model %s, got %s", c.key, model.Table)
+			}
+		})
+	}
+}
+
+func TestValidateMaterializedViewRefresh(t *testing.T) {
+	engine := NewViewsTestEngine()
+	cases := []struct {
+		name    string
+		input   MaterializedViewRefresh
+		wantErr string
+	}{
+		{name: "valid", input: MaterializedViewRefresh{Database: "public", Table: "user_stats", IntervalSeconds: 60}},
+		{name: "view", input: MaterializedViewRefresh{Database: "public", Table: "active_users", IntervalSeconds: 60}, wantErr: "active_users of database public is not a materialized view"},
+		{name: "table", input: MaterializedViewRefresh{Database: "public", Table: "users", IntervalSeconds: 60}, wantErr: "users of database public is not a materialized view"},
+		{name: "missing", input: MaterializedViewRefresh{Database: "public", Table: "missing", IntervalSeconds: 60}, wantErr: "materialized view missing doesn't exist for database public"},
+		{name: "interval", input: MaterializedViewRefresh{Database: "public", Table: "user_stats"}, wantErr: "please provide a positive refresh interval in seconds"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := engine.ValidateMaterializedViewRefresh(c.input)
+			if len(c.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.wantErr {
+				t.Fatalf("expected error %q, got %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSelectFromView(t *testing.T) {
+	engine := NewViewsTestEngine()
+	for _, key := range []string{"active_users", "user_stats"} {
+		t.Run(key, func(t *testing.T) {
+			model, err := engine.GetModelByKey("public", key)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			idx := 1
+			query, _, err := model.Select(jwt.MapClaims{"bypass_all": true}, map[string]any{"_total": true}, 0, &idx, nil, "", false)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			source := "FROM public." + key + " _0_" + key
+			if strings.Count(query, source) != 2 {
+				t.Errorf("expected rows and total to read %q, got %q", source, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package database
+
+import (
+	"application/environment"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var WEBHOOK_DELIVERY_PENDING string = "PENDING"
+var WEBHOOK_DELIVERY_DELIVERED string = "DELIVERED"
+var WEBHOOK_DELIVERY_DEAD string = "DEAD"
+
+type WebhookDelivery struct {
+	Id             int64  `json:"id"`
+	WebhookId      int64  `json:"webhook_id"`
+	Endpoint       string `json:"endpoint"`
+	Database       string `json:"database"`
+	Table          string `json:"table"`
+	Operation      string `json:"operation"`
+	Payload        any    `json:"payload"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	MaxAttempts    int    `json:"max_attempts"`
+	LastError      string `json:"last_error"`
+	LastStatusCode int    `json:"last_status_code"`
+	NextAttemptAt  string `json:"next_attempt_at"`
+	CreatedAt      string `json:"created_at"`
+	DeliveredAt    string `json:"delivered_at"`
+	auth           string
+	body           []byte
+}
+
+type WebhookDeliveriesInput struct {
+	Status string  `json:"status"`
+	Limit  int     `json:"limit"`
+	Ids    []int64 `json:"ids"`
+}
+
+type WebhookDispatcher struct {
+	signal chan struct{}
+	client *http.Client
+}
+
+func GetWebhookMaxAttempts() int {
+	return environment.GetEnvValueToIntWithDefault("WEBHOOK_MAX_ATTEMPTS", 5)
+}
+
+func GetWebhookRetryDelay(attempts int) time.Duration {
+	base := environment.GetEnvValueToIntWithDefault("WEBHOOK_RETRY_BASE_DELAY_IN_SECONDS", 5)
+	max := environment.GetEnvValueToIntWithDefault("WEBHOOK_RETRY_MAX_DELAY_IN_SECONDS", 3600)
+	delay := float64(base) * math.Pow(2, float64(attempts-1))
+	if delay > float64(max) {
+		delay = float64(max)
+	}
+	return time.Duration(delay) * time.Second
+}
+
+func GetWebhookDeliveryTimeout() time.Duration {
+	seconds := environment.GetEnvValueToIntWithDefault("WEBHOOK_DELIVERY_TIMEOUT_IN_SECONDS", 10)
+	return time.Duration(seconds) * time.Second
+}
+
+func NewWebhookDispatcher() *WebhookDispatcher {
+	return &WebhookDispatcher{
+		signal: make(chan struct{}, 1),
+		client: &http.Client{Timeout: GetWebhookDeliveryTimeout()},
+	}
+}
+
+func (dispatcher *WebhookDispatcher) Notify() {
+	select {
+	case dispatcher.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (engine *Engine) StartWebhookDispatcher(db *sql.DB) {
+	interval := environment.GetEnvValueToIntWithDefault("WEBHOOK_DISPATCH_INTERVAL_IN_SECONDS", 5)
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	retention := environment.GetEnvValueToIntWithDefault("WEBHOOK_DELIVERY_RETENTION_IN_SECONDS", 604800)
+	cleanupInterval := retention
+	if cleanupInterval <= 0 || cleanupInterval > 3600 {
+		cleanupInterval = 3600
+	}
+	cleanup := time.NewTicker(time.Duration(cleanupInterval) * time.Second)
+	go func() {
+		defer ticker.Stop()
+		defer cleanup.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-engine.WebhookDispatcher.signal:
+			case <-cleanup.C:
+				_, err := db.Exec(DELETE_EXPIRED_WEBHOOK_DELIVERIES, retention)
+				if err != nil {
+					fmt.Println(err)
+				}
+				continue
+			}
+			err := engine.DispatchWebhookDeliveries(db)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+	}()
+}
+
+func (engine *Engine) EnqueueWebhooks(ctx context.Context, tx *sql.Tx, input WebhookExecInput) error {
+	webhooks, err := engine.GetDatabaseTableOperationTypeWebhooks(input.Database, input.Table, input.Operation, input.Type)
+	if err != nil {
+		return nil
+	}
+
+	payload := WebhookPayload{
+		Database:  input.Database,
+		Table:     input.Table,
+		Operation: input.Operation,
+		Data:      input.Payload,
+		Old:       input.Old,
+		New:       input.New,
+		RequestId: input.RequestId,
+		Claims:    input.Claims,
+		Timestamp: input.Timestamp,
+	}
+
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.IsEnabledForChannel(input.Channel) {
+			continue
+		}
+		var auth sql.NullString
+		if webhook.ForwardAuthHeaders {
+			auth = sql.NullString{String: input.Auth, Valid: true}
+		}
+		_, err := tx.ExecContext(ctx, CREATE_WEBHOOK_DELIVERY,
+			webhook.Id,
+			webhook.Endpoint,
+			input.Database,
+			input.Table,
+			input.Operation,
+			string(jsonBody),
+			auth,
+			WEBHOOK_DELIVERY_PENDING,
+			GetWebhookMaxAttempts(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (engine *Engine) EnqueueWebhooksFromInputs(ctx context.Context, tx *sql.Tx, inputs []WebhookExecInput) error {
+	for _, input := range inputs {
+		err := engine.EnqueueWebhooks(ctx, tx, input)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (engine *Engine) ClaimWebhookDeliveries(db *sql.DB) ([]WebhookDelivery, error) {
+	batchSize := environment.GetEnvValueToIntWithDefault("WEBHOOK_DISPATCH_BATCH_SIZE", 50)
+	lease := GetWebhookDeliveryTimeout() + 30*time.Second
+	deliveries := make([]WebhookDelivery, 0)
+	scanner := Query(db, CLAIM_WEBHOOK_DELIVERIES, batchSize, lease.Seconds())
+	callback := func(rows *sql.Rows) error {
+		var row WebhookDelivery
+		var auth sql.NullString
+		err := rows.Scan(&row.Id, &row.WebhookId, &row.Endpoint, &row.Database, &row.Table, &row.Operation, &row.body, &auth, &row.Attempts, &row.MaxAttempts)
+		if err != nil {
+			return err
+		}
+		row.auth = auth.String
+		deliveries = append(deliveries, row)
+		return nil
+	}
+	err := scanner(callback)
+	return deliveries, err
+}
+
+func (engine *Engine) DispatchWebhookDeliveries(db *sql.DB) error {
+	deliveries, err := engine.ClaimWebhookDeliveries(db)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery WebhookDelivery) {
+			defer wg.Done()
+			statusCode, err := engine.DeliverWebhook(delivery)
+			err = engine.CompleteWebhookDelivery(db, delivery, statusCode, err)
+			if err != nil {
+				fmt.Println(err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (engine *Engine) DeliverWebhook(delivery WebhookDelivery) (int, error) {
+	webhook, err := engine.GetWebhookByID(delivery.WebhookId)
+	if err != nil {
+		return 0, err
+	}
+	if !webhook.Enabled {
+		return 0, &WebhookDisabledError{Id: delivery.WebhookId}
+	}
+
+	req, err := http.NewRequest("POST", delivery.Endpoint, bytes.NewBuffer(delivery.body))
+	if err != nil {
+		return 0, err
+	}
+
+	SetWebhookRequestHeaders(req, webhook, delivery.body)
+	if len(delivery.auth) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", delivery.auth))
+	}
+
+	res, err := engine.WebhookDispatcher.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		message := strings.Trim(string(responseBody), " \n")
+		if len(message) == 0 {
+			message = http.StatusText(res.StatusCode)
+		}
+		return res.StatusCode, fmt.Errorf("webhook responded with status %d: %s", res.StatusCode, message)
+	}
+
+	return res.StatusCode, nil
+}
+
+func (engine *Engine) CompleteWebhookDelivery(db *sql.DB, delivery WebhookDelivery, statusCode int, deliveryErr error) error {
+	lastStatusCode := sql.NullInt64{Int64: int64(statusCode), Valid: statusCode > 0}
+	attempts := delivery.Attempts + 1
+	if deliveryErr == nil {
+		_, err := db.Exec(UPDATE_WEBHOOK_DELIVERY_DELIVERED, delivery.Id, attempts, lastStatusCode)
+		return err
+	}
+
+	var notFound *WebhookNotFoundError
+	var disabled *WebhookDisabledError
+	status := WEBHOOK_DELIVERY_PENDING
+	if attempts >= delivery.MaxAttempts || errors.As(deliveryErr, &notFound) || errors.As(deliveryErr, &disabled) {
+		status = WEBHOOK_DELIVERY_DEAD
+	}
+	delay := GetWebhookRetryDelay(attempts)
+	_, err := db.Exec(UPDATE_WEBHOOK_DELIVERY_FAILED, delivery.Id, status, attempts, delay.Seconds(), deliveryErr.Error(), lastStatusCode)
+	return err
+}
+
+func ValidateWebhookDeliveryStatus(status string) error {
+	switch status {
+	case WEBHOOK_DELIVERY_PENDING, WEBHOOK_DELIVERY_DELIVERED, WEBHOOK_DELIVERY_DEAD:
+		return nil
+	default:
+		return fmt.Errorf("not supported webhook delivery status %s", status)
+	}
+}
+
+func (engine *Engine) GetWebhookDeliveries(db *sql.DB, input WebhookDeliveriesInput) ([]WebhookDelivery, error) {
+	input.Status = strings.ToUpper(strings.Trim(input.Status, " "))
+	if len(input.Status) == 0 {
+		input.Status = WEBHOOK_DELIVERY_DEAD
+	}
+	err := ValidateWebhookDeliveryStatus(input.Status)
+	if err != nil {
+		return nil, err
+	}
+	if input.Limit <= 0 {
+		input.Limit = 100
+	}
+
+	deliveries := make([]WebhookDelivery, 0)
+	scanner := Query(db, GET_WEBHOOK_DELIVERIES_BY_STATUS, input.Status, input.Limit)
+	callback := func(rows *sql.Rows) error {
+		var row WebhookDelivery
+		var lastError, deliveredAt sql.NullString
+		var lastStatusCode sql.NullInt64
+		var payload []byte
+		err := rows.Scan(&row.Id, &row.WebhookId, &row.Endpoint, &row.Database, &row.Table, &row.Operation, &payload, &row.Status, &row.Attempts, &row.MaxAttempts, &lastError, &lastStatusCode, &row.NextAttemptAt, &row.CreatedAt, &deliveredAt)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(payload, &row.Payload)
+		if err != nil {
+			return err
+		}
+		row.LastError = lastError.String
+		row.LastStatusCode = int(lastStatusCode.Int64)
+		row.DeliveredAt = deliveredAt.String
+		deliveries = append(deliveries, row)
+		return nil
+	}
+	err = scanner(callback)
+	return deliveries, err
+}
+
+func (engine *Engine) ReplayWebhookDeliveries(db *sql.DB, input WebhookDeliveriesInput) (int64, error) {
+	if len(input.Ids) == 0 {
+		return 0, fmt.Errorf("webhook delivery ids were not provided")
+	}
+	result, err := db.Exec(REPLAY_WEBHOOK_DELIVERIES, pq.Array(input.Ids), WEBHOOK_DELIVERY_PENDING, WEBHOOK_DELIVERY_DEAD, GetWebhookMaxAttempts())
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 0 {
+		return 0, fmt.Errorf("no dead webhook deliveries were found for the provided ids")
+	}
+	engine.WebhookDispatcher.Notify()
+	return affected, nil
+}
@@ -1,27 +1,61 @@
 package database
 
 import (
+	"application/environment"
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type Webhook struct {
-	Id                 string
-	Endpoint           string
-	Enabled            bool
-	Database           string
-	Table              string
-	Operation          string
-	RestEnabled        bool
-	GraphQLEnabled     bool
-	CreatedAt          string
-	Type               string
-	ForwardAuthHeaders bool
+	Id                 string            `json:"id"`
+	Endpoint           string            `json:"endpoint"`
+	Enabled            bool              `json:"enabled"`
+	Database           string            `json:"database"`
+	Table              string            `json:"table"`
+	Operation          string            `json:"operation"`
+	RestEnabled        bool              `json:"rest"`
+	GraphQLEnabled     bool              `json:"graphql"`
+	CreatedAt          string            `json:"created_at"`
+	Type               string            `json:"type"`
+	ForwardAuthHeaders bool              `json:"forward_auth_headers"`
+	Secret             string            `json:"-"`
+	Headers            map[string]string `json:"headers"`
+}
+
+type WebhookInput struct {
+	Id                 int64             `json:"id"`
+	Endpoint           string            `json:"endpoint"`
+	Enabled            bool              `json:"enabled"`
+	Database           string            `json:"database"`
+	Table              string            `json:"table"`
+	Operation          string            `json:"operation"`
+	RestEnabled        bool              `json:"rest"`
+	GraphQLEnabled     bool              `json:"graphql"`
+	Type               string            `json:"type"`
+	ForwardAuthHeaders bool              `json:"forward_auth_headers"`
+	Secret             string            `json:"secret"`
+	ClearSecret        bool              `json:"clear_secret"`
+	Headers            map[string]string `json:"headers"`
+	ClearHeaders       bool              `json:"clear_headers"`
 }
 
+var WEBHOOK_SIGNATURE_HEADER string = "X-Engine-Signature"
+var WEBHOOK_TIMESTAMP_HEADER string = "X-Engine-Timestamp"
+
 var PRE_EXEC string = "PRE_EXEC"
 
 var POST_EXEC string = "POST_EXEC"
@@ -37,14 +71,133 @@ type WebhookExecInput struct {
 	Table     string
 	Operation string
 	Type      string
+	Channel   string
 	Payload   any
 	Auth      string
+	Claims    jwt.MapClaims
+	Old       any
+	New       any
+	RequestId string
+	Timestamp string
 }
 
 type WebhookPayload struct {
-	Database string `json:"database"`
-	Table    string `json:"table"`
-	Data     any    `json:"data"`
+	Database  string        `json:"database"`
+	Table     string        `json:"table"`
+	Operation string        `json:"operation"`
+	Data      any           `json:"data"`
+	Old       any           `json:"old"`
+	New       any           `json:"new"`
+	RequestId string        `json:"request_id"`
+	Claims    jwt.MapClaims `json:"claims"`
+	Timestamp string        `json:"timestamp"`
+}
+
+type PreExecWebhookPayload struct {
+	Database  string        `json:"database"`
+	Table     string        `json:"table"`
+	Operation string        `json:"operation"`
+	Type      string        `json:"type"`
+	Data      any           `json:"data"`
+	Auth      jwt.MapClaims `json:"auth"`
+}
+
+type PreExecWebhookResponse struct {
+	Data    any    `json:"data"`
+	Message string `json:"message"`
+}
+
+type WebhookRejectionError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *WebhookRejectionError) Error() string {
+	return e.Message
+}
+
+type RequestError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+func NewBadRequestError(format string, args ...any) *RequestError {
+	return &RequestError{StatusCode: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+func NewForbiddenError(format string, args ...any) *RequestError {
+	return &RequestError{StatusCode: http.StatusForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+func GetErrorStatusCode(err error, defaultStatusCode int) int {
+	var rejection *WebhookRejectionError
+	if errors.As(err, &rejection) {
+		return rejection.StatusCode
+	}
+	var requestError *RequestError
+	if errors.As(err, &requestError) {
+		return requestError.StatusCode
+	}
+	return defaultStatusCode
+}
+
+func GetPreExecWebhookTimeout() time.Duration {
+	seconds := environment.GetEnvValueToIntWithDefault("PRE_EXEC_WEBHOOK_TIMEOUT_IN_SECONDS", 10)
+	return time.Duration(seconds) * time.Second
+}
+
+func BuildWebhookExecInputs(auth jwt.MapClaims, requestId string, role string, database string, operation string, channel string, results interface{}, previousResults map[string][]interface{}) []WebhookExecInput {
+	inputs := make([]WebhookExecInput, 0)
+	parsedResults, err := IsMapToArray(results)
+	if err != nil {
+		return inputs
+	}
+	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	for key, payload := range parsedResults {
+		input := WebhookExecInput{
+			Database:  database,
+			Table:     key,
+			Operation: operation,
+			Type:      POST_EXEC,
+			Channel:   channel,
+			Payload:   payload,
+			Auth:      role,
+			Claims:    auth,
+			RequestId: requestId,
+			Timestamp: timestamp,
+		}
+		switch operation {
+		case INSERT_OPERATION:
+			input.New = payload
+		case UPDATE_OPERATION:
+			input.Old = previousResults[key]
+			input.New = payload
+		case DELETE_OPERATION:
+			input.Old = payload
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+func (input WebhookExecInput) ToDataTriggerInput() DataTriggerInput {
+	return DataTriggerInput{
+		Database:  input.Database,
+		Table:     input.Table,
+		Operation: input.Operation,
+		Payload:   input.Payload,
+		Auth:      input.Auth,
+		Type:      input.Channel,
+		Old:       input.Old,
+		New:       input.New,
+		RequestId: input.RequestId,
+		Claims:    input.Claims,
+		Timestamp: input.Timestamp,
+	}
 }
 
 func (enigne *Engine) LoadWebhooks(db *sql.DB) map[string]map[string]map[string]map[string][]Webhook {
@@ -52,10 +205,20 @@ func (enigne *Engine) LoadWebhooks(db *sql.DB) map[string]map[string]map[string]
 	webhooks := make(map[string]map[string]map[string]map[string][]Webhook)
 	callback := func(rows *sql.Rows) error {
 		var row Webhook
-		err := rows.Scan(&row.Id, &row.Endpoint, &row.Enabled, &row.Database, &row.Table, &row.Operation, &row.RestEnabled, &row.GraphQLEnabled, &row.CreatedAt, &row.Type, &row.ForwardAuthHeaders)
+		var secret sql.NullString
+		var headers []byte
+		err := rows.Scan(&row.Id, &row.Endpoint, &row.Enabled, &row.Database, &row.Table, &row.Operation, &row.RestEnabled, &row.GraphQLEnabled, &row.CreatedAt, &row.Type, &row.ForwardAuthHeaders, &secret, &headers)
 		if err != nil {
 			return err
 		}
+		row.Secret = secret.String
+		row.Headers = make(map[string]string)
+		if len(headers) > 0 {
+			err = json.Unmarshal(headers, &row.Headers)
+			if err != nil {
+				return err
+			}
+		}
 		if _, ok := webhooks[row.Database]; !ok {
 			webhooks[row.Database] = make(map[string]map[string]map[string][]Webhook)
 		}
@@ -80,6 +243,281 @@ func (enigne *Engine) LoadWebhooks(db *sql.DB) map[string]map[string]map[string]
 	return webhooks
 }
 
+func (engine *Engine) GetWebhooksList() []Webhook {
+	webhooks := make([]Webhook, 0)
+	for _, tables := range engine.Webhooks {
+		for _, operations := range tables {
+			for _, types := range operations {
+				for _, entries := range types {
+					webhooks = append(webhooks, entries...)
+				}
+			}
+		}
+	}
+	return webhooks
+}
+
+func ValidateWebhookOperation(input WebhookInput) error {
+	switch input.Operation {
+	case INSERT_OPERATION, UPDATE_OPERATION, DELETE_OPERATION:
+		return nil
+	default:
+		return fmt.Errorf("not supported webhook operation %s", input.Operation)
+	}
+}
+
+func ValidateWebhookType(input WebhookInput) error {
+	if input.Type != PRE_EXEC && input.Type != POST_EXEC {
+		return fmt.Errorf("not supported webhook type %s", input.Type)
+	}
+	return nil
+}
+
+func ValidateWebhookEndpoint(input WebhookInput) error {
+	endpoint, err := url.ParseRequestURI(input.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid webhook endpoint %s", input.Endpoint)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return fmt.Errorf("webhook endpoint should use http or https")
+	}
+	if len(endpoint.Host) == 0 {
+		return fmt.Errorf("invalid webhook endpoint %s", input.Endpoint)
+	}
+	return nil
+}
+
+func ValidateWebhookHeaders(input WebhookInput) error {
+	for key := range input.Headers {
+		if len(key) == 0 || strings.ContainsAny(key, " :\r\n") {
+			return fmt.Errorf("invalid webhook header name %s", key)
+		}
+		switch http.CanonicalHeaderKey(key) {
+		case "Content-Type", "Content-Length", "Host", WEBHOOK_SIGNATURE_HEADER, WEBHOOK_TIMESTAMP_HEADER:
+			return fmt.Errorf("webhook header %s is reserved", key)
+		}
+	}
+	return nil
+}
+
+func GetWebhookHeadersJson(input WebhookInput) (any, error) {
+	if len(input.Headers) == 0 {
+		return nil, nil
+	}
+	headers, err := json.Marshal(input.Headers)
+	if err != nil {
+		return nil, err
+	}
+	return string(headers), nil
+}
+
+func FormatWebhookInput(input WebhookInput) WebhookInput {
+	input.Database = FormatDBName(input.Database)
+	input.Table = strings.ToLower(strings.Trim(input.Table, " "))
+	input.Endpoint = strings.Trim(input.Endpoint, " ")
+	input.Operation = strings.ToUpper(strings.Trim(input.Operation, " "))
+	input.Type = strings.ToUpper(strings.Trim(input.Type, " "))
+	if len(input.Type) == 0 {
+		input.Type = POST_EXEC
+	}
+	return input
+}
+
+func ValidateWebhookParts(engineMap map[string]map[string]*Model, input WebhookInput) error {
+	tablesMap, ok := engineMap[input.Database]
+	if !ok {
+		return fmt.Errorf("database %s doesn't exist", input.Database)
+	}
+
+	if _, ok := tablesMap[input.Table]; !ok {
+		return fmt.Errorf("table %s doesn't exist for database %s", input.Table, input.Database)
+	}
+
+	err := ValidateWebhookOperation(input)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateWebhookType(input)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateWebhookHeaders(input)
+	if err != nil {
+		return err
+	}
+
+	if input.ClearSecret && len(input.Secret) > 0 {
+		return fmt.Errorf("secret and clear_secret can't be provided together")
+	}
+
+	if input.ClearHeaders && len(input.Headers) > 0 {
+		return fmt.Errorf("headers and clear_headers can't be provided together")
+	}
+
+	return ValidateWebhookEndpoint(input)
+}
+
+func (engine *Engine) CreateWebhook(db *sql.DB, input WebhookInput) error {
+	input = FormatWebhookInput(input)
+	err := ValidateWebhookParts(engine.DatabaseToTableToModelMap, input)
+	if err != nil {
+		return err
+	}
+
+	headers, err := GetWebhookHeadersJson(input)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(CREATE_WEBHOOK,
+		input.Endpoint,
+		input.Database,
+		input.Table,
+		input.Operation,
+		input.Enabled,
+		input.RestEnabled,
+		input.GraphQLEnabled,
+		input.ForwardAuthHeaders,
+		input.Type,
+		input.Secret,
+		headers,
+	)
+	return err
+}
+
+func (engine *Engine) UpdateWebhookByID(db *sql.DB, input WebhookInput) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("webhook id was not provided")
+	}
+	input = FormatWebhookInput(input)
+	err := ValidateWebhookParts(engine.DatabaseToTableToModelMap, input)
+	if err != nil {
+		return err
+	}
+
+	headers, err := GetWebhookHeadersJson(input)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.Exec(UPDATE_WEBHOOK_BY_ID,
+		input.Endpoint,
+		input.Database,
+		input.Table,
+		input.Operation,
+		input.Enabled,
+		input.RestEnabled,
+		input.GraphQLEnabled,
+		input.ForwardAuthHeaders,
+		input.Type,
+		input.Id,
+		input.Secret,
+		headers,
+		input.ClearSecret,
+		input.ClearHeaders,
+	)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("webhook %d doesn't exist", input.Id))
+}
+
+func (engine *Engine) SetWebhookEnabledByID(db *sql.DB, input WebhookInput, enabled bool) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("webhook id was not provided")
+	}
+	result, err := db.Exec(UPDATE_WEBHOOK_ENABLED_BY_ID, enabled, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("webhook %d doesn't exist", input.Id))
+}
+
+func (engine *Engine) DeleteWebhookByID(db *sql.DB, input WebhookInput) error {
+	if input.Id <= 0 {
+		return fmt.Errorf("webhook id was not provided")
+	}
+	result, err := db.Exec(DELETE_WEBHOOK_BY_ID, input.Id)
+	if err != nil {
+		return err
+	}
+	return ExpectAffectedRows(result, fmt.Sprintf("webhook %d doesn't exist", input.Id))
+}
+
+func (engine *Engine) DeleteWebhooksByDatabase(db *sql.DB, input WebhookInput) error {
+	_, err := db.Exec(DELETE_WEBHOOKS_BY_DATABASE_NAME, input.Database)
+	return err
+}
+
+func (engine *Engine) DeleteWebhooksByDatabaseTable(db *sql.DB, input WebhookInput) error {
+	_, err := db.Exec(DELETE_WEBHOOKS_BY_DATABASE_TABLE_NAME, input.Database, input.Table)
+	return err
+}
+
+func (webhook Webhook) IsEnabledForChannel(channel string) bool {
+	if !webhook.Enabled {
+		return false
+	}
+	switch channel {
+	case RestDataTrigger:
+		return webhook.RestEnabled
+	case GraphQLDataTrigger:
+		return webhook.GraphQLEnabled
+	default:
+		return false
+	}
+}
+
+type WebhookNotFoundError struct {
+	Id int64
+}
+
+func (e *WebhookNotFoundError) Error() string {
+	return fmt.Sprintf("webhook %d doesn't exist", e.Id)
+}
+
+type WebhookDisabledError struct {
+	Id int64
+}
+
+func (e *WebhookDisabledError) Error() string {
+	return fmt.Sprintf("webhook %d is disabled", e.Id)
+}
+
+func (engine *Engine) GetWebhookByID(id int64) (Webhook, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	webhookId := strconv.FormatInt(id, 10)
+	for _, webhook := range engine.GetWebhooksList() {
+		if webhook.Id == webhookId {
+			return webhook, nil
+		}
+	}
+	return Webhook{}, &WebhookNotFoundError{Id: id}
+}
+
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
+
+func SetWebhookRequestHeaders(req *http.Request, webhook Webhook, body []byte) {
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(webhook.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WEBHOOK_TIMESTAMP_HEADER, timestamp)
+		req.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhookPayload(webhook.Secret, timestamp, body))
+	}
+}
+
 func (engine *Engine) GetDatabaseWebhooksMap(database string) (map[string]map[string]map[string][]Webhook, error) {
 	value, ok := engine.Webhooks[database]
 	if !ok {
@@ -131,52 +569,110 @@ func (engine *Engine) GetDatabaseTableOperationTypeWebhooks(database, table, ope
 
 }
 
-func (engine *Engine) ExecuteWebhook(webhook Webhook, input WebhookExecInput) {
-	if !webhook.Enabled {
-		return
-	}
-
-	payload := WebhookPayload{
-		Database: input.Database,
-		Table:    input.Table,
-		Data:     input.Payload,
+func (engine *Engine) ExecutePreExecWebhook(webhook Webhook, input WebhookExecInput) (any, error) {
+	payload := PreExecWebhookPayload{
+		Database:  input.Database,
+		Table:     input.Table,
+		Operation: input.Operation,
+		Type:      PRE_EXEC,
+		Data:      input.Payload,
+		Auth:      input.Claims,
 	}
 
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
 	req, err := http.NewRequest("POST", webhook.Endpoint, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if webhook.ForwardAuthHeaders {
+	SetWebhookRequestHeaders(req, webhook, jsonBody)
+	if webhook.ForwardAuthHeaders && len(input.Auth) > 0 {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", input.Auth))
 	}
 
-	client := http.Client{}
+	client := http.Client{Timeout: GetPreExecWebhookTimeout()}
 	res, err := client.Do(req)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, &WebhookRejectionError{
+			StatusCode: http.StatusBadGateway,
+			Message:    fmt.Sprintf("pre execution webhook for table %s failed", input.Table),
+		}
 	}
 	defer res.Body.Close()
 
-}
-
-func (engine *Engine) ExecuteWebhooks(input WebhookExecInput) {
-	webhooks, err := engine.GetDatabaseTableOperationTypeWebhooks(input.Database, input.Table, input.Operation, input.Type)
+	responseBody, err := io.ReadAll(res.Body)
 	if err != nil {
-		fmt.Println(err.Error())
-		return
+		return nil, &WebhookRejectionError{
+			StatusCode: http.StatusBadGateway,
+			Message:    fmt.Sprintf("pre execution webhook for table %s failed", input.Table),
+		}
+	}
+
+	var response PreExecWebhookResponse
+	parseErr := json.Unmarshal(responseBody, &response)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message := response.Message
+		if parseErr != nil || len(message) == 0 {
+			message = strings.Trim(string(responseBody), " \n")
+		}
+		if len(message) == 0 {
+			message = http.StatusText(res.StatusCode)
+		}
+		return nil, &WebhookRejectionError{
+			StatusCode: res.StatusCode,
+			Message:    message,
+		}
+	}
+
+	if len(bytes.TrimSpace(responseBody)) == 0 {
+		return input.Payload, nil
+	}
+
+	if parseErr != nil {
+		return nil, &WebhookRejectionError{
+			StatusCode: http.StatusBadGateway,
+			Message:    fmt.Sprintf("pre execution webhook for table %s returned an invalid response", input.Table),
+		}
+	}
+
+	if response.Data == nil {
+		return input.Payload, nil
 	}
-	for _, webhook := range webhooks {
 
-		go engine.ExecuteWebhook(webhook, input)
+	return response.Data, nil
+}
+
+func (engine *Engine) ExecutePreExecWebhooks(auth jwt.MapClaims, role string, database string, operation string, channel string, args map[string]interface{}) (map[string]interface{}, error) {
+	for key, payload := range args {
+		webhooks, err := engine.GetDatabaseTableOperationTypeWebhooks(database, key, operation, PRE_EXEC)
+		if err != nil {
+			continue
+		}
+		for _, webhook := range webhooks {
+			if !webhook.IsEnabledForChannel(channel) {
+				continue
+			}
+			webhookInput := WebhookExecInput{
+				Database:  database,
+				Table:     key,
+				Operation: operation,
+				Type:      PRE_EXEC,
+				Channel:   channel,
+				Payload:   payload,
+				Auth:      role,
+				Claims:    auth,
+			}
+			payload, err = engine.ExecutePreExecWebhook(webhook, webhookInput)
+			if err != nil {
+				return nil, err
+			}
+		}
+		args[key] = payload
 	}
+	return args, nil
 }
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateWebhookParts(t *testing.T) {
+	engineMap := map[string]map[string]*Model{"public": {"users": NewModel("public", "users")}}
+	valid := WebhookInput{Database: "public", Table: "users", Operation: INSERT_OPERATION, Type: POST_EXEC, Endpoint: "https://example.com/hook"}
+	cases := []struct {
+		name    string
+		update  func(input *WebhookInput)
+		wantErr string
+	}{
+		{name: "valid", update: func(input *WebhookInput) {}},
+		{name: "clear secret", update: func(input *WebhookInput) { input.ClearSecret = true }},
+		{name: "clear headers", update: func(input *WebhookInput) { input.ClearHeaders = true }},
+		{
+			name: "secret and clear secret",
+			update: func(input *WebhookInput) {
+				input.Secret = "s"
+				input.ClearSecret = true
+			},
+			wantErr: "secret and clear_secret can't be provided together",
+		},
+		{
+			name: "headers and clear headers",
+			update: func(input *WebhookInput) {
+				input.Headers = map[string]string{"X-Api-Key": "key"}
+				input.ClearHeaders = true
+			},
+			wantErr: "headers and clear_headers can't be provided together",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			input := valid
+			c.update(&input)
+			err := ValidateWebhookParts(engineMap, input)
+			if len(c.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != c.wantErr {
+				t.Fatalf("expected error %q, got %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDeliverWebhookChecksEnabled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+	}))
+	defer server.Close()
+
+	webhook := Webhook{Id: "7", Endpoint: server.URL, Database: "public", Table: "users", Operation: INSERT_OPERATION, Type: POST_EXEC}
+	engine := &Engine{
+		Webhooks:          map[string]map[string]map[string]map[string][]Webhook{"public": {"users": {INSERT_OPERATION: {POST_EXEC: {webhook}}}}},
+		WebhookDispatcher: NewWebhookDispatcher(),
+	}
+	delivery := WebhookDelivery{Id: 1, WebhookId: 7, Endpoint: server.URL, body: []byte(`{}`)}
+
+	_, err := engine.DeliverWebhook(delivery)
+	var disabled *WebhookDisabledError
+	if !errors.As(err, &disabled) {
+		t.Fatalf("expected a disabled webhook error, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no request for a disabled webhook, got %d", requests)
+	}
+
+	engine.Webhooks["public"]["users"][INSERT_OPERATION][POST_EXEC][0].Enabled = true
+	statusCode, err := engine.DeliverWebhook(delivery)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if statusCode != http.StatusOK || requests != 1 {
+		t.Errorf("expected one delivered request, got status %d and %d requests", statusCode, requests)
+	}
+}
@@ -0,0 +1,247 @@
+package engine
+
+import (
+	"application/database"
+	"application/environment"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+var GRAPHQL_TRANSPORT_WS_PROTOCOL string = "graphql-transport-ws"
+
+var GQL_CONNECTION_INIT string = "connection_init"
+var GQL_CONNECTION_ACK string = "connection_ack"
+var GQL_PING string = "ping"
+var GQL_PONG string = "pong"
+var GQL_SUBSCRIBE string = "subscribe"
+var GQL_NEXT string = "next"
+var GQL_ERROR string = "error"
+var GQL_COMPLETE string = "complete"
+
+var GQL_CLOSE_BAD_REQUEST int = 4400
+var GQL_CLOSE_UNAUTHORIZED int = 4401
+var GQL_CLOSE_INIT_TIMEOUT int = 4408
+var GQL_CLOSE_SUBSCRIBER_EXISTS int = 4409
+var GQL_CLOSE_TOO_MANY_INIT_REQUESTS int = 4429
+
+type GraphqlTransportMessage struct {
+	Id      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type GraphqlTransportResponse struct {
+	Id      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Payload any    `json:"payload,omitempty"`
+}
+
+type GraphqlTransportError struct {
+	Message string `json:"message"`
+}
+
+func (client *Client) SubscribeGraphql(subscription database.GraphqlSubscription) bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if _, ok := client.GraphqlSubscriptions[subscription.Id]; ok {
+		return false
+	}
+	client.GraphqlSubscriptions[subscription.Id] = subscription
+	return true
+}
+
+func (client *Client) UnsubscribeGraphql(subscriptionId string) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	delete(client.GraphqlSubscriptions, subscriptionId)
+}
+
+func (client *Client) HasGraphqlSubscription(subscriptionId string) bool {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	_, ok := client.GraphqlSubscriptions[subscriptionId]
+	return ok
+}
+
+func (client *Client) GetGraphqlSubscriptions() []database.GraphqlSubscription {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	subscriptions := make([]database.GraphqlSubscription, 0, len(client.GraphqlSubscriptions))
+	for _, subscription := range client.GraphqlSubscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions
+}
+
+func CloseGraphqlConnection(client *Client, code int, reason string) {
+	client.writeMutex.Lock()
+	defer client.writeMutex.Unlock()
+	message := websocket.FormatCloseMessage(code, reason)
+	err := client.Conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func SendGraphqlError(client *Client, id string, err error) error {
+	return SendMessage(client, GraphqlTransportResponse{
+		Id:      id,
+		Type:    GQL_ERROR,
+		Payload: []GraphqlTransportError{{Message: err.Error()}},
+	})
+}
+
+func (r *Router) ResolveGraphqlSubscription(client *Client, subscription database.GraphqlSubscription) error {
+	result, err := r.Engine.ResolveGraphqlSubscription(subscription, client.Auth, uuid.New().String(), r.DB)
+	if err != nil {
+		client.UnsubscribeGraphql(subscription.Id)
+		return SendGraphqlError(client, subscription.Id, err)
+	}
+	return SendMessage(client, GraphqlTransportResponse{Id: subscription.Id, Type: GQL_NEXT, Payload: result})
+}
+
+func (r *Router) SendGraphqlSubscriptionEvents(client *Client, input database.DataTriggerInput) error {
+	client.resolveMutex.Lock()
+	defer client.resolveMutex.Unlock()
+	for _, subscription := range client.GetGraphqlSubscriptions() {
+		if !subscription.IsAffectedBy(input) {
+			continue
+		}
+		if !client.HasGraphqlSubscription(subscription.Id) {
+			continue
+		}
+		err := r.ResolveGraphqlSubscription(client, subscription)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Router) HandleGraphqlSubscribe(client *Client, message GraphqlTransportMessage) error {
+	var input database.GraphQLRequestInput
+	err := json.Unmarshal(message.Payload, &input)
+	if err != nil {
+		CloseGraphqlConnection(client, GQL_CLOSE_BAD_REQUEST, "invalid subscribe payload")
+		return err
+	}
+
+	subscription, err := r.Engine.NewGraphqlSubscription(message.Id, input, client.Auth)
+	if err != nil {
+		return SendGraphqlError(client, message.Id, err)
+	}
+
+	if subscription.Operation != database.GRAPHQL_SUBSCRIPTION_OPERATION {
+		err = r.ResolveGraphqlSubscription(client, subscription)
+		if err != nil {
+			return err
+		}
+		return SendMessage(client, GraphqlTransportResponse{Id: subscription.Id, Type: GQL_COMPLETE})
+	}
+
+	client.resolveMutex.Lock()
+	defer client.resolveMutex.Unlock()
+	if !client.SubscribeGraphql(subscription) {
+		CloseGraphqlConnection(client, GQL_CLOSE_SUBSCRIBER_EXISTS, fmt.Sprintf("Subscriber for %s already exists", subscription.Id))
+		return fmt.Errorf("subscriber for %s already exists", subscription.Id)
+	}
+	return r.ResolveGraphqlSubscription(client, subscription)
+}
+
+func IsGraphqlTransportRequest(req *http.Request) bool {
+	for _, protocol := range websocket.Subprotocols(req) {
+		if protocol == GRAPHQL_TRANSPORT_WS_PROTOCOL {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Router) AuthenticateGraphqlConnection(client *Client, payload json.RawMessage) error {
+	if !database.ShouldAuthenticate() {
+		return nil
+	}
+	tokenString := database.GetConnectionInitToken(payload)
+	if len(tokenString) == 0 {
+		if client.Auth == nil {
+			return fmt.Errorf("no token was provided")
+		}
+		return nil
+	}
+	auth, err := database.ParseToken(tokenString)
+	if err != nil {
+		return err
+	}
+	client.resolveMutex.Lock()
+	defer client.resolveMutex.Unlock()
+	client.Auth = auth
+	return nil
+}
+
+func (r *Router) GraphqlWSHandler(client *Client) {
+	initTimeout := environment.GetEnvValueToIntWithDefault("GRAPHQL_WS_CONNECTION_INIT_TIMEOUT_IN_SECONDS", 10)
+	client.Conn.SetReadDeadline(time.Now().Add(time.Duration(initTimeout) * time.Second))
+	initialized := false
+	for {
+		messageType, msg, err := client.Conn.ReadMessage()
+		if err != nil {
+			if !initialized {
+				CloseGraphqlConnection(client, GQL_CLOSE_INIT_TIMEOUT, "Connection initialisation timeout")
+			}
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				fmt.Println(err)
+			}
+			return
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		var message GraphqlTransportMessage
+		err = json.Unmarshal(msg, &message)
+		if err != nil {
+			CloseGraphqlConnection(client, GQL_CLOSE_BAD_REQUEST, "Invalid message received")
+			return
+		}
+
+		switch message.Type {
+		case GQL_CONNECTION_INIT:
+			if initialized {
+				CloseGraphqlConnection(client, GQL_CLOSE_TOO_MANY_INIT_REQUESTS, "Too many initialisation requests")
+				return
+			}
+			if err := r.AuthenticateGraphqlConnection(client, message.Payload); err != nil {
+				CloseGraphqlConnection(client, GQL_CLOSE_UNAUTHORIZED, "Unauthorized")
+				return
+			}
+			initialized = true
+			client.Conn.SetReadDeadline(time.Time{})
+			err = SendMessage(client, GraphqlTransportResponse{Type: GQL_CONNECTION_ACK})
+		case GQL_PING:
+			err = SendMessage(client, GraphqlTransportResponse{Type: GQL_PONG})
+		case GQL_PONG:
+		case GQL_SUBSCRIBE:
+			if !initialized {
+				CloseGraphqlConnection(client, GQL_CLOSE_UNAUTHORIZED, "Unauthorized")
+				return
+			}
+			err = r.HandleGraphqlSubscribe(client, message)
+		case GQL_COMPLETE:
+			client.UnsubscribeGraphql(message.Id)
+		default:
+			CloseGraphqlConnection(client, GQL_CLOSE_BAD_REQUEST, fmt.Sprintf("Invalid message type %s", message.Type))
+			return
+		}
+
+		if err != nil {
+			log.Println(err)
+			return
+		}
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -77,6 +80,7 @@ type Router struct {
 	EnableSQLLogging  bool
 	DB                *sql.DB
 	Rooms             map[*Client]bool
+	roomsMutex        sync.RWMutex
 }
 
 func (r *Router) Status(res http.ResponseWriter, statusCode int) *Router {
@@ -119,6 +123,10 @@ func (r *Router) ErrorResponse(res http.ResponseWriter, status int, errorText st
 	r.Json(res, status, map[string]string{"message": errorText})
 }
 
+func (r *Router) ErrorResponseFromError(res http.ResponseWriter, defaultStatus int, err error) {
+	r.ErrorResponse(res, database.GetErrorStatusCode(err, defaultStatus), err.Error())
+}
+
 func (r *Router) NotFound(res http.ResponseWriter, req *http.Request) {
 	r.Json(res, 404, map[string]string{"message": "NOT_FOUND"})
 }
@@ -377,6 +385,15 @@ func GetAuth(req *http.Request) jwt.MapClaims {
 	return auth
 }
 
+func GetRequestId(req *http.Request) string {
+	requestId, ok := req.Context().Value(RequestContextKey("requestId")).(string)
+	if !ok {
+		return ""
+	}
+
+	return requestId
+}
+
 func GetParams(req *http.Request) map[string]string {
 	params := req.Context().Value(RequestContextKey("params"))
 	if params == nil {
@@ -466,25 +483,13 @@ func (r *Router) GetHttpHandler(url string, method HttpVerb) (http.HandlerFunc,
 	matchedUrl, params := r.MatchRoute(url, method, r.urlKeys[method])
 	route = r.routes[method][matchedUrl]
 	if route == nil {
-		// strMethod, ok := r.httpVerbToStrMap[method]
-		// if ok {
-		// 	customRestHandlersMap, ok := r.Engine.RestHandlersMap[strMethod]
-		// 	if ok {
-		// 		if strings.HasPrefix(url, r.BaseUrl) {
-		// 			url = "/" + strings.TrimLeft(url, r.BaseUrl)
-		// 		}
-		// 		input, ok := customRestHandlersMap[url]
-		// 		if ok {
-		// 			return r.HandleCustomRestHandler(input), []func(res http.ResponseWriter, req *http.Request, next func(req *http.Request)){}
-		// 		}
-		// 		if strings.HasSuffix(url, "/") {
-		// 			input, ok := customRestHandlersMap[strings.TrimRight(url, "/")]
-		// 			if ok {
-		// 				return r.HandleCustomRestHandler(input), []func(res http.ResponseWriter, req *http.Request, next func(req *http.Request)){}
-		// 			}
-		// 		}
-		// 	}
-		// }
+		strMethod, ok := r.httpVerbToStrMap[method]
+		if ok {
+			input, params, ok := r.Engine.MatchRestHandler(strMethod, strings.TrimPrefix(url, r.BaseUrl))
+			if ok {
+				return HandlerWithContext(r.HandleCustomRestHandler(input), "params", params), []func(res http.ResponseWriter, req *http.Request, next func(req *http.Request)){}
+			}
+		}
 		return r.NotFound, []func(res http.ResponseWriter, req *http.Request, next func(req *http.Request)){}
 	}
 	handler := route.handler
@@ -625,85 +630,73 @@ func (r *Router) Subscribe(eventName string, listener func(args ...any)) func()
 }
 
 func (r *Router) RemoveAllLEventEmitteristeners() {
-	for key := range r.Engine.EventEmitter.EventMap {
-		delete(r.Engine.EventEmitter.EventMap, key)
-	}
+	r.Engine.EventEmitter.RemoveAll()
 }
 
 func (r *Router) EmitEvent(eventName string, args ...any) {
 	r.Engine.EventEmitter.Emit(eventName, args...)
 }
 
-// NOT IN USE
+func GetCustomRestHandlerParams(req *http.Request, method string) (map[string]any, error) {
+	params := make(map[string]any)
+	for key, values := range req.URL.Query() {
+		if len(values) == 1 {
+			params[key] = values[0]
+		} else {
+			params[key] = values
+		}
+	}
+
+	if method != "GET" && req.ContentLength != 0 {
+		body := make(map[string]any)
+		err := json.NewDecoder(req.Body).Decode(&body)
+		defer req.Body.Close()
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("request body should be a json object")
+		}
+		for key, value := range body {
+			params[key] = value
+		}
+	}
+
+	for key, value := range GetParams(req) {
+		params[key] = value
+	}
+	return params, nil
+}
+
 func (r *Router) HandleCustomRestHandler(input database.CustomRestHandlerInput) http.HandlerFunc {
 	if !input.Enabled {
 		return r.NotFound
 	}
 
-	if input.Auth && environment.GetEnvValue("DISABLE_AUTH") != "ON" {
-		return func(res http.ResponseWriter, req *http.Request) {
-			_, err := r.Engine.AuthenticateForDatabase(req, input.Database)
+	return func(res http.ResponseWriter, req *http.Request) {
+		if input.Auth && !r.Engine.AuthDisabled {
+			enhancedReq, err := r.Engine.AuthenticateForDatabase(req, input.Database)
 			if err != nil {
 				r.ErrorResponse(res, http.StatusUnauthorized, err.Error())
 				return
 			}
-
-			body := GetBody(req)
-			var args any
-			if parsed, ok := body["args"]; ok {
-				args = parsed
-			}
-			scanner := database.Query(r.DB, input.Query, args)
-			var result []any = make([]any, 0)
-			cb := func(rows *sql.Rows) error {
-				var row any
-				err := rows.Scan(&row)
-				if err != nil {
-					return err
-				}
-				result = append(result, row)
-				return err
-			}
-			err = scanner(cb)
-			if err != nil {
-				r.ErrorResponse(res, http.StatusInternalServerError, err.Error())
-				return
-			}
-
-			r.Json(res, http.StatusAccepted, result)
+			req = enhancedReq
 		}
-	} else {
-		return func(res http.ResponseWriter, req *http.Request) {
 
-			body := GetBody(req)
-			var args []any = make([]any, 0)
-			if parsed, ok := body["args"]; ok && input.Method != "GET" {
-				parsedArgs, err := database.IsArray(parsed)
-
-				if err == nil {
-					args = append(args, parsedArgs...)
-				}
-			}
-			scanner := database.Query(r.DB, input.Query, args...)
-
-			var result []struct{} = make([]struct{}, 0)
-			cb := func(rows *sql.Rows) error {
-				var row struct{}
-				err := rows.Scan(&row)
-				if err != nil {
-					return err
-				}
-				result = append(result, row)
-				return nil
-			}
-			err := scanner(cb)
-			if err != nil {
-				r.ErrorResponse(res, http.StatusInternalServerError, err.Error())
-				return
-			}
+		params, err := GetCustomRestHandlerParams(req, input.Method)
+		if err != nil {
+			r.ErrorResponse(res, http.StatusBadRequest, err.Error())
+			return
+		}
 
-			r.Json(res, http.StatusAccepted, result)
+		result, err := r.Engine.ExecuteRestHandler(r.DB, GetAuth(req), input, params)
+		var validationError *database.CustomRestHandlerValidationError
+		if errors.As(err, &validationError) {
+			r.Json(res, http.StatusBadRequest, map[string]any{"message": "invalid parameters", "errors": validationError.Errors})
+			return
+		}
+		if err != nil {
+			r.ErrorResponseFromError(res, http.StatusInternalServerError, err)
+			return
 		}
-	}
 
+		r.Json(res, http.StatusOK, result)
+	}
 }
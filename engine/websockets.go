@@ -1,9 +1,12 @@
 package engine
 
 import (
+	"application/database"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,9 +15,14 @@ import (
 )
 
 type Client struct {
-	Id   string `json:"id"`
-	Auth jwt.MapClaims
-	Conn *websocket.Conn
+	Id                   string `json:"id"`
+	Auth                 jwt.MapClaims
+	Conn                 *websocket.Conn
+	Subscriptions        map[string]database.Subscription
+	GraphqlSubscriptions map[string]database.GraphqlSubscription
+	mutex                sync.RWMutex
+	writeMutex           sync.Mutex
+	resolveMutex         sync.Mutex
 }
 
 type Room struct {
@@ -29,24 +37,120 @@ type Message struct {
 	Data  any    `json:data"`
 }
 
+type SubscriptionRequest struct {
+	Type string `json:"type"`
+	database.Subscription
+}
+
+type SubscriptionMessage struct {
+	Type    string `json:"type"`
+	Id      string `json:"id,omitempty"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+var SUBSCRIBE_MESSAGE string = "subscribe"
+var UNSUBSCRIBE_MESSAGE string = "unsubscribe"
+var SUBSCRIBED_MESSAGE string = "subscribed"
+var UNSUBSCRIBED_MESSAGE string = "unsubscribed"
+var EVENT_MESSAGE string = "event"
+var ERROR_MESSAGE string = "error"
+
 func (r *Router) NewClient(conn *websocket.Conn, auth jwt.MapClaims) *Client {
 	clientId := uuid.New().String()
-	client := &Client{Conn: conn, Id: clientId, Auth: auth}
+	client := &Client{
+		Conn:                 conn,
+		Id:                   clientId,
+		Auth:                 auth,
+		Subscriptions:        make(map[string]database.Subscription),
+		GraphqlSubscriptions: make(map[string]database.GraphqlSubscription),
+	}
+	r.roomsMutex.Lock()
 	r.Rooms[client] = true
+	r.roomsMutex.Unlock()
 	return client
 }
 
+func (r *Router) RemoveClient(client *Client) {
+	r.roomsMutex.Lock()
+	delete(r.Rooms, client)
+	r.roomsMutex.Unlock()
+}
+
+func (r *Router) GetClients() []*Client {
+	r.roomsMutex.RLock()
+	defer r.roomsMutex.RUnlock()
+	clients := make([]*Client, 0, len(r.Rooms))
+	for client := range r.Rooms {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+func (client *Client) Subscribe(subscription database.Subscription) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.Subscriptions[subscription.Id] = subscription
+}
+
+func (client *Client) Unsubscribe(subscriptionId string) bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	_, ok := client.Subscriptions[subscriptionId]
+	delete(client.Subscriptions, subscriptionId)
+	return ok
+}
+
+func (client *Client) GetSubscriptions() []database.Subscription {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	subscriptions := make([]database.Subscription, 0, len(client.Subscriptions))
+	for _, subscription := range client.Subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions
+}
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:   1024,
 	WriteBufferSize:  1024,
 	HandshakeTimeout: 5 * time.Second,
+	Subprotocols:     []string{GRAPHQL_TRANSPORT_WS_PROTOCOL},
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
 }
 
-func SendMessage(client *Client, data any) {
+func SendMessage(client *Client, data any) error {
+	client.writeMutex.Lock()
+	defer client.writeMutex.Unlock()
+	return client.Conn.WriteJSON(data)
+}
 
+func (r *Router) HandleSubscriptionMessage(client *Client, msg []byte) SubscriptionMessage {
+	var request SubscriptionRequest
+	err := json.Unmarshal(msg, &request)
+	if err != nil {
+		return SubscriptionMessage{Type: ERROR_MESSAGE, Message: "invalid message"}
+	}
+
+	switch request.Type {
+	case SUBSCRIBE_MESSAGE:
+		subscription := database.FormatSubscription(request.Subscription)
+		err := r.Engine.ValidateSubscription(client.Auth, subscription)
+		if err != nil {
+			return SubscriptionMessage{Type: ERROR_MESSAGE, Id: request.Id, Message: err.Error()}
+		}
+		client.Subscribe(subscription)
+		return SubscriptionMessage{Type: SUBSCRIBED_MESSAGE, Id: subscription.Id}
+	case UNSUBSCRIBE_MESSAGE:
+		if !client.Unsubscribe(request.Id) {
+			return SubscriptionMessage{Type: ERROR_MESSAGE, Id: request.Id, Message: fmt.Sprintf("subscription %s doesn't exist", request.Id)}
+		}
+		return SubscriptionMessage{Type: UNSUBSCRIBED_MESSAGE, Id: request.Id}
+	default:
+		return SubscriptionMessage{Type: ERROR_MESSAGE, Id: request.Id, Message: fmt.Sprintf("not supported message type %s", request.Type)}
+	}
 }
 
 func (r *Router) WSHandler(res http.ResponseWriter, req *http.Request) {
@@ -57,11 +161,12 @@ func (r *Router) WSHandler(res http.ResponseWriter, req *http.Request) {
 	}
 	auth := GetAuth(req)
 	client := r.NewClient(conn, auth)
+	defer r.RemoveClient(client)
 	defer client.Conn.Close()
-	defer func() {
-		delete(r.Rooms, client)
-	}()
-	defer client.Conn.Close()
+	if conn.Subprotocol() == GRAPHQL_TRANSPORT_WS_PROTOCOL {
+		r.GraphqlWSHandler(client)
+		return
+	}
 	for {
 		messageType, msg, err := client.Conn.ReadMessage()
 		if messageType == websocket.CloseMessage {
@@ -77,6 +182,14 @@ func (r *Router) WSHandler(res http.ResponseWriter, req *http.Request) {
 			break
 		}
 
-		fmt.Println(msg, messageType)
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		err = SendMessage(client, r.HandleSubscriptionMessage(client, msg))
+		if err != nil {
+			fmt.Println(err)
+			break
+		}
 	}
 }